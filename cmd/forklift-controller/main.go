@@ -19,6 +19,8 @@ package main
 import (
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -83,6 +85,10 @@ func main() {
 	log.Info("setting up manager")
 	mgr, err := manager.New(cfg, manager.Options{
 		Metrics: server.Options{BindAddress: Settings.Metrics.Address()},
+		// The replicas sharing the inventory DB
+		// elect the leader running the controllers.
+		LeaderElection:   Settings.Inventory.Database != "",
+		LeaderElectionID: leaderElectionID(),
 	})
 	if err != nil {
 		log.Error(err, "unable to set up overall controller manager")
@@ -138,6 +144,17 @@ func main() {
 	}
 }
 
+// Leader election (lease) ID.
+// The deployments running other roles elect their own leader.
+func leaderElectionID() string {
+	id := []string{"forklift"}
+	for role := range Settings.Role.Roles {
+		id = append(id, role)
+	}
+	sort.Strings(id[1:])
+	return strings.Join(id, "-")
+}
+
 // Build and start profiler.
 func profiler() (profiler interface{ Stop() }) {
 	var kind func(*profile.Profile)
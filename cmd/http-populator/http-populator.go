@@ -0,0 +1,607 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/relay"
+	"github.com/konveyor/forklift-controller/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Converts the image into the volume.
+	imageConverter = "/usr/local/bin/image-converter"
+	// CA certificate passed to nbdkit.
+	caFile = "/tmp/ca.pem"
+	// Rate read by the nbdkit rate filter.
+	rateFile = "/tmp/rate"
+)
+
+// Progress printed by the image-converter.
+var conversionProgress = regexp.MustCompile(`Progress: (\d+)%`)
+
+type AppConfig struct {
+	url          string
+	format       string
+	checksum     string
+	volumePath   string
+	crName       string
+	crNamespace  string
+	secretName   string
+	ownerUID     string
+	pvcSize      int64
+	resumeOffset int64
+	resumeState  string
+	// Transfer relay.
+	relayURL         string
+	relayCompression string
+	// Transfer rate limit.
+	rateLimit *ratelimit.Schedule
+}
+
+// Settings read from the secret.
+type sourceConfig struct {
+	s3       *s3Credentials
+	cacert   string
+	insecure bool
+}
+
+func main() {
+	config := &AppConfig{rateLimit: &ratelimit.Schedule{}}
+	flag.StringVar(&config.url, "url", "", "URL of the disk image (https://images.example.com/disk.qcow2)")
+	flag.StringVar(&config.format, "format", "raw", "Format of the disk image")
+	flag.StringVar(&config.checksum, "checksum", "", "Checksum of the disk image (sha256:<hex digest>)")
+	flag.StringVar(&config.volumePath, "volume-path", "", "Volume path to populate")
+	flag.StringVar(&config.secretName, "secret-name", "", "Name of secret containing S3 credentials")
+	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
+	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
+	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Int64Var(&config.resumeOffset, "resume-offset", 0, "Offset (in bytes) to resume an interrupted transfer from")
+	flag.StringVar(&config.resumeState, "resume-state", "", "State of the hashes at the resume offset")
+	flag.StringVar(&config.relayURL, "relay-url", "", "URL of the transfer relay (https://relay.example.com:8443)")
+	flag.StringVar(&config.relayCompression, "relay-compression", "zstd", "Compression of the relayed stream (zstd, none)")
+	flag.Int64Var(&config.rateLimit.Default, "rate-limit", 0, "Transfer rate limit (bytes per second, 0 is not limited)")
+	flag.Var(config.rateLimit, "rate-window", "Transfer rate limit during a UTC time window ([Mon,...]/HH:MM-HH:MM=<bytes per second>)")
+	flag.Parse()
+
+	if config.pvcSize <= 0 {
+		klog.Fatal("pvc-size must be greater than 0")
+	}
+	if err := validateURL(config.url); err != nil {
+		klog.Fatal(err)
+	}
+
+	certsDirectory, err := os.MkdirTemp("", "certsdir")
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	metrics.StartPrometheusEndpoint(certsDirectory)
+
+	checksum, err := populate(config, loadSourceConfig())
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if checksum != nil {
+		if err = checksum.Report(checkpoint.TerminationLog); err != nil {
+			klog.Warning("Failed to report the checksum: ", err)
+		}
+	}
+}
+
+// Populate the volume. Returns the checksum of raw images, the
+// checksum of converted images would not match the volume content.
+func populate(config *AppConfig, source *sourceConfig) (checksum *checkpoint.Checksum, err error) {
+	imageURL := config.url
+	if source.s3 != nil {
+		imageURL, err = presign(imageURL, source.s3, time.Now(), presignExpiry)
+		if err != nil {
+			return
+		}
+	}
+	client, err := createHTTPClient(source)
+	if err != nil {
+		return
+	}
+	if config.relayURL != "" {
+		// The relay verifies the server certificate.
+		var transport *relay.Transport
+		transport, err = relay.NewTransport(config.relayURL, config.relayCompression != relay.CompressionNone, relay.CertDir)
+		if err != nil {
+			return
+		}
+		klog.Info("Transferring through the relay: ", config.relayURL)
+		client.Transport = transport
+	}
+	progress := createProgressCounter()
+	if config.format == "" || config.format == "raw" {
+		checkpoints := createCheckpointGauge()
+		checksum, err = transfer(client, imageURL, config, config.resumeOffset, progress, checkpoints)
+		if errors.Is(err, checkpoint.ErrChecksum) && config.resumeOffset > 0 {
+			klog.Warning("Resumed transfer failed verification, restarting from the beginning: ", err)
+			checksum, err = transfer(client, imageURL, config, 0, progress, checkpoints)
+		}
+		return
+	}
+	if streamable(config, source) {
+		err = stream(imageURL, config, source, progress)
+		return
+	}
+	err = convertImage(client, imageURL, config, progress)
+	return
+}
+
+// The image can be converted while it is read through nbdkit,
+// without a scratch file. The checksum of the image, the relay
+// and the presigned (GET) S3 requests require reading the image
+// (once) with the populator.
+func streamable(config *AppConfig, source *sourceConfig) bool {
+	return config.checksum == "" && config.relayURL == "" && source.s3 == nil
+}
+
+// Only HTTP(S) images are supported.
+func validateURL(rawURL string) (err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		err = fmt.Errorf("unsupported image URL %q, expected an http(s) URL", rawURL)
+	}
+	return
+}
+
+// Download the raw image into the volume starting at the offset.
+// Returns the checksum of the image, computed with the algorithm
+// of the expected checksum or sha256 when there is none.
+func transfer(client *http.Client, imageURL string, config *AppConfig, offset int64, progress *prometheus.CounterVec, checkpoints *prometheus.GaugeVec) (checksum *checkpoint.Checksum, err error) {
+	h, algorithm, expected, err := parseChecksum(config.checksum)
+	if err != nil {
+		return
+	}
+	if h == nil {
+		h, algorithm = sha256.New(), "sha256"
+	}
+	writer, err := checkpoint.Open(config.volumePath, offset, config.resumeState, h)
+	if err != nil {
+		return
+	}
+	writer.Sparse = true
+	writer.Checkpoint = func(offset int64, state string) {
+		checkpoints.Reset()
+		checkpoints.WithLabelValues(config.ownerUID, state).Set(float64(offset))
+	}
+	if writer.Offset() > 0 {
+		klog.Info("Resuming the transfer at offset: ", writer.Offset())
+	}
+
+	reader, err := download(client, imageURL, writer.Offset())
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	defer reader.Close()
+
+	countingReader := &CountingReader{reader: ratelimit.NewReader(reader, config.rateLimit)}
+	countingReader.read.Store(writer.Offset())
+	done := make(chan bool)
+	go monitorProgress(countingReader, progress, config.ownerUID, config.pvcSize, done)
+
+	_, err = io.Copy(writer, countingReader)
+	done <- err == nil
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+	klog.Info("Skipped writing zero bytes: ", writer.Skipped())
+	if expected != "" {
+		err = writer.Verify(expected)
+		if err != nil {
+			return
+		}
+		klog.Info("Verified the image checksum.")
+	}
+	checksum = &checkpoint.Checksum{
+		Algorithm: algorithm,
+		Digest:    hex.EncodeToString(writer.Sum()),
+		Size:      writer.Offset(),
+	}
+	return
+}
+
+// Download the image starting at the offset.
+// The data before the offset is skipped when the
+// server does not support range requests.
+func download(client *http.Client, imageURL string, offset int64) (data io.ReadCloser, err error) {
+	request, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		if offset > 0 {
+			klog.Info("Range requests not supported, skipping the transferred data.")
+			if _, err = io.CopyN(io.Discard, response.Body, offset); err != nil {
+				response.Body.Close()
+				return
+			}
+		}
+	default:
+		response.Body.Close()
+		err = fmt.Errorf("failed to download the image: %s", response.Status)
+		return
+	}
+	data = response.Body
+	return
+}
+
+// Download the image once into a scratch file (limited to
+// the size of the volume) and convert it into the volume. The checksum is verified on the data
+// downloaded, which is the data converted. The download is
+// the first half of the progress and the conversion the other.
+func convertImage(client *http.Client, imageURL string, config *AppConfig, progress *prometheus.CounterVec) (err error) {
+	scratch, err := fetch(client, imageURL, config, progress)
+	if scratch != "" {
+		defer os.Remove(scratch)
+	}
+	if err != nil {
+		return
+	}
+	err = convert(scratch, config, progress)
+	if err != nil {
+		return
+	}
+	finalizeProgress(progress, config.ownerUID)
+	return
+}
+
+// Download the image into a scratch file (in TMPDIR) and
+// verify the checksum of the data downloaded.
+// Returns the path of the scratch file.
+func fetch(client *http.Client, imageURL string, config *AppConfig, progress *prometheus.CounterVec) (path string, err error) {
+	h, _, expected, err := parseChecksum(config.checksum)
+	if err != nil {
+		return
+	}
+	file, err := os.CreateTemp("", "image-*")
+	if err != nil {
+		return
+	}
+	path = file.Name()
+	defer file.Close()
+	reader, err := download(client, imageURL, 0)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	countingReader := &CountingReader{reader: ratelimit.NewReader(reader, config.rateLimit)}
+	var source io.Reader = countingReader
+	if h != nil {
+		source = io.TeeReader(countingReader, h)
+	}
+	done := make(chan bool)
+	go monitorProgress(countingReader, progress, config.ownerUID, 2*config.pvcSize, done)
+	klog.Info("Downloading the ", config.format, " image.")
+	_, err = io.Copy(file, source)
+	// The progress is finalized after the conversion.
+	done <- false
+	if err != nil {
+		return
+	}
+	err = file.Sync()
+	if err != nil {
+		return
+	}
+	if h != nil {
+		actual := hex.EncodeToString(h.Sum(nil))
+		if actual != expected {
+			err = fmt.Errorf("%w: expected %s, got %s", checkpoint.ErrChecksum, expected, actual)
+			return
+		}
+		klog.Info("Verified the image checksum.")
+	}
+	return
+}
+
+// Convert the image with the image-converter, reading it through
+// the nbdkit curl plugin which only fetches the ranges qemu-img
+// needs. The raw image is written directly into the volume.
+func stream(imageURL string, config *AppConfig, source *sourceConfig, progress *prometheus.CounterVec) (err error) {
+	args, err := createCommandArguments(imageURL, config, source)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = config.rateLimit.Maintain(ctx, rateFile)
+	if err != nil {
+		return
+	}
+	cmd := exec.Command("nbdkit", args...)
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	cmd.Stderr = cmd.Stdout
+	klog.Info("Streaming the image from ", config.format, " to raw.")
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := conversionProgress.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[1], 64)
+			setProgress(progress, config.ownerUID, percent)
+		}
+		klog.Info(line)
+	}
+	err = cmd.Wait()
+	if err != nil {
+		return
+	}
+	finalizeProgress(progress, config.ownerUID)
+	return
+}
+
+// Arguments of nbdkit serving the image with the curl
+// plugin to the image-converter.
+func createCommandArguments(imageURL string, config *AppConfig, source *sourceConfig) (args []string, err error) {
+	filterOptions, params := config.rateLimit.NbdkitArgs(rateFile)
+	args = []string{
+		"--exit-with-parent",
+		"--foreground",
+		"--readonly",
+		"--unix", "-",
+	}
+	args = append(args, filterOptions...)
+	args = append(args,
+		"curl",
+		"url="+imageURL)
+	args = append(args, params...)
+	if source.insecure {
+		args = append(args, "sslverify=false")
+	} else if source.cacert != "" {
+		err = os.WriteFile(caFile, []byte(source.cacert), 0600)
+		if err != nil {
+			return
+		}
+		args = append(args, "cainfo="+caFile)
+	}
+	args = append(args, "--run", fmt.Sprintf(
+		"%s -src-path \"$uri\" -dst-path %s -src-format %s -dst-format raw",
+		imageConverter,
+		strconv.Quote(config.volumePath),
+		strconv.Quote(config.format)))
+	return
+}
+
+// Convert the downloaded image to raw into the volume.
+func convert(scratch string, config *AppConfig, progress *prometheus.CounterVec) (err error) {
+	cmd := exec.Command(
+		imageConverter,
+		"-src-path", scratch,
+		"-dst-path", config.volumePath,
+		"-src-format", config.format,
+		"-dst-format", "raw")
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	cmd.Stderr = cmd.Stdout
+	klog.Info("Converting the image from ", config.format, " to raw.")
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := conversionProgress.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[1], 64)
+			setProgress(progress, config.ownerUID, 50+percent/2)
+		}
+		klog.Info(line)
+	}
+	err = cmd.Wait()
+	return
+}
+
+// Parse the "<algorithm>:<hex digest>" checksum.
+func parseChecksum(checksum string) (h hash.Hash, algorithm, expected string, err error) {
+	if checksum == "" {
+		return
+	}
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	algorithm = strings.ToLower(algorithm)
+	if !found {
+		err = fmt.Errorf("invalid checksum %q", checksum)
+		return
+	}
+	switch algorithm {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		err = fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+		return
+	}
+	expected = strings.ToLower(digest)
+	return
+}
+
+func createHTTPClient(source *sourceConfig) (client *http.Client, err error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: source.insecure}
+	if !source.insecure && source.cacert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(source.cacert)) {
+			err = errors.New("failed to parse the CA certificate")
+			return
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	return
+}
+
+func createProgressCounter() *prometheus.CounterVec {
+	progress := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_populator_progress",
+			Help: "Progress of volume population",
+		},
+		[]string{"ownerUID"},
+	)
+	if err := prometheus.Register(progress); err != nil {
+		klog.Error("Prometheus progress counter not registered:", err)
+	}
+	return progress
+}
+
+func createCheckpointGauge() *prometheus.GaugeVec {
+	checkpoints := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "http_populator_checkpoint",
+			Help: "Bytes stored in the volume",
+		},
+		[]string{"ownerUID", "state"},
+	)
+	if err := prometheus.Register(checkpoints); err != nil {
+		klog.Error("Prometheus checkpoint gauge not registered:", err)
+	}
+	return checkpoints
+}
+
+func monitorProgress(reader *CountingReader, progress *prometheus.CounterVec, ownerUID string, pvcSize int64, done chan bool) {
+	metric := &dto.Metric{}
+	for {
+		select {
+		case succeeded := <-done:
+			if succeeded {
+				finalizeProgress(progress, ownerUID)
+			}
+			return
+		case <-time.After(time.Second):
+			currentProgress := (float64(reader.read.Load()) / float64(pvcSize)) * 100
+			if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
+				klog.Error(err)
+			} else if currentProgress > metric.Counter.GetValue() {
+				progress.WithLabelValues(ownerUID).Add(currentProgress - metric.Counter.GetValue())
+			}
+		}
+	}
+}
+
+func setProgress(progress *prometheus.CounterVec, ownerUID string, percent float64) {
+	metric := &dto.Metric{}
+	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
+		klog.Error(err)
+		return
+	}
+	if percent > metric.Counter.GetValue() {
+		progress.WithLabelValues(ownerUID).Add(percent - metric.Counter.GetValue())
+	}
+}
+
+func finalizeProgress(progress *prometheus.CounterVec, ownerUID string) {
+	metric := &dto.Metric{}
+	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
+		klog.Error(err)
+		return
+	}
+	remaining := 100 - metric.Counter.GetValue()
+	if remaining > 0 {
+		progress.WithLabelValues(ownerUID).Add(remaining)
+	}
+	klog.Info("Finished populating the volume. Progress: 100%")
+}
+
+func loadSourceConfig() *sourceConfig {
+	source := &sourceConfig{
+		cacert:   os.Getenv("cacert"),
+		insecure: getEnvAsBool("insecureSkipVerify", false),
+	}
+	if accessKeyID := os.Getenv("accessKeyId"); accessKeyID != "" {
+		source.s3 = &s3Credentials{
+			accessKeyID:  accessKeyID,
+			secretKey:    os.Getenv("secretKey"),
+			sessionToken: os.Getenv("sessionToken"),
+			region:       os.Getenv("region"),
+		}
+	}
+	return source
+}
+
+func getEnvAsBool(key string, defaultVal bool) bool {
+	val, found := os.LookupEnv(key)
+	if !found {
+		return defaultVal
+	}
+	boolVal, err := strconv.ParseBool(val)
+	if err != nil {
+		klog.Fatal("Invalid boolean value for", key, ":", val)
+	}
+	return boolVal
+}
+
+type CountingReader struct {
+	reader io.Reader
+	read   atomic.Int64
+}
+
+func (cr *CountingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.read.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
+)
+
+var image = append(bytes.Repeat([]byte("image"), 1000), make([]byte, 2*checkpoint.BlockSize)...)
+
+func setupServer(ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "disk.img", time.Time{}, bytes.NewReader(image))
+	}))
+}
+
+func TestPopulateRaw(t *testing.T) {
+	var ranges []string
+	server := setupServer(&ranges)
+	defer server.Close()
+
+	config := &AppConfig{
+		url:        server.URL + "/disk.img",
+		format:     "raw",
+		checksum:   fmt.Sprintf("sha256:%x", sha256.Sum256(image)),
+		volumePath: filepath.Join(t.TempDir(), "disk.img"),
+		ownerUID:   "test-uid",
+		pvcSize:    int64(len(image)),
+	}
+	if _, err := populate(config, &sourceConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(config.volumePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, image) {
+		t.Error("Unexpected volume content")
+	}
+}
+
+func TestTransferResume(t *testing.T) {
+	var ranges []string
+	server := setupServer(&ranges)
+	defer server.Close()
+
+	config := &AppConfig{
+		url:          server.URL + "/disk.img",
+		checksum:     fmt.Sprintf("sha256:%x", sha256.Sum256(image)),
+		volumePath:   filepath.Join(t.TempDir(), "disk.img"),
+		pvcSize:      int64(len(image)),
+		resumeOffset: 100,
+	}
+	writer, err := checkpoint.Open(config.volumePath, 0, "", sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = writer.Write(image[:100]); err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	config.resumeState = writer.State()
+	checksum, err := transfer(http.DefaultClient, config.url, config, config.resumeOffset, createProgressCounter(), createCheckpointGauge())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum.String() != fmt.Sprintf("%s:%d", config.checksum, len(image)) {
+		t.Errorf("Unexpected checksum %s", checksum)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=100-" {
+		t.Errorf("Unexpected range requests %v", ranges)
+	}
+	content, err := os.ReadFile(config.volumePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, image) {
+		t.Error("Unexpected volume content")
+	}
+}
+
+func TestTransferChecksumMismatch(t *testing.T) {
+	var ranges []string
+	server := setupServer(&ranges)
+	defer server.Close()
+
+	config := &AppConfig{
+		url:        server.URL + "/disk.img",
+		checksum:   "md5:00000000000000000000000000000000",
+		volumePath: filepath.Join(t.TempDir(), "disk.img"),
+		pvcSize:    int64(len(image)),
+	}
+	_, err := transfer(http.DefaultClient, config.url, config, 0, createProgressCounter(), createCheckpointGauge())
+	if !errors.Is(err, checkpoint.ErrChecksum) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	config.format = "qcow2"
+	t.Setenv("TMPDIR", t.TempDir())
+	path, err := fetch(http.DefaultClient, config.url, config, createProgressCounter())
+	if !errors.Is(err, checkpoint.ErrChecksum) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	if path == "" {
+		t.Error("Expected the scratch file")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	var ranges []string
+	server := setupServer(&ranges)
+	defer server.Close()
+
+	config := &AppConfig{
+		url:        server.URL + "/disk.qcow2",
+		format:     "qcow2",
+		checksum:   fmt.Sprintf("sha256:%x", sha256.Sum256(image)),
+		volumePath: filepath.Join(t.TempDir(), "disk.img"),
+		pvcSize:    int64(len(image)),
+	}
+	scratch := t.TempDir()
+	t.Setenv("TMPDIR", scratch)
+	path, err := fetch(http.DefaultClient, config.url, config, createProgressCounter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != scratch {
+		t.Errorf("Unexpected scratch file %s", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, image) {
+		t.Error("Unexpected scratch content")
+	}
+	if len(ranges) != 1 || ranges[0] != "" {
+		t.Errorf("Expected a single download, got %v", ranges)
+	}
+}
+
+func TestStreamable(t *testing.T) {
+	config := &AppConfig{format: "qcow2"}
+	if !streamable(config, &sourceConfig{}) {
+		t.Error("Expected the image to be streamed")
+	}
+	if streamable(config, &sourceConfig{s3: &s3Credentials{}}) {
+		t.Error("Expected the S3 image to be downloaded")
+	}
+	config.relayURL = "https://relay.example.com:8443"
+	if streamable(config, &sourceConfig{}) {
+		t.Error("Expected the relayed image to be downloaded")
+	}
+	config.relayURL = ""
+	config.checksum = "sha256:abcd"
+	if streamable(config, &sourceConfig{}) {
+		t.Error("Expected the image with a checksum to be downloaded")
+	}
+}
+
+func TestCreateCommandArguments(t *testing.T) {
+	config := &AppConfig{
+		format:     "vmdk",
+		volumePath: "/dev/block",
+		rateLimit:  &ratelimit.Schedule{Default: 1000},
+	}
+	args, err := createCommandArguments("https://images.example.com/disk.vmdk", config, &sourceConfig{insecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(args, " ")
+	for _, expected := range []string{
+		"--filter=rate curl url=https://images.example.com/disk.vmdk rate=8000 rate-file=" + rateFile,
+		"sslverify=false",
+		`-src-path "$uri" -dst-path "/dev/block" -src-format "vmdk" -dst-format raw`,
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected %q in %q", expected, joined)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, valid := range []string{"http://images.example.com/disk.img", "https://bucket.s3.amazonaws.com/disk.qcow2"} {
+		if err := validateURL(valid); err != nil {
+			t.Errorf("Unexpected error for %s: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"file:///etc/passwd", "ftp://images.example.com/disk.img", "images.example.com/disk.img"} {
+		if err := validateURL(invalid); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	if _, _, _, err := parseChecksum("crc32:0000"); err == nil {
+		t.Error("Expected an unsupported algorithm error")
+	}
+	h, algorithm, expected, err := parseChecksum("sha256:ABCD")
+	if err != nil || h == nil || algorithm != "sha256" || expected != "abcd" {
+		t.Errorf("Unexpected result %v %s %s %v", h, algorithm, expected, err)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// Region used when the secret does not specify one.
+	defaultRegion = "us-east-1"
+	// Longest validity of a presigned URL.
+	presignExpiry = 7 * 24 * time.Hour
+	// Hash of the payload of presigned requests.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3 credentials.
+type s3Credentials struct {
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+	region       string
+}
+
+// Presign the object URL (AWS signature version 4 query string
+// authentication) so that it can be read by plain HTTP clients.
+func presign(rawURL string, credentials *s3Credentials, now time.Time, expiry time.Duration) (signed string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+	region := credentials.region
+	if region == "" {
+		region = defaultRegion
+	}
+	query := u.Query()
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiry.Seconds()), 10))
+	u.RawQuery = query.Encode()
+	// S3 signs the path escaped once.
+	u.RawPath = canonicalPath(u)
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return
+	}
+	signer := v4.NewSigner(func(options *v4.SignerOptions) {
+		options.DisableURIPathEscaping = true
+	})
+	signed, _, err = signer.PresignHTTP(
+		context.Background(),
+		aws.Credentials{
+			AccessKeyID:     credentials.accessKeyID,
+			SecretAccessKey: credentials.secretKey,
+			SessionToken:    credentials.sessionToken,
+		},
+		request,
+		unsignedPayload,
+		"s3",
+		region,
+		now)
+	return
+}
+
+// URI encode each path segment, S3 does not normalize the path.
+func canonicalPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Encode all but the unreserved characters (RFC 3986).
+func uriEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
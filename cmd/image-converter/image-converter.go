@@ -4,45 +4,92 @@ import (
 	"bufio"
 	"bytes"
 	"flag"
+	"fmt"
+	"net/http"
 	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/klog/v2"
 )
 
+// Image formats mapped to the qemu-img formats.
+var qemuFormats = map[string]string{
+	"raw":   "raw",
+	"qcow2": "qcow2",
+	"vmdk":  "vmdk",
+	"vhd":   "vpc",
+	"vhdx":  "vhdx",
+	"vdi":   "vdi",
+}
+
+// Progress printed by qemu-img -p: "    (12.34/100%)".
+var progressRegex = regexp.MustCompile(`\((\d+(?:\.\d+)?)/100%\)`)
+
 func main() {
-	var srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode string
+	var srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode, metricsAddress string
 
 	flag.StringVar(&srcVolPath, "src-path", "", "Source volume path")
 	flag.StringVar(&dstVolPath, "dst-path", "", "Target volume path")
-	flag.StringVar(&srcFormat, "src-format", "", "Format of the source volume")
-	flag.StringVar(&dstFormat, "dst-format", "", "Format of the target volume")
-	flag.StringVar(&volumeMode, "volume-mode", "", "Format of the target volume")
+	flag.StringVar(&srcFormat, "src-format", "", "Format of the source volume (raw, qcow2, vmdk, vhd, vhdx, vdi)")
+	flag.StringVar(&dstFormat, "dst-format", "", "Format of the target volume (raw, qcow2, vmdk, vhd, vhdx, vdi)")
+	flag.StringVar(&volumeMode, "volume-mode", "", "Mode of the source volume the converted image is copied back to (Block, Filesystem), "+
+		"the image is converted directly into the target when empty")
+	flag.StringVar(&metricsAddress, "metrics-address", "", "Address serving the progress metric (:2112), not served when empty")
 
 	flag.Parse()
 
 	klog.Info("srcVolPath: ", srcVolPath, " dstVolPath: ", dstVolPath, " sourceFormat: ", srcFormat, " targetFormat: ", dstFormat)
-	err := convert(srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode)
+	progress := &Progress{}
+	if metricsAddress != "" {
+		progress.gauge = createProgressGauge()
+		http.Handle("/metrics", promhttp.Handler())
+		go func() {
+			klog.Info("Serving the metrics on: ", metricsAddress)
+			if err := http.ListenAndServe(metricsAddress, nil); err != nil {
+				klog.Warning("Failed to serve the metrics: ", err)
+			}
+		}()
+	}
+	err := convert(srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode, progress)
 	if err != nil {
 		klog.Fatal(err)
 	}
 }
 
-func convert(srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode string) error {
-	err := qemuimgConvert(srcVolPath, dstVolPath, srcFormat, dstFormat)
+func convert(srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode string, progress *Progress) error {
+	src, err := qemuFormat(srcFormat)
 	if err != nil {
 		return err
 	}
+	dst, err := qemuFormat(dstFormat)
+	if err != nil {
+		return err
+	}
+	// Copying back to a block device is a second pass.
+	if volumeMode == "Block" {
+		progress.passes = 2
+	}
 
-	klog.Info("Copying over source")
+	err = qemuimgConvert(srcVolPath, dstVolPath, src, dst, progress)
+	if err != nil {
+		return err
+	}
 
 	// Copy dst over src
 	switch volumeMode {
 	case "Block":
-		err = qemuimgConvert(dstVolPath, srcVolPath, dstFormat, dstFormat)
+		klog.Info("Copying over source")
+		progress.pass++
+		err = qemuimgConvert(dstVolPath, srcVolPath, dst, dst, progress)
 		if err != nil {
 			return err
 		}
 	case "Filesystem":
+		klog.Info("Copying over source")
 		// Use mv for files as it's faster than qemu-img convert
 		cmd := exec.Command("mv", dstVolPath, srcVolPath)
 		var stderr bytes.Buffer
@@ -54,11 +101,21 @@ func convert(srcVolPath, dstVolPath, srcFormat, dstFormat, volumeMode string) er
 			return err
 		}
 	}
+	progress.Update(100)
 
 	return nil
 }
 
-func qemuimgConvert(srcVolPath, dstVolPath, srcFormat, dstFormat string) error {
+// Format of qemu-img.
+func qemuFormat(format string) (string, error) {
+	qemu, found := qemuFormats[strings.ToLower(format)]
+	if !found {
+		return "", fmt.Errorf("unsupported image format %q", format)
+	}
+	return qemu, nil
+}
+
+func qemuimgConvert(srcVolPath, dstVolPath, srcFormat, dstFormat string, progress *Progress) error {
 	cmd := exec.Command(
 		"qemu-img",
 		"convert",
@@ -93,9 +150,17 @@ func qemuimgConvert(srcVolPath, dstVolPath, srcFormat, dstFormat string) error {
 	}()
 
 	scanner := bufio.NewScanner(stdout)
+	scanner.Split(scanLines)
 	for scanner.Scan() {
 		line := scanner.Text()
-		klog.Info(line)
+		if match := progressRegex.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[1], 64)
+			progress.Update(percent)
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			klog.Info(line)
+		}
 	}
 
 	err = cmd.Wait()
@@ -105,3 +170,59 @@ func qemuimgConvert(srcVolPath, dstVolPath, srcFormat, dstFormat string) error {
 
 	return nil
 }
+
+// Split the lines ended by a line feed or
+// the carriage return qemu-img rewrites
+// the progress with.
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		advance, token = i+1, data[:i]
+		return
+	}
+	if atEOF {
+		advance, token = len(data), data
+	}
+	return
+}
+
+func createProgressGauge() prometheus.Gauge {
+	gauge := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "image_converter_progress",
+			Help: "Percent of the image converted",
+		},
+	)
+	if err := prometheus.Register(gauge); err != nil {
+		klog.Error("Prometheus progress gauge not registered:", err)
+	}
+	return gauge
+}
+
+// Progress of the conversion.
+// Logged as "Progress: <percent>%" and set on the
+// gauge when the metrics are served.
+type Progress struct {
+	gauge prometheus.Gauge
+	// Number of qemu-img passes, one when not set.
+	passes int
+	// Current pass, starting at zero.
+	pass int
+	// Last percent logged.
+	logged int64
+}
+
+// Update the progress with the percent of the current pass.
+func (r *Progress) Update(percent float64) {
+	passes := max(r.passes, 1)
+	total := (float64(r.pass)*100 + min(percent, 100)) / float64(passes)
+	if r.gauge != nil {
+		r.gauge.Set(total)
+	}
+	if int64(total) > r.logged {
+		r.logged = int64(total)
+		klog.Info("Progress: ", r.logged, "%")
+	}
+}
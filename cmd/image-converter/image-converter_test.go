@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestQemuFormat(t *testing.T) {
+	cases := map[string]string{
+		"raw":   "raw",
+		"qcow2": "qcow2",
+		"VMDK":  "vmdk",
+		"vhd":   "vpc",
+		"vhdx":  "vhdx",
+		"vdi":   "vdi",
+	}
+	for format, expected := range cases {
+		qemu, err := qemuFormat(format)
+		if err != nil {
+			t.Fatalf("qemuFormat(%q) failed: %v", format, err)
+		}
+		if qemu != expected {
+			t.Errorf("qemuFormat(%q) = %q, expected %q", format, qemu, expected)
+		}
+	}
+	if _, err := qemuFormat("ami"); err == nil {
+		t.Error("qemuFormat(\"ami\") did not fail")
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	output := "    (0.00/100%)\r    (50.50/100%)\r    (100.00/100%)\r\ndone"
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Split(scanLines)
+	var percents []string
+	var lines []string
+	for scanner.Scan() {
+		if match := progressRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			percents = append(percents, match[1])
+		} else if scanner.Text() != "" {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	if strings.Join(percents, ",") != "0.00,50.50,100.00" {
+		t.Errorf("unexpected progress: %v", percents)
+	}
+	if len(lines) != 1 || lines[0] != "done" {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
+
+func TestProgressPasses(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_progress"})
+	progress := &Progress{gauge: gauge, passes: 2}
+	value := func() float64 {
+		metric := &dto.Metric{}
+		if err := gauge.Write(metric); err != nil {
+			t.Fatal(err)
+		}
+		return metric.Gauge.GetValue()
+	}
+
+	progress.Update(50)
+	if value() != 25 {
+		t.Errorf("first pass at 50%% reported %v", value())
+	}
+	progress.pass++
+	progress.Update(50)
+	if value() != 75 {
+		t.Errorf("second pass at 50%% reported %v", value())
+	}
+	progress.Update(100)
+	if value() != 100 {
+		t.Errorf("completed conversion reported %v", value())
+	}
+}
@@ -1,12 +1,31 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
 	"flag"
+	"fmt"
+	"hash"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/relay"
 	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/openstack"
 	"github.com/konveyor/forklift-controller/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,29 +33,66 @@ import (
 	"k8s.io/klog/v2"
 )
 
+const (
+	// Converts the image read through nbdkit.
+	imageConverter = "/usr/local/bin/image-converter"
+	// CA certificate passed to nbdkit.
+	caFile = "/tmp/ca.pem"
+	// Rate read by the nbdkit rate filter.
+	rateFile = "/tmp/rate"
+	// Prints the header authenticating the nbdkit requests.
+	headerScript = "/tmp/auth-header.sh"
+	// Seconds after which nbdkit runs the header script
+	// again, renewing the token during long conversions.
+	headerRenew = 600
+)
+
+// Progress logged by the image-converter.
+var conversionProgress = regexp.MustCompile(`Progress: (\d+)%`)
+
 type AppConfig struct {
 	identityEndpoint string
 	imageID          string
+	format           string
+	authHeader       bool
 	crNamespace      string
 	crName           string
 	secretName       string
 	ownerUID         string
 	pvcSize          int64
+	resumeOffset     int64
+	resumeState      string
 	volumePath       string
+	relayURL         string
+	relayCompression string
+	rateLimit        *ratelimit.Schedule
 }
 
 func main() {
-	config := &AppConfig{}
+	config := &AppConfig{rateLimit: &ratelimit.Schedule{}}
 	flag.StringVar(&config.identityEndpoint, "endpoint", "", "endpoint URL (https://openstack.example.com:5000/v2.0)")
 	flag.StringVar(&config.secretName, "secret-name", "", "secret containing OpenStack credentials")
 	flag.StringVar(&config.imageID, "image-id", "", "Openstack image ID")
+	flag.StringVar(&config.format, "format", "raw", "Format of the image, converted to raw (raw, qcow2, vmdk, vhd, vhdx, vdi)")
+	flag.BoolVar(&config.authHeader, "auth-header", false, "Print the header authenticating the image requests and exit")
 	flag.StringVar(&config.volumePath, "volume-path", "", "Path to populate")
 	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
 	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
 	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
 	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Int64Var(&config.resumeOffset, "resume-offset", 0, "Offset (in bytes) to resume an interrupted transfer from")
+	flag.StringVar(&config.resumeState, "resume-state", "", "State of the hashes at the resume offset")
+	flag.StringVar(&config.relayURL, "relay-url", "", "URL of the transfer relay (https://relay.example.com:8443)")
+	flag.StringVar(&config.relayCompression, "relay-compression", "zstd", "Compression of the relayed stream (zstd, none)")
+	flag.Int64Var(&config.rateLimit.Default, "rate-limit", 0, "Transfer rate limit (bytes per second, 0 is not limited)")
+	flag.Var(config.rateLimit, "rate-window", "Transfer rate limit during a UTC time window ([Mon,...]/HH:MM-HH:MM=<bytes per second>)")
 	flag.Parse()
 
+	if config.authHeader {
+		printAuthHeader(config)
+		return
+	}
+
 	if config.pvcSize <= 0 {
 		klog.Fatal("pvc-size must be greater than 0")
 	}
@@ -48,12 +104,23 @@ func main() {
 
 	metrics.StartPrometheusEndpoint(certsDirectory)
 
-	populate(config)
+	checksum := populate(config)
+	if checksum != nil {
+		if err = checksum.Report(checkpoint.TerminationLog); err != nil {
+			klog.Warning("Failed to report the checksum: ", err)
+		}
+	}
 }
 
-func populate(config *AppConfig) {
+// Populate the volume. Returns the checksum of raw images, the
+// checksum of converted images would not match the volume content.
+func populate(config *AppConfig) *checkpoint.Checksum {
 	client := createClient(config)
-	downloadAndSaveImage(client, config)
+	if config.format != "" && config.format != "raw" {
+		convertImage(client, config)
+		return nil
+	}
+	return downloadAndSaveImage(client, config)
 }
 
 func createClient(config *AppConfig) *libclient.Client {
@@ -67,24 +134,334 @@ func createClient(config *AppConfig) *libclient.Client {
 	if err != nil {
 		klog.Fatal(err)
 	}
+	if config.relayURL != "" {
+		transport, err := relay.NewTransport(config.relayURL, config.relayCompression != relay.CompressionNone, relay.CertDir)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.Info("Downloading through the relay: ", config.relayURL)
+		client.DataTransport = transport
+	}
 
 	return client
 }
 
-func downloadAndSaveImage(client *libclient.Client, config *AppConfig) {
+func downloadAndSaveImage(client *libclient.Client, config *AppConfig) (checksum *checkpoint.Checksum) {
 	klog.Info("Downloading the image: ", config.imageID)
-	imageReader, err := client.DownloadImage(config.imageID)
+	progressVec := createProgressCounter()
+	checkpointVec := createCheckpointGauge()
+	checksum, err := transfer(client, config, config.resumeOffset, progressVec, checkpointVec)
+	if errors.Is(err, checkpoint.ErrChecksum) && config.resumeOffset > 0 {
+		klog.Warning("Resumed transfer failed verification, restarting from the beginning: ", err)
+		checksum, err = transfer(client, config, 0, progressVec, checkpointVec)
+	}
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return
+}
+
+// Download the image into the volume starting at the offset
+// and verify the checksum published by the image service.
+// Returns the checksum of the image.
+func transfer(client *libclient.Client, config *AppConfig, offset int64, progress *prometheus.CounterVec, checkpoints *prometheus.GaugeVec) (checksum *checkpoint.Checksum, err error) {
+	h, algorithm, expected := imageChecksum(client, config.imageID)
+	writer, err := checkpoint.Open(config.volumePath, offset, config.resumeState, h)
+	if err != nil {
+		return
+	}
+	// Skip the zero blocks of raw images, qcow2 images
+	// do not store the unallocated clusters anyway.
+	writer.Sparse = true
+	writer.Checkpoint = func(offset int64, state string) {
+		checkpoints.Reset()
+		checkpoints.WithLabelValues(config.ownerUID, state).Set(float64(offset))
+	}
+	if writer.Offset() > 0 {
+		klog.Info("Resuming the transfer at offset: ", writer.Offset())
+	}
+
+	imageReader, ranged, err := client.DownloadImageFrom(config.imageID, writer.Offset())
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	defer imageReader.Close()
+	if writer.Offset() > 0 && !ranged {
+		klog.Info("Range requests not supported, skipping the transferred data.")
+		if _, err = io.CopyN(io.Discard, imageReader, writer.Offset()); err != nil {
+			_ = writer.Close()
+			return
+		}
+	}
+
+	err = writeData(ratelimit.NewReader(imageReader, config.rateLimit), writer, config, progress)
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+	klog.Info("Skipped writing zero bytes: ", writer.Skipped())
+	if expected == "" {
+		klog.Warning("The image has no checksum, skipping verification.")
+	} else {
+		err = writer.Verify(expected)
+		if err != nil {
+			return
+		}
+		klog.Info("Verified the image checksum.")
+	}
+	checksum = &checkpoint.Checksum{
+		Algorithm: algorithm,
+		Digest:    hex.EncodeToString(writer.Sum()),
+		Size:      writer.Offset(),
+	}
+	return
+}
+
+// Convert the image to raw while populating the volume. The
+// image is streamed through nbdkit, nothing is stored in the
+// scratch space. Images published with a checksum are verified
+// first by reading them once more, the verification is the first
+// half of the progress and the conversion the other.
+func convertImage(client *libclient.Client, config *AppConfig) {
+	klog.Info("Converting the image: ", config.imageID)
+	progress := createProgressCounter()
+	h, _, expected := imageChecksum(client, config.imageID)
+	start := float64(0)
+	var err error
+	if expected != "" {
+		err = verify(client, config, h, expected, progress)
+		start = 50
+	}
+	if err == nil {
+		err = convert(client, config, progress, start)
+	}
 	if err != nil {
 		klog.Fatal(err)
 	}
+	finalizeProgress(progress, config.ownerUID)
+}
 
+// Verify the checksum of the image by hashing the image read
+// through the image client, with the relay and the rate limit.
+func verify(client *libclient.Client, config *AppConfig, h hash.Hash, expected string, progress *prometheus.CounterVec) (err error) {
+	imageReader, _, err := client.DownloadImageFrom(config.imageID, 0)
+	if err != nil {
+		return
+	}
 	defer imageReader.Close()
 
-	file := openFile(config.volumePath)
-	defer file.Close()
+	read := int64(0)
+	countingReader := &CountingReader{
+		reader: ratelimit.NewReader(imageReader, config.rateLimit),
+		total:  2 * config.pvcSize,
+		read:   &read,
+	}
+	done := make(chan bool)
+	go reportProgress(done, countingReader, progress, config)
+	klog.Info("Verifying the ", config.format, " image.")
+	_, err = io.Copy(h, countingReader)
+	// The progress is finalized after the conversion.
+	done <- false
+	if err != nil {
+		return
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != expected {
+		err = fmt.Errorf("%w: expected %s, got %s", checkpoint.ErrChecksum, expected, actual)
+		return
+	}
+	klog.Info("Verified the image checksum.")
+	return
+}
 
-	progressVec := createProgressCounter()
-	writeData(imageReader, file, config, progressVec)
+// Convert the image with the image-converter, reading it through
+// the nbdkit curl plugin which only fetches the ranges qemu-img
+// needs. The raw image is written directly into the volume. Images
+// read through the relay are served to nbdkit on the loopback.
+// The progress of the conversion starts at the percent passed.
+func convert(client *libclient.Client, config *AppConfig, progress *prometheus.CounterVec, start float64) (err error) {
+	imageURL, _, err := client.ImageData(config.imageID)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := client.Options
+	if client.DataTransport != nil {
+		imageURL, err = serveRelayed(ctx, imageURL, client.DataTransport)
+		if err != nil {
+			return
+		}
+		// The relay verifies the image service.
+		options = nil
+	}
+	args, err := createCommandArguments(imageURL, config, options)
+	if err != nil {
+		return
+	}
+	err = config.rateLimit.Maintain(ctx, rateFile)
+	if err != nil {
+		return
+	}
+	cmd := exec.Command("nbdkit", args...)
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	cmd.Stderr = cmd.Stdout
+	klog.Info("Converting the image from ", config.format, " to raw.")
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := conversionProgress.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[1], 64)
+			setProgress(progress, config.ownerUID, start+percent*(100-start)/100)
+		}
+		klog.Info(line)
+	}
+	err = cmd.Wait()
+	return
+}
+
+// Serve the image on the loopback, proxied through the transport
+// until the context is done. Returns the URL of the image served.
+func serveRelayed(ctx context.Context, imageURL string, transport http.RoundTripper) (servedURL string, err error) {
+	upstream, err := url.Parse(imageURL)
+	if err != nil {
+		return
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	server := &http.Server{
+		Handler: &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				u := *upstream
+				r.Out.URL = &u
+				r.Out.Host = upstream.Host
+			},
+			Transport: transport,
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go func() {
+		if sErr := server.Serve(listener); !errors.Is(sErr, http.ErrServerClosed) {
+			klog.Error("Failed to serve the image: ", sErr)
+		}
+	}()
+	servedURL = (&url.URL{Scheme: "http", Host: listener.Addr().String(), Path: upstream.Path}).String()
+	return
+}
+
+func createCommandArguments(imageURL string, config *AppConfig, options map[string]string) (args []string, err error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+	// The header script runs the populator with the environment
+	// holding the credentials, the token is not in the arguments.
+	script := fmt.Sprintf(
+		"#!/bin/sh\nexec %s -auth-header -endpoint=%s -image-id=%s\n",
+		strconv.Quote(executable),
+		strconv.Quote(config.identityEndpoint),
+		strconv.Quote(config.imageID))
+	err = os.WriteFile(headerScript, []byte(script), 0700)
+	if err != nil {
+		return
+	}
+	filterOptions, params := config.rateLimit.NbdkitArgs(rateFile)
+	args = []string{
+		"--exit-with-parent",
+		"--foreground",
+		"--readonly",
+		"--unix", "-",
+	}
+	args = append(args, filterOptions...)
+	args = append(args,
+		"curl",
+		"url="+imageURL,
+		"header-script="+headerScript,
+		"header-script-renew="+strconv.Itoa(headerRenew))
+	args = append(args, params...)
+	if insecure, _ := strconv.ParseBool(options["insecureSkipVerify"]); insecure {
+		args = append(args, "sslverify=false")
+	} else if options["cacert"] != "" {
+		err = os.WriteFile(caFile, []byte(options["cacert"]), 0600)
+		if err != nil {
+			return
+		}
+		args = append(args, "cainfo="+caFile)
+	}
+	args = append(args, "--run", fmt.Sprintf(
+		"%s -src-path \"$uri\" -dst-path %s -src-format %s -dst-format raw",
+		imageConverter,
+		strconv.Quote(config.volumePath),
+		strconv.Quote(config.format)))
+	return
+}
+
+// Print the header authenticating the image requests.
+// Run by nbdkit through the header script.
+func printAuthHeader(config *AppConfig) {
+	client := &libclient.Client{
+		URL:     config.identityEndpoint,
+		Options: readOptions(),
+	}
+	err := client.Connect()
+	if err != nil {
+		klog.Fatal(err)
+	}
+	_, token, err := client.ImageData(config.imageID)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	fmt.Println("X-Auth-Token: " + token)
+}
+
+// Hash matching the checksum published for the image.
+// Prefers the multihash over the legacy md5 checksum.
+// Images without a checksum are hashed with sha256 and
+// the expected checksum is empty.
+func imageChecksum(client *libclient.Client, imageID string) (h hash.Hash, algorithm, expected string) {
+	h, algorithm = sha256.New(), "sha256"
+	image := &libclient.Image{}
+	err := client.Get(image, imageID)
+	if err != nil {
+		klog.Warning("Failed to get the image checksum: ", err)
+		return
+	}
+	algo, _ := image.Properties["os_hash_algo"].(string)
+	value, _ := image.Properties["os_hash_value"].(string)
+	if value != "" {
+		switch algo {
+		case "sha256":
+			expected = value
+			return
+		case "sha384":
+			h, algorithm, expected = sha512.New384(), algo, value
+			return
+		case "sha512":
+			h, algorithm, expected = sha512.New(), algo, value
+			return
+		}
+	}
+	if image.Checksum != "" {
+		h, algorithm, expected = md5.New(), "md5", image.Checksum
+	}
+	return
 }
 
 func createProgressCounter() *prometheus.CounterVec {
@@ -103,35 +480,43 @@ func createProgressCounter() *prometheus.CounterVec {
 	return progressVec
 }
 
-func openFile(volumePath string) *os.File {
-	flags := os.O_RDWR
-	if strings.HasSuffix(volumePath, "disk.img") {
-		flags |= os.O_CREATE
-	}
-	file, err := os.OpenFile(volumePath, flags, 0650)
-	if err != nil {
-		klog.Fatal(err)
+func createCheckpointGauge() *prometheus.GaugeVec {
+	checkpointVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "openstack_populator_checkpoint",
+			Help: "Bytes stored in the volume",
+		},
+		[]string{"ownerUID", "state"},
+	)
+
+	if err := prometheus.Register(checkpointVec); err != nil {
+		klog.Error("Prometheus checkpoint gauge not registered:", err)
 	}
-	return file
+
+	return checkpointVec
 }
 
-func writeData(reader io.ReadCloser, file *os.File, config *AppConfig, progress *prometheus.CounterVec) {
-	countingReader := &CountingReader{reader: reader, total: config.pvcSize, read: new(int64)}
+// Progress is reported in logical bytes, counting
+// the zero blocks skipped by the writer.
+func writeData(reader io.Reader, writer *checkpoint.Writer, config *AppConfig, progress *prometheus.CounterVec) (err error) {
+	read := writer.Offset()
+	countingReader := &CountingReader{reader: reader, total: config.pvcSize, read: &read}
 	done := make(chan bool)
 
 	go reportProgress(done, countingReader, progress, config)
 
-	if _, err := io.Copy(file, countingReader); err != nil {
-		klog.Fatal(err)
-	}
-	done <- true
+	_, err = io.Copy(writer, countingReader)
+	done <- err == nil
+	return
 }
 
 func reportProgress(done chan bool, countingReader *CountingReader, progress *prometheus.CounterVec, config *AppConfig) {
 	for {
 		select {
-		case <-done:
-			finalizeProgress(progress, config.ownerUID)
+		case succeeded := <-done:
+			if succeeded {
+				finalizeProgress(progress, config.ownerUID)
+			}
 			return
 		default:
 			updateProgress(countingReader, progress, config.ownerUID)
@@ -164,18 +549,22 @@ func updateProgress(countingReader *CountingReader, progress *prometheus.Counter
 		return
 	}
 
+	currentProgress := (float64(*countingReader.read) / float64(countingReader.total)) * 100
+	setProgress(progress, ownerUID, currentProgress)
+
+	klog.Info("Progress: ", int64(currentProgress), "%")
+}
+
+// Raise the progress counter to the percent.
+func setProgress(progress *prometheus.CounterVec, ownerUID string, percent float64) {
 	metric := &dto.Metric{}
 	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
 		klog.Errorf("updateProgress: failed to write metric; %v", err)
 	}
 
-	currentProgress := (float64(*countingReader.read) / float64(countingReader.total)) * 100
-
-	if currentProgress > *metric.Counter.Value {
-		progress.WithLabelValues(ownerUID).Add(currentProgress - *metric.Counter.Value)
+	if percent > *metric.Counter.Value {
+		progress.WithLabelValues(ownerUID).Add(percent - *metric.Counter.Value)
 	}
-
-	klog.Info("Progress: ", int64(currentProgress), "%")
 }
 
 func readOptions() map[string]string {
@@ -207,7 +596,7 @@ func sensitiveInfo(option string) bool {
 }
 
 type CountingReader struct {
-	reader io.ReadCloser
+	reader io.Reader
 	read   *int64
 	total  int64
 }
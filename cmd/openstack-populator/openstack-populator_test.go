@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
 )
 
+const mockData = "mock_data\n"
+
 func setupMockServer() (*httptest.Server, string, int, error) {
 	listener, err := net.Listen("tcp", ":0")
 	if err != nil {
@@ -41,7 +51,18 @@ func setupMockServer() (*httptest.Server, string, int, error) {
 	})
 
 	mux.HandleFunc("/v2/images/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, `mock_data`)
+		if !strings.HasSuffix(r.URL.Path, "/file") {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": "test-image-id", "os_hash_algo": "sha256", "os_hash_value": "%x"}`, sha256.Sum256([]byte(mockData)))
+			return
+		}
+		var offset int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset); err == nil {
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, mockData[offset:])
+			return
+		}
+		fmt.Fprint(w, mockData)
 	})
 
 	mux.HandleFunc("/v3/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
@@ -127,7 +148,7 @@ func setupMockServer() (*httptest.Server, string, int, error) {
 	return server, baseURL, port, nil
 }
 
-func TestPopulate(t *testing.T) {
+func setupEnv() {
 	os.Setenv("username", "testuser")
 	os.Setenv("password", "testpassword")
 	os.Setenv("projectName", "Default")
@@ -136,6 +157,10 @@ func TestPopulate(t *testing.T) {
 	os.Setenv("availability", "public")
 	os.Setenv("regionName", "RegionOne")
 	os.Setenv("authType", "password")
+}
+
+func TestPopulate(t *testing.T) {
+	setupEnv()
 
 	server, identityServerURL, port, err := setupMockServer()
 	if err != nil {
@@ -160,7 +185,10 @@ func TestPopulate(t *testing.T) {
 	}
 
 	fmt.Println("server ", identityServerURL)
-	populate(config)
+	checksum := populate(config)
+	if expected := fmt.Sprintf("sha256:%x:%d", sha256.Sum256([]byte(mockData)), len(mockData)); checksum.String() != expected {
+		t.Errorf("Expected checksum %s, got %s", expected, checksum)
+	}
 
 	file, err := os.Open(fileName)
 	if err != nil {
@@ -179,3 +207,156 @@ func TestPopulate(t *testing.T) {
 
 	os.Remove(fileName)
 }
+
+func TestPopulateResume(t *testing.T) {
+	setupEnv()
+
+	server, identityServerURL, _, err := setupMockServer()
+	if err != nil {
+		t.Fatalf("Failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	fileName := "disk.img"
+	defer os.Remove(fileName)
+
+	config := &AppConfig{
+		identityEndpoint: identityServerURL,
+		secretName:       "test-secret",
+		imageID:          "test-image-id",
+		ownerUID:         "test-uid",
+		pvcSize:          100,
+		resumeOffset:     5,
+		volumePath:       fileName,
+	}
+
+	client := createClient(config)
+	h, _, _ := imageChecksum(client, config.imageID)
+	writer, err := checkpoint.Open(fileName, 0, "", h)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	if _, err = writer.Write([]byte(mockData[:5])); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	config.resumeState = writer.State()
+	_, err = transfer(client, config, config.resumeOffset, createProgressCounter(), createCheckpointGauge())
+	if err != nil {
+		t.Fatalf("Failed to resume the transfer: %v", err)
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != mockData {
+		t.Errorf("Expected %s, got %s", mockData, string(content))
+	}
+}
+
+func TestCreateCommandArguments(t *testing.T) {
+	config := &AppConfig{
+		identityEndpoint: "https://keystone.example.com:5000/v3",
+		imageID:          "test-image-id",
+		format:           "qcow2",
+		volumePath:       "/dev/block",
+	}
+	options := map[string]string{"insecureSkipVerify": "true", "password": "secret"}
+	args, err := createCommandArguments("https://glance.example.com/v2/images/test-image-id/file", config, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(args, " ")
+	for _, expected := range []string{
+		"curl url=https://glance.example.com/v2/images/test-image-id/file header-script=" + headerScript,
+		"sslverify=false",
+		`-src-path "$uri" -dst-path "/dev/block" -src-format "qcow2" -dst-format raw`,
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected %q in %q", expected, joined)
+		}
+	}
+	if strings.Contains(joined, "secret") {
+		t.Errorf("Credentials in the arguments %q", joined)
+	}
+	script, err := os.ReadFile(headerScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), `-auth-header -endpoint="https://keystone.example.com:5000/v3" -image-id="test-image-id"`) {
+		t.Errorf("Unexpected header script %q", script)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	setupEnv()
+
+	server, identityServerURL, _, err := setupMockServer()
+	if err != nil {
+		t.Fatalf("Failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	config := &AppConfig{
+		identityEndpoint: identityServerURL,
+		imageID:          "test-image-id",
+		format:           "qcow2",
+		ownerUID:         "test-uid",
+		pvcSize:          100,
+		rateLimit:        &ratelimit.Schedule{},
+	}
+	client := createClient(config)
+	h, _, expected := imageChecksum(client, config.imageID)
+	err = verify(client, config, h, expected, createProgressCounter())
+	if err != nil {
+		t.Fatalf("Failed to verify the image: %v", err)
+	}
+
+	h, _, _ = imageChecksum(client, config.imageID)
+	err = verify(client, config, h, "0123", createProgressCounter())
+	if !errors.Is(err, checkpoint.ErrChecksum) {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+}
+
+func TestServeRelayed(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/images/test-image-id/file" || r.Header.Get("X-Auth-Token") != "token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "disk.img", time.Time{}, strings.NewReader(mockData))
+	}))
+	defer upstream.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	servedURL, err := serveRelayed(ctx, upstream.URL+"/v2/images/test-image-id/file", http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(servedURL, "http://127.0.0.1:") {
+		t.Errorf("Unexpected URL %s", servedURL)
+	}
+	request, err := http.NewRequest(http.MethodGet, servedURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("X-Auth-Token", "token")
+	request.Header.Set("Range", "bytes=2-")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusPartialContent || string(content) != mockData[2:] {
+		t.Errorf("Unexpected response %s %q", response.Status, content)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm of the ImageIO checksum.
+const imageioAlgorithm = "blake2b"
+
+// Algorithm of the block hash as reported to the controller.
+const blockHashAlgorithm = "blkhash"
+
+// Block size of the ImageIO checksum.
+const imageioBlockSize = 4 << 20
+
+// Checksum of the image computed by ImageIO.
+type imageioChecksum struct {
+	Checksum  string `json:"checksum"`
+	Algorithm string `json:"algorithm"`
+	BlockSize int64  `json:"block_size"`
+}
+
+// Get the checksum of the image from the ImageIO service.
+// ImageIO reads the whole image on the host to compute it.
+func (r *diskTransfer) checksum() (checksum *imageioChecksum, err error) {
+	response, err := r.httpClient.Get(fmt.Sprintf("%s/checksum?block_size=%d", r.url, imageioBlockSize))
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("image transfer %s checksum failed: %s", r.transferID, response.Status)
+		return
+	}
+	checksum = &imageioChecksum{}
+	err = json.NewDecoder(response.Body).Decode(checksum)
+	if err != nil {
+		checksum = nil
+		return
+	}
+	if checksum.Algorithm != imageioAlgorithm || checksum.BlockSize != imageioBlockSize {
+		err = fmt.Errorf(
+			"image transfer %s checksum %s/%d not supported",
+			r.transferID,
+			checksum.Algorithm,
+			checksum.BlockSize)
+		checksum = nil
+	}
+	return
+}
+
+// Checksum of the disk computed by ImageIO.
+// Used by the controller to verify disks copied by CDI.
+func diskChecksum(config *engineConfig, diskID string) (checksum *checkpoint.Checksum, err error) {
+	transfer, err := startTransfer(config, diskID)
+	if err != nil {
+		return
+	}
+	defer func() {
+		transfer.finish(err == nil)
+	}()
+	imageio, err := transfer.checksum()
+	if err != nil {
+		return
+	}
+	checksum = &checkpoint.Checksum{
+		Algorithm: blockHashAlgorithm,
+		Digest:    imageio.Checksum,
+		Size:      transfer.size,
+	}
+	return
+}
+
+// Block hash of the first bytes of the volume.
+// Compared by the controller with the ImageIO checksum.
+func volumeChecksum(volPath string, size int64) (checksum *checkpoint.Checksum, err error) {
+	volume, err := os.Open(volPath)
+	if err != nil {
+		return
+	}
+	defer volume.Close()
+	h := newBlockHash(imageioBlockSize)
+	_, err = io.CopyN(h, volume, size)
+	if err != nil {
+		return
+	}
+	checksum = &checkpoint.Checksum{
+		Algorithm: blockHashAlgorithm,
+		Digest:    hex.EncodeToString(h.Sum(nil)),
+		Size:      size,
+	}
+	return
+}
+
+// Block hash computed the way ImageIO computes the checksum of
+// an image. Each block is hashed with blake2b-256 and the digest
+// is the blake2b-256 of the block digests.
+type blockHash struct {
+	// Size of the blocks.
+	blockSize int64
+	// Bytes of the current block written.
+	filled int64
+	// Hash of the current block.
+	block hash.Hash
+	// Hash of the block digests.
+	outer hash.Hash
+}
+
+func newBlockHash(blockSize int64) (h *blockHash) {
+	h = &blockHash{blockSize: blockSize}
+	h.Reset()
+	return
+}
+
+func (h *blockHash) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		m := int(min(int64(len(p)), h.blockSize-h.filled))
+		_, _ = h.block.Write(p[:m])
+		h.filled += int64(m)
+		n += m
+		p = p[m:]
+		if h.filled == h.blockSize {
+			_, _ = h.outer.Write(h.block.Sum(nil))
+			h.block.Reset()
+			h.filled = 0
+		}
+	}
+	return
+}
+
+// The digest including the partial last block.
+func (h *blockHash) Sum(b []byte) []byte {
+	if h.filled == 0 {
+		return h.outer.Sum(b)
+	}
+	outer := newHash256()
+	state, _ := h.outer.(encoding.BinaryMarshaler).MarshalBinary()
+	_ = outer.(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+	_, _ = outer.Write(h.block.Sum(nil))
+	return outer.Sum(b)
+}
+
+func (h *blockHash) Reset() {
+	h.filled = 0
+	h.block = newHash256()
+	h.outer = newHash256()
+}
+
+func (h *blockHash) Size() int {
+	return blake2b.Size256
+}
+
+func (h *blockHash) BlockSize() int {
+	return blake2b.BlockSize
+}
+
+// Save the state of the hash.
+func (h *blockHash) MarshalBinary() (state []byte, err error) {
+	block, err := h.block.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return
+	}
+	outer, err := h.outer.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return
+	}
+	state = binary.AppendVarint(state, h.blockSize)
+	state = binary.AppendVarint(state, h.filled)
+	state = binary.AppendUvarint(state, uint64(len(block)))
+	state = append(state, block...)
+	state = append(state, outer...)
+	return
+}
+
+// Restore the state of the hash.
+func (h *blockHash) UnmarshalBinary(state []byte) (err error) {
+	malformed := errors.New("malformed block hash state")
+	blockSize, n := binary.Varint(state)
+	if n <= 0 || blockSize != h.blockSize {
+		err = malformed
+		return
+	}
+	state = state[n:]
+	filled, n := binary.Varint(state)
+	if n <= 0 || filled < 0 || filled >= blockSize {
+		err = malformed
+		return
+	}
+	state = state[n:]
+	length, n := binary.Uvarint(state)
+	if n <= 0 || uint64(len(state)-n) < length {
+		err = malformed
+		return
+	}
+	state = state[n:]
+	block, outer := newHash256(), newHash256()
+	err = block.(encoding.BinaryUnmarshaler).UnmarshalBinary(state[:length])
+	if err != nil {
+		return
+	}
+	err = outer.(encoding.BinaryUnmarshaler).UnmarshalBinary(state[length:])
+	if err != nil {
+		return
+	}
+	h.filled, h.block, h.outer = filled, block, outer
+	return
+}
+
+func newHash256() hash.Hash {
+	// Cannot fail without a key.
+	h, _ := blake2b.New256(nil)
+	return h
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Checksum computed the way ImageIO computes it.
+func imageioDigest(data []byte, blockSize int) string {
+	outer, _ := blake2b.New256(nil)
+	for len(data) > 0 {
+		block := data[:min(len(data), blockSize)]
+		sum := blake2b.Sum256(block)
+		outer.Write(sum[:])
+		data = data[len(block):]
+	}
+	return hex.EncodeToString(outer.Sum(nil))
+}
+
+func TestBlockHash(t *testing.T) {
+	data := bytes.Repeat([]byte("disk"), 10)
+	for _, size := range []int{0, 7, 16, 40} {
+		h := newBlockHash(16)
+		h.Write(data[:size/2])
+		h.Write(data[size/2 : size])
+		if actual, expected := hex.EncodeToString(h.Sum(nil)), imageioDigest(data[:size], 16); actual != expected {
+			t.Fatalf("size %d: expected %s, got %s", size, expected, actual)
+		}
+	}
+}
+
+func TestBlockHashResume(t *testing.T) {
+	data := bytes.Repeat([]byte("disk"), 10)
+	h := newBlockHash(16)
+	h.Write(data[:21])
+	state, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := newBlockHash(16)
+	if err = resumed.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	resumed.Write(data[21:])
+	if actual, expected := hex.EncodeToString(resumed.Sum(nil)), imageioDigest(data, 16); actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+	if err = newBlockHash(32).UnmarshalBinary(state); err == nil {
+		t.Fatal("expected the block size to not match")
+	}
+}
+
+func TestTransferChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/ticket/checksum" || r.URL.Query().Get("block_size") != "4194304" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&imageioChecksum{
+			Checksum:  "abc",
+			Algorithm: imageioAlgorithm,
+			BlockSize: imageioBlockSize,
+		})
+	}))
+	defer server.Close()
+	transfer := &diskTransfer{httpClient: server.Client(), url: server.URL + "/images/ticket"}
+	checksum, err := transfer.checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum.Checksum != "abc" {
+		t.Fatalf("unexpected checksum %s", checksum.Checksum)
+	}
+	transfer.url = server.URL + "/images/missing"
+	if _, err = transfer.checksum(); err == nil {
+		t.Fatal("expected the checksum to fail")
+	}
+}
+
+func TestVolumeChecksum(t *testing.T) {
+	data := bytes.Repeat([]byte("disk"), 3<<20)
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	size := int64(imageioBlockSize + 5)
+	checksum, err := volumeChecksum(path, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum.Algorithm != blockHashAlgorithm || checksum.Size != size {
+		t.Fatalf("unexpected checksum %s", checksum)
+	}
+	if expected := imageioDigest(data[:size], imageioBlockSize); checksum.Digest != expected {
+		t.Fatalf("expected %s, got %s", expected, checksum.Digest)
+	}
+	if _, err = volumeChecksum(path, int64(len(data))+1); err == nil {
+		t.Fatal("expected the volume to be too small")
+	}
+}
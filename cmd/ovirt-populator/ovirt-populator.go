@@ -1,39 +1,64 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"os/exec"
 	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/relay"
 	"github.com/konveyor/forklift-controller/pkg/metrics"
+	ovirtsdk "github.com/ovirt/go-ovirt"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"k8s.io/klog/v2"
 )
 
+// Time to wait for the image transfer to become ready.
+const transferTimeout = 5 * time.Minute
+
 type engineConfig struct {
 	URL      string
 	username string
 	password string
 	cacert   string
 	insecure bool
+	// Transfer relay.
+	relayURL         string
+	relayCompression string
+	// Transfer rate limit.
+	rateLimit *ratelimit.Schedule
 }
 
-type TransferProgress struct {
-	Transferred uint64  `json:"transferred"`
-	Description string  `json:"description"`
-	Size        *uint64 `json:"size,omitempty"`
-	Elapsed     float64 `json:"elapsed"`
+// Download of a disk through the ImageIO service.
+type diskTransfer struct {
+	connection *ovirtsdk.Connection
+	httpClient *http.Client
+	diskID     string
+	transferID string
+	// Provisioned size of the disk.
+	size int64
+	url  string
 }
 
 func main() {
-	var engineUrl, diskID, volPath, secretName, crName, crNamespace, ownerUID string
-	var pvcSize *int64
+	var engineUrl, diskID, volPath, secretName, crName, crNamespace, ownerUID, relayURL, relayCompression string
+	var pvcSize, resumeOffset *int64
+	var resumeState string
+	var checksumOnly bool
+	var digestSize int64
+	rateLimit := &ratelimit.Schedule{}
 
 	flag.StringVar(&engineUrl, "engine-url", "", "ovirt-engine url (https://engine.fqdn)")
 	flag.StringVar(&diskID, "disk-id", "", "ovirt-engine disk id")
@@ -43,9 +68,34 @@ func main() {
 	flag.StringVar(&crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
 	flag.StringVar(&ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
 	pvcSize = flag.Int64("pvc-size", 0, "Size of pvc (in bytes)")
+	resumeOffset = flag.Int64("resume-offset", 0, "Offset (in bytes) to resume an interrupted transfer from")
+	flag.StringVar(&resumeState, "resume-state", "", "State of the hashes at the resume offset")
+	flag.StringVar(&relayURL, "relay-url", "", "URL of the transfer relay (https://relay.fqdn:8443)")
+	flag.StringVar(&relayCompression, "relay-compression", "zstd", "Compression of the relayed stream (zstd, none)")
+	flag.Int64Var(&rateLimit.Default, "rate-limit", 0, "Transfer rate limit (bytes per second, 0 is not limited)")
+	flag.Var(rateLimit, "rate-window", "Transfer rate limit during a UTC time window ([Mon,...]/HH:MM-HH:MM=<bytes per second>)")
+	flag.BoolVar(&checksumOnly, "checksum-only", false, "Report the ImageIO checksum of the disk without transferring it")
+	flag.Int64Var(&digestSize, "digest-size", 0, "Report the block hash of the first bytes of the volume path (in bytes)")
 
 	flag.Parse()
 
+	if digestSize > 0 || checksumOnly {
+		var checksum *checkpoint.Checksum
+		var err error
+		if digestSize > 0 {
+			checksum, err = volumeChecksum(volPath, digestSize)
+		} else {
+			checksum, err = diskChecksum(loadEngineConfig(engineUrl), diskID)
+		}
+		if err == nil {
+			err = checksum.Report(checkpoint.TerminationLog)
+		}
+		if err != nil {
+			klog.Fatal(err)
+		}
+		return
+	}
+
 	if pvcSize == nil || *pvcSize <= 0 {
 		klog.Fatal("pvc-size must be greater than 0")
 	}
@@ -57,56 +107,294 @@ func main() {
 
 	metrics.StartPrometheusEndpoint(certsDirectory)
 
-	populate(engineUrl, diskID, volPath, ownerUID, *pvcSize)
+	config := loadEngineConfig(engineUrl)
+	config.relayURL = relayURL
+	config.relayCompression = relayCompression
+	config.rateLimit = rateLimit
+	populate(config, diskID, volPath, ownerUID, *pvcSize, *resumeOffset, resumeState)
 }
 
-func populate(engineURL, diskID, volPath, ownerUID string, pvcSize int64) {
-	config := loadEngineConfig(engineURL)
-	prepareCredentials(config)
-	executePopulationProcess(config, diskID, volPath, ownerUID, pvcSize)
+func populate(config *engineConfig, diskID, volPath, ownerUID string, pvcSize, resumeOffset int64, resumeState string) {
+	progress := createProgressCounter()
+	checkpoints := createCheckpointGauge()
+	checksum, err := executePopulationProcess(config, diskID, volPath, ownerUID, pvcSize, resumeOffset, resumeState, progress, checkpoints)
+	if errors.Is(err, checkpoint.ErrChecksum) && resumeOffset > 0 {
+		klog.Warning("Resumed transfer failed verification, restarting from the beginning: ", err)
+		checksum, err = executePopulationProcess(config, diskID, volPath, ownerUID, pvcSize, 0, "", progress, checkpoints)
+	}
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if err = checksum.Report(checkpoint.TerminationLog); err != nil {
+		klog.Warning("Failed to report the checksum: ", err)
+	}
 }
 
-func prepareCredentials(config *engineConfig) {
-	writeFile("/tmp/ovirt.pass", config.password)
-	if !config.insecure {
-		writeFile("/tmp/ca.pem", config.cacert)
+// Download the disk into the volume starting at the offset,
+// verify the data transferred matches the checksum computed
+// by ImageIO and the volume holds the data transferred.
+// Returns the (sha256) checksum of the disk.
+func executePopulationProcess(config *engineConfig, diskID, volPath, ownerUID string, pvcSize, offset int64, state string, progress *prometheus.CounterVec, checkpoints *prometheus.GaugeVec) (checksum *checkpoint.Checksum, err error) {
+	transfer, err := startTransfer(config, diskID)
+	if err != nil {
+		return
 	}
-}
+	defer func() {
+		transfer.finish(err == nil)
+	}()
 
-func writeFile(filename, content string) {
-	file, err := os.Create(filename)
+	source := newBlockHash(imageioBlockSize)
+	writer, err := checkpoint.Open(volPath, offset, state, sha256.New(), source)
 	if err != nil {
-		klog.Fatalf("Failed to create %s: %v", filename, err)
+		return
 	}
-	defer file.Close()
+	writer.Checkpoint = func(offset int64, state string) {
+		checkpoints.Reset()
+		checkpoints.WithLabelValues(ownerUID, state).Set(float64(offset))
+	}
+	if writer.Offset() > transfer.size {
+		_ = writer.Close()
+		err = fmt.Errorf("resume offset %d is beyond the disk size %d", writer.Offset(), transfer.size)
+		return
+	}
+	if writer.Offset() > 0 {
+		klog.Info("Resuming the transfer at offset: ", writer.Offset())
+	}
+
+	reader, err := transfer.download(writer.Offset())
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	defer reader.Close()
 
-	if _, err := file.Write([]byte(content)); err != nil {
-		klog.Fatalf("Failed to write to %s: %v", filename, err)
+	countingReader := &CountingReader{reader: ratelimit.NewReader(reader, config.rateLimit)}
+	countingReader.read.Store(writer.Offset())
+	done := make(chan bool)
+	go monitorProgress(countingReader, progress, ownerUID, pvcSize, done)
+
+	_, err = io.Copy(writer, countingReader)
+	done <- err == nil
+	if err != nil {
+		_ = writer.Close()
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+	if writer.Offset() != transfer.size {
+		err = fmt.Errorf("%w: transferred %d bytes of %d", checkpoint.ErrChecksum, writer.Offset(), transfer.size)
+		return
 	}
+	expected, err := transfer.checksum()
+	if err != nil {
+		return
+	}
+	if actual := hex.EncodeToString(source.Sum(nil)); actual != expected.Checksum {
+		err = fmt.Errorf("%w: expected %s, got %s", checkpoint.ErrChecksum, expected.Checksum, actual)
+		return
+	}
+	klog.Info("Verified the disk checksum.")
+	err = writer.VerifyVolume(volPath, sha256.New())
+	if err != nil {
+		return
+	}
+	klog.Info("Verified the volume content.")
+	checksum = &checkpoint.Checksum{
+		Algorithm: "sha256",
+		Digest:    hex.EncodeToString(writer.Sum()),
+		Size:      writer.Offset(),
+	}
+	return
 }
 
-func executePopulationProcess(config *engineConfig, diskID, volPath, ownerUID string, pvcSize int64) {
-	args := createCommandArguments(config, diskID, volPath)
-	cmd := exec.Command("ovirt-img", args...)
-	r, _ := cmd.StdoutPipe()
-	cmd.Stderr = cmd.Stdout
-	done := make(chan struct{})
-	scanner := bufio.NewScanner(r)
-	klog.Info(fmt.Sprintf("Running command: %s", cmd.String()))
+// Cancel transfers of the disk left behind by a previous
+// populator and start a new download transfer.
+func startTransfer(config *engineConfig, diskID string) (transfer *diskTransfer, err error) {
+	connection, err := ovirtsdk.NewConnectionBuilder().
+		URL(config.URL + "/ovirt-engine/api").
+		Username(config.username).
+		Password(config.password).
+		CACert([]byte(config.cacert)).
+		Insecure(config.insecure).
+		Build()
+	if err != nil {
+		return
+	}
+	httpClient, err := createHTTPClient(config)
+	if err != nil {
+		connection.Close()
+		return
+	}
+	transfer = &diskTransfer{
+		connection: connection,
+		httpClient: httpClient,
+		diskID:     diskID,
+	}
+	err = transfer.start()
+	if err != nil {
+		transfer.finish(false)
+		transfer = nil
+	}
+	return
+}
 
-	go monitorProgress(scanner, ownerUID, pvcSize, done)
+func (r *diskTransfer) start() (err error) {
+	system := r.connection.SystemService()
+	diskResponse, err := system.DisksService().DiskService(r.diskID).Get().Send()
+	if err != nil {
+		return
+	}
+	r.size = diskResponse.MustDisk().MustProvisionedSize()
 
-	if err := cmd.Start(); err != nil {
-		klog.Fatal(err)
+	transfersService := system.ImageTransfersService()
+	listResponse, err := transfersService.List().Send()
+	if err != nil {
+		return
+	}
+	for _, stale := range listResponse.MustImageTransfer().Slice() {
+		disk, found := stale.Disk()
+		if !found || disk.MustId() != r.diskID {
+			continue
+		}
+		klog.Info("Cancelling the stale image transfer: ", stale.MustId())
+		_, err = transfersService.ImageTransferService(stale.MustId()).Cancel().Send()
+		if err != nil {
+			klog.Warning("Failed to cancel the stale image transfer: ", err)
+		}
 	}
 
-	<-done
-	if err := cmd.Wait(); err != nil {
-		klog.Fatal(err)
+	transfer, err := ovirtsdk.NewImageTransferBuilder().
+		Disk(ovirtsdk.NewDiskBuilder().Id(r.diskID).MustBuild()).
+		Direction(ovirtsdk.IMAGETRANSFERDIRECTION_DOWNLOAD).
+		Format(ovirtsdk.DISKFORMAT_RAW).
+		Build()
+	if err != nil {
+		return
+	}
+	addResponse, err := transfersService.Add().ImageTransfer(transfer).Send()
+	if err != nil {
+		return
+	}
+	transfer = addResponse.MustImageTransfer()
+	r.transferID = transfer.MustId()
+	klog.Info("Started the image transfer: ", r.transferID)
+
+	deadline := time.Now().Add(transferTimeout)
+	for {
+		phase, _ := transfer.Phase()
+		switch phase {
+		case ovirtsdk.IMAGETRANSFERPHASE_TRANSFERRING:
+			var found bool
+			r.url, found = transfer.TransferUrl()
+			if !found || r.url == "" {
+				r.url, _ = transfer.ProxyUrl()
+			}
+			if r.url == "" {
+				err = fmt.Errorf("image transfer %s has no transfer URL", r.transferID)
+			}
+			return
+		case ovirtsdk.IMAGETRANSFERPHASE_INITIALIZING, ovirtsdk.IMAGETRANSFERPHASE_RESUMING, "":
+		default:
+			err = fmt.Errorf("image transfer %s is %s", r.transferID, phase)
+			return
+		}
+		if time.Now().After(deadline) {
+			err = fmt.Errorf("timed out waiting for image transfer %s", r.transferID)
+			return
+		}
+		time.Sleep(time.Second)
+		getResponse, gErr := transfersService.ImageTransferService(r.transferID).Get().Send()
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		transfer = getResponse.MustImageTransfer()
+	}
+}
+
+// Download the disk data starting at the offset.
+func (r *diskTransfer) download(offset int64) (data io.ReadCloser, err error) {
+	request, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, r.size-1))
+	}
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return
+	}
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		if offset > 0 {
+			klog.Info("Range requests not supported, skipping the transferred data.")
+			if _, err = io.CopyN(io.Discard, response.Body, offset); err != nil {
+				response.Body.Close()
+				return
+			}
+		}
+	default:
+		response.Body.Close()
+		err = fmt.Errorf("image transfer %s failed: %s", r.transferID, response.Status)
+		return
+	}
+	data = response.Body
+	return
+}
+
+// Finalize the transfer when it succeeded, otherwise cancel it.
+func (r *diskTransfer) finish(succeeded bool) {
+	defer r.connection.Close()
+	if r.transferID == "" {
+		return
+	}
+	service := r.connection.SystemService().ImageTransfersService().ImageTransferService(r.transferID)
+	var err error
+	if succeeded {
+		_, err = service.Finalize().Send()
+	} else {
+		_, err = service.Cancel().Send()
+	}
+	if err != nil {
+		klog.Warning("Failed to end the image transfer: ", err)
+	}
+}
+
+func createHTTPClient(config *engineConfig) (client *http.Client, err error) {
+	if config.relayURL != "" {
+		// The relay verifies the ImageIO certificate.
+		var transport *relay.Transport
+		transport, err = relay.NewTransport(config.relayURL, config.relayCompression != relay.CompressionNone, relay.CertDir)
+		if err != nil {
+			return
+		}
+		klog.Info("Transferring through the relay: ", config.relayURL)
+		client = &http.Client{Transport: transport}
+		return
 	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.insecure}
+	if !config.insecure {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.cacert)) {
+			err = errors.New("failed to parse the CA certificate")
+			return
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	return
 }
 
-func monitorProgress(scanner *bufio.Scanner, ownerUID string, pvcSize int64, done chan struct{}) {
+func createProgressCounter() *prometheus.CounterVec {
 	progress := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "ovirt_progress",
@@ -116,25 +404,42 @@ func monitorProgress(scanner *bufio.Scanner, ownerUID string, pvcSize int64, don
 	)
 	if err := prometheus.Register(progress); err != nil {
 		klog.Error("Prometheus progress gauge not registered:", err)
-		return
 	}
+	return progress
+}
 
-	var currentProgress float64
-	total := pvcSize
-	metric := &dto.Metric{}
+func createCheckpointGauge() *prometheus.GaugeVec {
+	checkpoints := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ovirt_checkpoint",
+			Help: "Bytes stored in the volume",
+		},
+		[]string{"ownerUID", "state"},
+	)
+	if err := prometheus.Register(checkpoints); err != nil {
+		klog.Error("Prometheus checkpoint gauge not registered:", err)
+	}
+	return checkpoints
+}
 
-	for scanner.Scan() {
-		progressOutput := TransferProgress{}
-		text := scanner.Text()
-		klog.Info(text)
-		if err := json.Unmarshal([]byte(text), &progressOutput); err != nil {
-			var syntaxError *json.SyntaxError
-			if !errors.As(err, &syntaxError) {
+func monitorProgress(reader *CountingReader, progress *prometheus.CounterVec, ownerUID string, pvcSize int64, done chan bool) {
+	metric := &dto.Metric{}
+	for {
+		select {
+		case succeeded := <-done:
+			if !succeeded {
+				return
+			}
+			if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
 				klog.Error(err)
 			}
-		}
-		if total > 0 {
-			currentProgress = (float64(progressOutput.Transferred) / float64(total)) * 100
+			remaining := 100 - int64(metric.Counter.GetValue())
+			if remaining > 0 {
+				progress.WithLabelValues(ownerUID).Add(float64(remaining))
+			}
+			return
+		case <-time.After(time.Second):
+			currentProgress := (float64(reader.read.Load()) / float64(pvcSize)) * 100
 			if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
 				klog.Error(err)
 			} else if currentProgress > metric.Counter.GetValue() {
@@ -142,31 +447,6 @@ func monitorProgress(scanner *bufio.Scanner, ownerUID string, pvcSize int64, don
 			}
 		}
 	}
-
-	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
-		klog.Error(err)
-	}
-
-	remaining := 100 - int64(metric.Counter.GetValue())
-	if remaining > 0 {
-		progress.WithLabelValues(ownerUID).Add(float64(remaining))
-	}
-
-	done <- struct{}{}
-}
-
-func createCommandArguments(config *engineConfig, diskID, volPath string) []string {
-	var args []string
-	args = append(args, "download-disk", "--output", "json", "--engine-url="+config.URL, "--username="+config.username, "--password-file=/tmp/ovirt.pass")
-
-	if config.insecure {
-		args = append(args, "--insecure")
-	} else {
-		args = append(args, "--cafile=/tmp/ca.pem")
-	}
-
-	args = append(args, "-f", "raw", diskID, volPath)
-	return args
 }
 
 func loadEngineConfig(engineURL string) *engineConfig {
@@ -191,3 +471,14 @@ func getEnvAsBool(key string, defaultVal bool) bool {
 	}
 	return boolVal
 }
+
+type CountingReader struct {
+	reader io.Reader
+	read   atomic.Int64
+}
+
+func (cr *CountingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.read.Add(int64(n))
+	return n, err
+}
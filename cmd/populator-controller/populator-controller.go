@@ -4,9 +4,11 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	populator_machinery "github.com/konveyor/forklift-controller/pkg/lib-volume-populator/populator-machinery"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,6 +47,20 @@ var populators = map[string]populator{
 		imageVar:        "OPENSTACK_POPULATOR_IMAGE",
 		metricsEndpoint: ":8081",
 	},
+	"vsphere": {
+		kind:            "VSphereVolumePopulator",
+		resource:        "vspherevolumepopulators",
+		controllerFunc:  getVSpherePopulatorPodArgs,
+		imageVar:        "VSPHERE_POPULATOR_IMAGE",
+		metricsEndpoint: ":8082",
+	},
+	"http": {
+		kind:            "HttpVolumePopulator",
+		resource:        "httpvolumepopulators",
+		controllerFunc:  getHttpPopulatorPodArgs,
+		imageVar:        "HTTP_POPULATOR_IMAGE",
+		metricsEndpoint: ":8083",
+	},
 }
 
 func main() {
@@ -101,6 +117,8 @@ func getOvirtPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) ([]st
 	args = append(args, "--secret-name="+ovirtVolumePopulator.Spec.EngineSecretName)
 	args = append(args, "--disk-id="+ovirtVolumePopulator.Spec.DiskID)
 	args = append(args, "--engine-url="+ovirtVolumePopulator.Spec.EngineURL)
+	args = append(args, getRelayArgs(ovirtVolumePopulator.Spec.TransferRelay)...)
+	args = append(args, getRateLimitArgs(ovirtVolumePopulator.Spec.RateLimit)...)
 	args = append(args, "--cr-name="+ovirtVolumePopulator.Name)
 	args = append(args, "--cr-namespace="+ovirtVolumePopulator.Namespace)
 
@@ -118,12 +136,92 @@ func getOpenstackPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) (
 	args = append(args, "--endpoint="+openstackPopulator.Spec.IdentityURL)
 	args = append(args, "--secret-name="+openstackPopulator.Spec.SecretName)
 	args = append(args, "--image-id="+openstackPopulator.Spec.ImageID)
+	if openstackPopulator.Spec.Format != "" {
+		args = append(args, "--format="+openstackPopulator.Spec.Format)
+	}
+	args = append(args, getRelayArgs(openstackPopulator.Spec.TransferRelay)...)
+	args = append(args, getRateLimitArgs(openstackPopulator.Spec.RateLimit)...)
 	args = append(args, "--cr-name="+openstackPopulator.Name)
 	args = append(args, "--cr-namespace="+openstackPopulator.Namespace)
 
 	return args, nil
 }
 
+func getVSpherePopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) ([]string, error) {
+	var vspherePopulator v1beta1.VSphereVolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &vspherePopulator)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{}
+	args = append(args, "--volume-path="+getVolumePath(rawBlock))
+	args = append(args, "--server-url="+vspherePopulator.Spec.ServerURL)
+	args = append(args, "--secret-name="+vspherePopulator.Spec.SecretName)
+	args = append(args, "--vm-id="+vspherePopulator.Spec.VmID)
+	args = append(args, "--backing-file="+vspherePopulator.Spec.BackingFile)
+	if vspherePopulator.Spec.Thumbprint != "" {
+		args = append(args, "--thumbprint="+vspherePopulator.Spec.Thumbprint)
+	}
+	args = append(args, getRateLimitArgs(vspherePopulator.Spec.RateLimit)...)
+	args = append(args, "--cr-name="+vspherePopulator.Name)
+	args = append(args, "--cr-namespace="+vspherePopulator.Namespace)
+
+	return args, nil
+}
+
+func getHttpPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) ([]string, error) {
+	var httpPopulator v1beta1.HttpVolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &httpPopulator)
+	if err != nil {
+		return nil, err
+	}
+	format := httpPopulator.Spec.Format
+	if format == "" {
+		format = v1beta1.ImageFormatRaw
+	}
+	args := []string{}
+	args = append(args, "--volume-path="+getVolumePath(rawBlock))
+	args = append(args, "--url="+httpPopulator.Spec.URL)
+	args = append(args, "--format="+format)
+	if httpPopulator.Spec.SecretName != "" {
+		args = append(args, "--secret-name="+httpPopulator.Spec.SecretName)
+	}
+	if httpPopulator.Spec.Checksum != "" {
+		args = append(args, "--checksum="+httpPopulator.Spec.Checksum)
+	}
+	args = append(args, getRelayArgs(httpPopulator.Spec.TransferRelay)...)
+	args = append(args, getRateLimitArgs(httpPopulator.Spec.RateLimit)...)
+	args = append(args, "--cr-name="+httpPopulator.Name)
+	args = append(args, "--cr-namespace="+httpPopulator.Namespace)
+
+	return args, nil
+}
+
+func getRelayArgs(relay *plan.TransferRelay) []string {
+	if relay == nil {
+		return nil
+	}
+	compression := plan.RelayCompressionNone
+	if relay.Compressed() {
+		compression = plan.RelayCompressionZstd
+	}
+	return []string{
+		"--relay-url=" + relay.URL,
+		"--relay-compression=" + compression,
+	}
+}
+
+func getRateLimitArgs(limit *plan.RateLimit) []string {
+	if limit == nil {
+		return nil
+	}
+	args := []string{"--rate-limit=" + strconv.FormatInt(limit.Rate, 10)}
+	for _, window := range limit.Schedule {
+		args = append(args, "--rate-window="+window.String())
+	}
+	return args
+}
+
 func getVolumePath(rawBlock bool) string {
 	if rawBlock {
 		return devicePath
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/relay"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	var listen, certDir, allowedHosts, upstreamCA string
+	var upstreamInsecure bool
+	flag.StringVar(&listen, "listen", ":8443", "Address the relay listens on")
+	flag.StringVar(&certDir, "cert-dir", relay.CertDir, "Directory with the relay certificate (tls.crt, tls.key) and the client CA (ca.crt)")
+	flag.StringVar(&allowedHosts, "allowed-hosts", "", "Comma separated hosts the relay reads from (.example.com matches the subdomains)")
+	flag.StringVar(&upstreamCA, "upstream-cacert", "", "CA certificate file verifying the upstream servers")
+	flag.BoolVar(&upstreamInsecure, "upstream-insecure", false, "Skip the verification of the upstream servers")
+	flag.Parse()
+
+	if allowedHosts == "" {
+		klog.Fatal("allowed-hosts must be specified")
+	}
+	config, err := relay.ServerTLS(certDir)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	client, err := createUpstreamClient(upstreamCA, upstreamInsecure)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	server := &http.Server{
+		Addr: listen,
+		Handler: &relay.Server{
+			AllowedHosts: strings.Split(allowedHosts, ","),
+			Client:       client,
+		},
+		TLSConfig: config,
+	}
+	klog.Info("Relaying to: ", allowedHosts, " on: ", listen)
+	err = server.ListenAndServeTLS("", "")
+	if err != nil {
+		klog.Fatal(err)
+	}
+}
+
+func createUpstreamClient(caFile string, insecure bool) (client *http.Client, err error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		ca, rErr := os.ReadFile(caFile)
+		if rErr != nil {
+			err = rErr
+			return
+		}
+		pool, pErr := x509.SystemCertPool()
+		if pErr != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client = &http.Client{Transport: transport}
+	return
+}
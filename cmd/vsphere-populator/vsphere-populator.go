@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/ratelimit"
+	"github.com/konveyor/forklift-controller/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// VDDK library copied by the init container.
+	vddkLibDir = "/opt/vmware-vix-disklib-distrib"
+	// Password file passed to nbdkit.
+	passwordFile = "/tmp/vsphere.pass"
+	// Rate read by the nbdkit rate filter.
+	rateFile = "/tmp/rate"
+)
+
+// nbdcopy machine readable progress: "<percent>/100".
+var progressRegExp = regexp.MustCompile(`^(\d+)/100$`)
+
+type serverConfig struct {
+	host       string
+	username   string
+	password   string
+	thumbprint string
+}
+
+type AppConfig struct {
+	serverURL   string
+	thumbprint  string
+	vmID        string
+	backingFile string
+	volumePath  string
+	crName      string
+	crNamespace string
+	secretName  string
+	ownerUID    string
+	pvcSize     int64
+	rateLimit   *ratelimit.Schedule
+}
+
+func main() {
+	config := &AppConfig{rateLimit: &ratelimit.Schedule{}}
+	flag.StringVar(&config.serverURL, "server-url", "", "vCenter or ESXi SDK URL (https://vcenter.example.com/sdk)")
+	flag.StringVar(&config.thumbprint, "thumbprint", "", "SHA-1 thumbprint of the server certificate")
+	flag.StringVar(&config.vmID, "vm-id", "", "VM managed object reference")
+	flag.StringVar(&config.backingFile, "backing-file", "", "VMDK backing file ([datastore] vm/vm.vmdk)")
+	flag.StringVar(&config.volumePath, "volume-path", "", "Volume path to populate")
+	flag.StringVar(&config.secretName, "secret-name", "", "Name of secret containing vSphere credentials")
+	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
+	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
+	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Int64Var(&config.rateLimit.Default, "rate-limit", 0, "Transfer rate limit (bytes per second, 0 is not limited)")
+	flag.Var(config.rateLimit, "rate-window", "Transfer rate limit during a UTC time window ([Mon,...]/HH:MM-HH:MM=<bytes per second>)")
+	flag.Parse()
+
+	if config.pvcSize <= 0 {
+		klog.Fatal("pvc-size must be greater than 0")
+	}
+
+	certsDirectory, err := os.MkdirTemp("", "certsdir")
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	metrics.StartPrometheusEndpoint(certsDirectory)
+
+	populate(config)
+}
+
+func populate(config *AppConfig) {
+	server, err := loadServerConfig(config)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	writeFile(passwordFile, server.password)
+	prepareVolume(config.volumePath)
+	executePopulationProcess(server, config)
+}
+
+func loadServerConfig(config *AppConfig) (server *serverConfig, err error) {
+	u, err := url.Parse(config.serverURL)
+	if err != nil {
+		return
+	}
+	if u.Hostname() == "" {
+		err = fmt.Errorf("server URL '%s' has no host", config.serverURL)
+		return
+	}
+	server = &serverConfig{
+		host:       u.Hostname(),
+		username:   os.Getenv("user"),
+		password:   os.Getenv("password"),
+		thumbprint: config.thumbprint,
+	}
+	if server.thumbprint == "" {
+		server.thumbprint = os.Getenv("thumbprint")
+	}
+	return
+}
+
+func writeFile(filename, content string) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		klog.Fatalf("Failed to create %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write([]byte(content)); err != nil {
+		klog.Fatalf("Failed to write to %s: %v", filename, err)
+	}
+}
+
+// Create the image file on filesystem volumes.
+// Block devices already exist.
+func prepareVolume(volumePath string) {
+	if !strings.HasSuffix(volumePath, "disk.img") {
+		return
+	}
+	file, err := os.OpenFile(volumePath, os.O_RDWR|os.O_CREATE, 0650)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	_ = file.Close()
+}
+
+func executePopulationProcess(server *serverConfig, config *AppConfig) {
+	if err := config.rateLimit.Maintain(context.Background(), rateFile); err != nil {
+		klog.Fatal(err)
+	}
+	args := createCommandArguments(server, config)
+	cmd := exec.Command("nbdkit", args...)
+	r, _ := cmd.StdoutPipe()
+	cmd.Stderr = cmd.Stdout
+	done := make(chan struct{})
+	klog.Info(fmt.Sprintf("Running command: %s", cmd.String()))
+
+	go monitorProgress(r, createProgressCounter(), config.ownerUID, done)
+
+	if err := cmd.Start(); err != nil {
+		klog.Fatal(err)
+	}
+
+	<-done
+	if err := cmd.Wait(); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// Build the nbdkit arguments.
+// The VDDK plugin exposes the disk over a private socket
+// and nbdcopy streams it into the volume.
+func createCommandArguments(server *serverConfig, config *AppConfig) []string {
+	copyCmd := fmt.Sprintf("nbdcopy --progress=1 --flush $uri %s", config.volumePath)
+	options, params := config.rateLimit.NbdkitArgs(rateFile)
+	args := []string{
+		"--readonly",
+		"--exit-with-parent",
+		"--unix", "-",
+		"--run", copyCmd,
+	}
+	args = append(args, options...)
+	args = append(
+		args,
+		"vddk",
+		"libdir="+vddkLibDir,
+		"server="+server.host,
+		"user="+server.username,
+		"password=+"+passwordFile)
+	if server.thumbprint != "" {
+		args = append(args, "thumbprint="+server.thumbprint)
+	}
+	args = append(args, params...)
+	args = append(args, "vm=moref="+config.vmID, "file="+config.backingFile)
+	return args
+}
+
+func createProgressCounter() *prometheus.CounterVec {
+	progressVec := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vsphere_populator_progress",
+			Help: "Progress of volume population",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(progressVec); err != nil {
+		klog.Error("Prometheus progress counter not registered:", err)
+	}
+
+	return progressVec
+}
+
+func monitorProgress(r io.Reader, progress *prometheus.CounterVec, ownerUID string, done chan struct{}) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		percent, found := parseProgress(text)
+		if !found {
+			klog.Info(text)
+			continue
+		}
+		updateProgress(progress, ownerUID, percent)
+	}
+	updateProgress(progress, ownerUID, 100)
+	klog.Info("Finished populating the volume.")
+
+	done <- struct{}{}
+}
+
+// Parse a nbdcopy progress line.
+func parseProgress(line string) (percent float64, found bool) {
+	match := progressRegExp.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return
+	}
+	percent, err := strconv.ParseFloat(match[1], 64)
+	found = err == nil
+	return
+}
+
+// The progress counter only moves forward.
+func updateProgress(progress *prometheus.CounterVec, ownerUID string, percent float64) {
+	metric := &dto.Metric{}
+	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
+		klog.Error(err)
+		return
+	}
+	if percent > metric.Counter.GetValue() {
+		progress.WithLabelValues(ownerUID).Add(percent - metric.Counter.GetValue())
+		klog.Info("Progress: ", int64(percent), "%")
+	}
+}
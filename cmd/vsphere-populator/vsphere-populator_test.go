@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCreateCommandArguments(t *testing.T) {
+	server := &serverConfig{
+		host:       "esxi.example.com",
+		username:   "root",
+		thumbprint: "AA:BB",
+	}
+	config := &AppConfig{
+		vmID:        "vm-42",
+		backingFile: "[datastore1] vm/vm.vmdk",
+		volumePath:  "/dev/block",
+	}
+	args := createCommandArguments(server, config)
+	joined := strings.Join(args, " ")
+	for _, expected := range []string{
+		"--run nbdcopy --progress=1 --flush $uri /dev/block",
+		"server=esxi.example.com",
+		"user=root",
+		"password=+" + passwordFile,
+		"thumbprint=AA:BB",
+		"vm=moref=vm-42",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("expected %q in %q", expected, joined)
+		}
+	}
+	if args[len(args)-1] != "file=[datastore1] vm/vm.vmdk" {
+		t.Errorf("unexpected backing file argument: %s", args[len(args)-1])
+	}
+	for i, arg := range args {
+		if arg == "vddk" {
+			if i < 5 {
+				t.Errorf("nbdkit options must precede the plugin: %v", args)
+			}
+			break
+		}
+	}
+}
+
+func TestLoadServerConfig(t *testing.T) {
+	t.Setenv("user", "administrator@vsphere.local")
+	t.Setenv("password", "secret")
+	t.Setenv("thumbprint", "CC:DD")
+	server, err := loadServerConfig(&AppConfig{serverURL: "https://vcenter.example.com/sdk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.host != "vcenter.example.com" || server.thumbprint != "CC:DD" {
+		t.Errorf("unexpected server config: %+v", server)
+	}
+	if _, err = loadServerConfig(&AppConfig{serverURL: "/sdk"}); err == nil {
+		t.Error("expected error for URL without host")
+	}
+}
+
+func TestMonitorProgress(t *testing.T) {
+	progress := prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "test_progress"},
+		[]string{"ownerUID"},
+	)
+	output := strings.NewReader("0/100\nnbdkit: vddk: debug\n42/100\n17/100\n")
+	done := make(chan struct{}, 1)
+	monitorProgress(output, progress, "uid", done)
+	<-done
+
+	metric := &dto.Metric{}
+	if err := progress.WithLabelValues("uid").Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	if metric.Counter.GetValue() != 100 {
+		t.Errorf("expected 100, got %v", metric.Counter.GetValue())
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	if percent, found := parseProgress("42/100"); !found || percent != 42 {
+		t.Errorf("expected 42, got %v %v", percent, found)
+	}
+	if _, found := parseProgress("nbdkit: debug: 42/100"); found {
+		t.Error("unexpected match")
+	}
+}
@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+
+set -e
+
+bazeldnf_repos="--repofile rpm/stream9-repo.yaml"
+if [ "${CUSTOM_REPO}" ]; then
+    bazeldnf_repos="--repofile ${CUSTOM_REPO} ${bazeldnf_repos}"
+fi
+
+bazel run \
+    //:bazeldnf -- fetch \
+    ${bazeldnf_repos}
+
+converter_deps="
+qemu-img
+"
+
+bazel run \
+        //:bazeldnf -- rpmtree \
+        --public \
+        --nobest \
+        --buildfile cmd/http-populator/BUILD.bazel \
+        --name deps \
+        --basesystem centos-stream-release \
+        ${bazeldnf_repos} \
+        $converter_deps
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+
+set -e
+
+bazeldnf_repos="--repofile rpm/stream9-repo.yaml"
+if [ "${CUSTOM_REPO}" ]; then
+    bazeldnf_repos="--repofile ${CUSTOM_REPO} ${bazeldnf_repos}"
+fi
+
+bazel run \
+    //:bazeldnf -- fetch \
+    ${bazeldnf_repos}
+
+nbdkit_deps="
+nbdkit-server
+nbdkit-vddk-plugin
+libnbd
+"
+
+bazel run \
+        //:bazeldnf -- rpmtree \
+        --public \
+        --nobest \
+        --buildfile cmd/vsphere-populator/BUILD.bazel \
+        --name deps \
+        --basesystem centos-stream-release \
+        ${bazeldnf_repos} \
+        $nbdkit_deps
@@ -2,6 +2,7 @@ package v1beta1
 
 import (
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -10,13 +11,94 @@ type HookSpec struct {
 	// Service account.
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 	// Image to run.
-	Image string `json:"image"`
+	// Required unless the hook runs in the guest.
+	// +optional
+	Image string `json:"image,omitempty"`
 	// A base64 encoded Ansible playbook.
 	Playbook string `json:"playbook,omitempty"`
 	// Hook deadline in seconds.
+	// In-guest hooks default to 600.
+	// +optional
 	Deadline int64 `json:"deadline,omitempty"`
+	// Command to run.
+	// Replaces the image entrypoint (and the playbook).
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Command arguments.
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Environment variables.
+	// +optional
+	Env []core.EnvVar `json:"env,omitempty"`
+	// Secrets mounted (read-only) in the hook container.
+	// The secrets must exist in the namespace of the plan.
+	// +optional
+	Secrets []HookSecret `json:"secrets,omitempty"`
+	// Compute resources.
+	// +optional
+	Resources core.ResourceRequirements `json:"resources,omitempty"`
+	// Retry policy.
+	// +optional
+	Retry *HookRetry `json:"retry,omitempty"`
+	// Run a script inside the migrated VM using the
+	// qemu-guest-agent rather than a Job.
+	// +optional
+	Guest *GuestHook `json:"guest,omitempty"`
+}
+
+// In-guest hook.
+// The script is passed (stdin) to the interpreter
+// executed by the qemu-guest-agent. The hook fails when
+// the interpreter exits non-zero.
+type GuestHook struct {
+	// Script.
+	Script string `json:"script"`
+	// Interpreter command.
+	// Default: /bin/sh
+	// +optional
+	Interpreter []string `json:"interpreter,omitempty"`
+}
+
+// Interpreter command.
+func (r *GuestHook) Command() []string {
+	if len(r.Interpreter) > 0 {
+		return r.Interpreter
+	}
+	return []string{"/bin/sh"}
+}
+
+// Secret mounted in the hook container.
+type HookSecret struct {
+	// Secret name.
+	Name string `json:"name"`
+	// Mount path.
+	// Default: /tmp/secret/<name>
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
 }
 
+// Path the secret is mounted.
+func (r *HookSecret) Path() string {
+	if r.MountPath != "" {
+		return r.MountPath
+	}
+	return "/tmp/secret/" + r.Name
+}
+
+// Hook retry policy.
+type HookRetry struct {
+	// Number of retries before the hook is marked failed.
+	// Failed runs are retried with an exponential back-off
+	// (10s, 20s, 40s ...) capped at six minutes.
+	Limit int32 `json:"limit"`
+}
+
+// Output reported by a hook.
+// Lines written to stdout with this prefix followed by
+// a JSON object are merged into the VM hook outputs.
+// Example: forklift-output: {"ip": "10.0.0.1"}
+const HookOutputPrefix = "forklift-output:"
+
 // Hook status.
 type HookStatus struct {
 	// Conditions.
@@ -0,0 +1,98 @@
+package v1beta1
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var HttpVolumePopulatorKind = "HttpVolumePopulator"
+
+// Disk image formats.
+const (
+	ImageFormatRaw   = "raw"
+	ImageFormatQcow2 = "qcow2"
+	ImageFormatVmdk  = "vmdk"
+	ImageFormatVhd   = "vhd"
+	ImageFormatVhdx  = "vhdx"
+	ImageFormatVdi   = "vdi"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={hvp,hvps}
+type HttpVolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HttpVolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status HttpVolumePopulatorStatus `json:"status"`
+}
+
+type HttpVolumePopulatorSpec struct {
+	// The HTTP(S) URL of the disk image. Images in object storage
+	// are referenced by the S3 object URL, for example
+	// "https://bucket.s3.us-east-1.amazonaws.com/disk.qcow2".
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+	// The secret containing the S3 credentials (accessKeyId, secretKey,
+	// optional sessionToken and region) and the TLS settings
+	// (cacert, insecureSkipVerify).
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// The checksum of the image, "<algorithm>:<hex digest>"
+	// with the md5, sha1, sha256 or sha512 algorithm.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(md5|sha1|sha256|sha512):[0-9a-fA-F]+$`
+	Checksum string `json:"checksum,omitempty"`
+	// The format of the image, it is converted to raw.
+	// Images in other formats are converted while read
+	// (using range requests). Images with a checksum, read
+	// from S3 or through a relay are downloaded to the
+	// ephemeral storage of the populator pod first, limited
+	// to (and requested with) the size of the volume.
+	// +optional
+	// +kubebuilder:validation:Enum=raw;qcow2;vmdk;vhd;vhdx;vdi
+	Format string `json:"format,omitempty"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// The relay the disk data is read through.
+	// +optional
+	TransferRelay *plan.TransferRelay `json:"transferRelay,omitempty"`
+	// Rate limit of the disk transfer.
+	// +optional
+	RateLimit *plan.RateLimit `json:"rateLimit,omitempty"`
+}
+
+type HttpVolumePopulatorStatus struct {
+	// +optional
+	Progress string `json:"progress"`
+	// Bytes stored in the volume.
+	// A restarted populator resumes the transfer from it.
+	// +optional
+	Checkpoint int64 `json:"checkpoint,omitempty"`
+	// State of the hashes at the checkpoint (base64 encoded),
+	// the resumed transfer continues the digest from it.
+	// +optional
+	CheckpointState string `json:"checkpointState,omitempty"`
+	// Checksum of the data read from the source and stored
+	// in the volume, "<algorithm>:<hex digest>".
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+	// Bytes covered by the checksum.
+	// +optional
+	ChecksumSize int64 `json:"checksumSize,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HttpVolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []HttpVolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HttpVolumePopulator{}, &HttpVolumePopulatorList{})
+}
@@ -68,6 +68,9 @@ type MigrationStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// VM status
 	VMs []*plan.VMStatus `json:"vms,omitempty"`
+	// Disk transfer progress of the VMs.
+	// +optional
+	Transfer *plan.TransferProgress `json:"transfer,omitempty"`
 }
 
 // +genclient
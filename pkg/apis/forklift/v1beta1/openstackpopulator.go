@@ -1,6 +1,7 @@
 package v1beta1
 
 import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -24,13 +25,39 @@ type OpenstackVolumePopulatorSpec struct {
 	IdentityURL string `json:"identityUrl"`
 	SecretName  string `json:"secretName"`
 	ImageID     string `json:"imageId"`
+	// The format of the image, it is converted to raw
+	// while the volume is populated.
+	// +optional
+	// +kubebuilder:validation:Enum=raw;qcow2;vmdk;vhd;vhdx;vdi
+	Format string `json:"format,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// The relay the disk data is read through.
+	// +optional
+	TransferRelay *plan.TransferRelay `json:"transferRelay,omitempty"`
+	// Rate limit of the disk transfer.
+	// +optional
+	RateLimit *plan.RateLimit `json:"rateLimit,omitempty"`
 }
 
 type OpenstackVolumePopulatorStatus struct {
 	// +optional
 	Progress string `json:"progress"`
+	// Bytes stored in the volume.
+	// A restarted populator resumes the transfer from it.
+	// +optional
+	Checkpoint int64 `json:"checkpoint,omitempty"`
+	// State of the hashes at the checkpoint (base64 encoded),
+	// the resumed transfer continues the digest from it.
+	// +optional
+	CheckpointState string `json:"checkpointState,omitempty"`
+	// Checksum of the data read from the source and stored
+	// in the volume, "<algorithm>:<hex digest>".
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+	// Bytes covered by the checksum.
+	// +optional
+	ChecksumSize int64 `json:"checksumSize,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
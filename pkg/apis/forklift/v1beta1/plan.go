@@ -24,6 +24,7 @@ import (
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // PlanSpec defines the desired state of Plan.
@@ -42,12 +43,54 @@ type PlanSpec struct {
 	Warm bool `json:"warm,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// Read the disks through a transfer relay deployed next to the source.
+	// The relay is deployed by the administrator at the source site, the
+	// populator pods reach it on the transfer network when set.
+	// Used only by the oVirt and OpenStack volume populators, the disks
+	// copied by CDI or virt-v2v are transferred directly (with a warning).
+	// +optional
+	TransferRelay *plan.TransferRelay `json:"transferRelay,omitempty"`
+	// Bandwidth limit of the disk transfers.
+	// Applied by the volume populators and virt-v2v, plans with
+	// disks copied by CDI are rejected.
+	// +optional
+	BandwidthLimit *plan.BandwidthLimit `json:"bandwidthLimit,omitempty"`
 	// Whether this plan should be archived.
 	Archived bool `json:"archived,omitempty"`
 	// Preserve the CPU model and flags the VM runs with in its oVirt cluster.
 	PreserveClusterCPUModel bool `json:"preserveClusterCpuModel,omitempty"`
 	// Preserve static IPs of VMs in vSphere (Windows only)
 	PreserveStaticIPs bool `json:"preserveStaticIPs,omitempty"`
+	// Plan-level hooks.
+	// Run once for the plan: the PreHook before the first VM
+	// is migrated; the PostHook after the last VM completes or fails.
+	// +optional
+	Hooks []plan.HookRef `json:"hooks,omitempty"`
+	// Verify the migrated VMs boot and pass health checks.
+	// +optional
+	Verify *plan.Verify `json:"verify,omitempty"`
+	// Verify the checksum of the disks transferred by volume
+	// populators or CDI matches the source before the migration
+	// continues. Not supported by warm migrations or when the
+	// disks are copied by virt-v2v.
+	// +optional
+	VerifyIntegrity bool `json:"verifyIntegrity,omitempty"`
+	// Test failover (rehearsal) mode.
+	// +optional
+	Test *plan.TestFailover `json:"test,omitempty"`
+}
+
+// Find a plan-level hook for the specified step.
+func (r *PlanSpec) FindHook(step string) (ref plan.HookRef, found bool) {
+	for _, h := range r.Hooks {
+		if h.Step == step {
+			found = true
+			ref = h
+			break
+		}
+	}
+
+	return
 }
 
 // Find a planned VM.
@@ -72,6 +115,25 @@ type PlanStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Migration
 	Migration plan.MigrationStatus `json:"migration,omitempty"`
+	// Concerns reported for the VMs that have been acknowledged.
+	// Recorded for audit.
+	// +optional
+	WaivedConcerns []plan.WaivedConcern `json:"waivedConcerns,omitempty"`
+	// Test failover (rehearsal) status.
+	// +optional
+	Rehearsal plan.MigrationStatus `json:"rehearsal,omitempty"`
+}
+
+// Find the migration status with the snapshot for the migration.
+// Rehearsals are recorded separately from migrations.
+func (r *PlanStatus) FindMigration(uid types.UID) (status *plan.MigrationStatus) {
+	if found, _ := r.Rehearsal.SnapshotWithMigration(uid); found {
+		status = &r.Rehearsal
+	} else {
+		status = &r.Migration
+	}
+
+	return
 }
 
 // +genclient
@@ -91,6 +153,8 @@ type Plan struct {
 	// Referenced resources populated
 	// during validation.
 	Referenced `json:"-"`
+	// The plan is being rehearsed.
+	rehearsing bool `json:"-"`
 }
 
 // Decide if the plan should use EL9 image for virt-v2v conversion.
@@ -107,7 +171,7 @@ func (p *Plan) VSphereUsesEl9VirtV2v() (bool, error) {
 
 	switch source.Type() {
 	case VSphere:
-		return !p.Spec.Warm && destination.IsHost(), nil
+		return !p.SnapshotCopy() && !source.UsesVolumePopulator() && !source.UsesStorageOffload() && destination.IsHost(), nil
 	case Ova:
 		return true, nil
 	default:
@@ -115,6 +179,46 @@ func (p *Plan) VSphereUsesEl9VirtV2v() (bool, error) {
 	}
 }
 
+// The disks are copied from a snapshot of the source VM.
+// Warm migrations and test failovers.
+func (p *Plan) SnapshotCopy() bool {
+	return p.Spec.Warm || p.Spec.Test != nil
+}
+
+// Rehearse the plan.
+// In test failover mode, the rehearsal status replaces the migration
+// status and the test namespace and network map replace the target
+// namespace and network map. Returns a function that restores the plan.
+func (p *Plan) Rehearse() (restore func()) {
+	restore = func() {}
+	test := p.Spec.Test
+	if test == nil {
+		return
+	}
+	namespace := p.Spec.TargetNamespace
+	network := p.Referenced.Map.Network
+	p.Status.Migration, p.Status.Rehearsal = p.Status.Rehearsal, p.Status.Migration
+	p.Spec.TargetNamespace = test.Namespace
+	if p.Referenced.Map.TestNetwork != nil {
+		p.Referenced.Map.Network = p.Referenced.Map.TestNetwork
+	}
+	p.rehearsing = true
+	restore = func() {
+		p.Status.Migration, p.Status.Rehearsal = p.Status.Rehearsal, p.Status.Migration
+		p.Spec.TargetNamespace = namespace
+		p.Referenced.Map.Network = network
+		p.rehearsing = false
+	}
+
+	return
+}
+
+// The plan is being rehearsed.
+// Set between Rehearse() and the restore.
+func (p *Plan) Rehearsing() bool {
+	return p.rehearsing
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type PlanList struct {
 	meta.TypeMeta `json:",inline"`
@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Bandwidth limit of the disk transfers.
+// The limits are in bytes per second (e.g. 100Mi). The plan limit
+// is split evenly between the VMs migrated concurrently and the VM
+// limit between the disks of the VM. The split is made when the
+// transfers start, it is a best-effort share of the plan limit.
+type BandwidthLimit struct {
+	// Limit of the disk transfers of the plan.
+	// +optional
+	Total *resource.Quantity `json:"total,omitempty"`
+	// Limit of the disk transfers of each VM.
+	// +optional
+	PerVM *resource.Quantity `json:"perVM,omitempty"`
+	// Limits replacing the above during time windows.
+	// The first window containing the current time is used.
+	// +optional
+	Schedule []BandwidthWindow `json:"schedule,omitempty"`
+}
+
+// Limit of each disk transfer of a VM.
+// vms: the number of VMs migrated concurrently.
+// disks: the number of disks of the VM.
+func (r *BandwidthLimit) RateLimit(vms, disks int) (limit *RateLimit) {
+	limit = &RateLimit{
+		Rate: diskRate(r.Total, r.PerVM, vms, disks),
+	}
+	for _, window := range r.Schedule {
+		limit.Schedule = append(
+			limit.Schedule,
+			RateWindow{
+				TimeWindow: window.TimeWindow,
+				Rate:       diskRate(window.Total, window.PerVM, vms, disks),
+			})
+	}
+	return
+}
+
+// Bandwidth limit during a time window.
+// Unset limits are not limited during the window.
+type BandwidthWindow struct {
+	TimeWindow `json:",inline"`
+	// Limit of the disk transfers of the plan.
+	// +optional
+	Total *resource.Quantity `json:"total,omitempty"`
+	// Limit of the disk transfers of each VM.
+	// +optional
+	PerVM *resource.Quantity `json:"perVM,omitempty"`
+}
+
+// Time window (UTC).
+type TimeWindow struct {
+	// Start time (HH:MM).
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End time (HH:MM).
+	// The window ends the next day when the end is not after the start.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+	// Days the window starts.
+	// Default: every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+}
+
+// Day of the week.
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// Rate limit of a disk transfer.
+type RateLimit struct {
+	// Bytes per second. Zero is not limited.
+	// +optional
+	Rate int64 `json:"rate,omitempty"`
+	// Rates replacing the above during time windows.
+	// +optional
+	Schedule []RateWindow `json:"schedule,omitempty"`
+}
+
+// Rate limit during a time window.
+type RateWindow struct {
+	TimeWindow `json:",inline"`
+	// Bytes per second. Zero is not limited.
+	// +optional
+	Rate int64 `json:"rate,omitempty"`
+}
+
+// Format as [<day>,...]/<HH:MM>-<HH:MM>=<rate>.
+func (r *RateWindow) String() string {
+	days := make([]string, 0, len(r.Days))
+	for _, day := range r.Days {
+		days = append(days, string(day))
+	}
+	return fmt.Sprintf("%s/%s-%s=%d", strings.Join(days, ","), r.Start, r.End, r.Rate)
+}
+
+// Rate of a disk transfer.
+func diskRate(total, perVM *resource.Quantity, vms, disks int) (rate int64) {
+	if vms < 1 {
+		vms = 1
+	}
+	if disks < 1 {
+		disks = 1
+	}
+	if perVM != nil && perVM.Value() > 0 {
+		rate = perVM.Value()
+	}
+	if total != nil && total.Value() > 0 {
+		share := total.Value() / int64(vms)
+		if rate == 0 || share < rate {
+			rate = share
+		}
+	}
+	if rate > 0 {
+		rate = max(rate/int64(disks), 1)
+	}
+	return
+}
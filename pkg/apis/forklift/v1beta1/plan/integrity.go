@@ -0,0 +1,38 @@
+package plan
+
+// Disk integrity.
+// The source checksum is computed over the data read from
+// the source disk by the volume populator, or by a pod reading
+// the source of the DataVolume, and the target checksum over
+// the same bytes of the target PVC.
+type DiskIntegrity struct {
+	// The target PVC name.
+	PVC string `json:"pvc"`
+	// Checksum algorithm.
+	Algorithm string `json:"algorithm"`
+	// Bytes covered by the checksums.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Checksum of the source disk.
+	// +optional
+	Source string `json:"source,omitempty"`
+	// Checksum of the target PVC.
+	// +optional
+	Target string `json:"target,omitempty"`
+	// The checksums match.
+	// +optional
+	Verified bool `json:"verified,omitempty"`
+}
+
+// Find the integrity of a disk by PVC name.
+func (r *VMStatus) FindIntegrity(pvc string) (integrity *DiskIntegrity, found bool) {
+	for i := range r.Integrity {
+		if r.Integrity[i].PVC == pvc {
+			integrity = &r.Integrity[i]
+			found = true
+			break
+		}
+	}
+
+	return
+}
@@ -1,6 +1,8 @@
 package plan
 
 import (
+	"time"
+
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +39,37 @@ type MigrationStatus struct {
 	History []Snapshot `json:"history,omitempty"`
 	// VM status
 	VMs []*VMStatus `json:"vms,omitempty"`
+	// Plan-level hook status.
+	Hooks []*Step `json:"hooks,omitempty"`
+	// Output reported by plan-level hooks.
+	HookOutputs map[string]string `json:"hookOutputs,omitempty"`
+	// Disk transfer progress of the VMs.
+	// +optional
+	Transfer *TransferProgress `json:"transfer,omitempty"`
+}
+
+// Reflect the disk transfer progress of the VMs.
+// The VMs not transferring yet are expected to
+// be seeded with the size of their disks.
+func (r *MigrationStatus) ReflectTransfers(now time.Time) {
+	transfers := []*TransferProgress{}
+	for _, vm := range r.VMs {
+		transfers = append(transfers, vm.Transfer)
+	}
+	r.Transfer = Rollup(transfers, now)
+}
+
+// Find plan-level hook status by step name.
+func (r *MigrationStatus) FindHook(name string) (step *Step, found bool) {
+	for _, s := range r.Hooks {
+		if s.Name == name {
+			found = true
+			step = s
+			break
+		}
+	}
+
+	return
 }
 
 // The active snapshot.
@@ -125,6 +158,20 @@ func (r *Step) ReflectTasks() {
 	}
 }
 
+// Reflect the disk transfer progress of the tasks.
+// The tasks not transferring yet count their size.
+func (r *Step) ReflectTransfers(now time.Time) {
+	transfers := []*TransferProgress{}
+	for _, task := range r.Tasks {
+		transfer := task.Transfer
+		if transfer == nil {
+			transfer = &TransferProgress{BytesTotal: task.Progress.Total * MB}
+		}
+		transfers = append(transfers, transfer)
+	}
+	r.Transfer = Rollup(transfers, now)
+}
+
 // Migration task.
 type Task struct {
 	Timed `json:",inline"`
@@ -138,6 +185,9 @@ type Task struct {
 	Reason string `json:"reason,omitempty"`
 	// Progress.
 	Progress libitr.Progress `json:"progress"`
+	// Disk transfer progress.
+	// +optional
+	Transfer *TransferProgress `json:"transfer,omitempty"`
 	// Annotations.
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// Error.
@@ -152,6 +202,30 @@ func (r *Task) AddError(reason ...string) {
 	r.Error.Add(reason...)
 }
 
+// Update the disk transfer progress with the
+// bytes reported by the transfer.
+func (r *Task) UpdateTransfer(completed, total int64, now time.Time) {
+	if r.Transfer == nil {
+		r.Transfer = &TransferProgress{}
+	}
+	r.Transfer.Update(min(completed, total), total, now)
+}
+
+// Reflect the progress (MB) of a disk transfer task
+// when the transfer reports no byte counts.
+func (r *Task) ReflectTransfer(now time.Time) {
+	r.UpdateTransfer(r.Progress.Completed*MB, r.Progress.Total*MB, now)
+}
+
+// Complete the disk transfer progress.
+func (r *Task) CompleteTransfer(now time.Time) {
+	total := r.Progress.Total * MB
+	if r.Transfer != nil && r.Transfer.BytesTotal > 0 {
+		total = r.Transfer.BytesTotal
+	}
+	r.UpdateTransfer(total, total, now)
+}
+
 // Return whether the task has an error.
 func (r *Task) HasError() bool {
 	return r.Error != nil
@@ -0,0 +1,62 @@
+package plan
+
+// Clone phases.
+const (
+	ClonePending   = "Pending"
+	CloneRunning   = "Running"
+	CloneCompleted = "Completed"
+	CloneFailed    = "Failed"
+)
+
+// Storage offload.
+// The disks cloned by the storage array. Recorded when the
+// disks are first evaluated so that the array is asked once
+// which disks it can clone.
+type Offload struct {
+	// Array driver.
+	Driver string `json:"driver"`
+	// Disks cloned by the array.
+	// +optional
+	Disks []OffloadedDisk `json:"disks,omitempty"`
+}
+
+// Disk cloned by the storage array.
+type OffloadedDisk struct {
+	// Volume name on the array.
+	Name string `json:"name"`
+	// Source disk (base) file.
+	File string `json:"file"`
+	// Target storage class.
+	StorageClass string `json:"storageClass"`
+	// Datastore devices (NAA IDs).
+	// +optional
+	Devices []string `json:"devices,omitempty"`
+	// Capacity in bytes.
+	Capacity int64 `json:"capacity"`
+	// Bytes cloned.
+	// +optional
+	Completed int64 `json:"completed,omitempty"`
+	// Clone phase.
+	Phase string `json:"phase"`
+	// Error reported by the array.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// Find an offloaded disk by file.
+func (r *Offload) FindDisk(file string) (disk *OffloadedDisk, found bool) {
+	for i := range r.Disks {
+		if r.Disks[i].File == file {
+			disk = &r.Disks[i]
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+// The clone has ended.
+func (r *OffloadedDisk) Ended() bool {
+	return r.Phase == CloneCompleted || r.Phase == CloneFailed
+}
@@ -0,0 +1,30 @@
+package plan
+
+// Relay compression.
+const (
+	RelayCompressionZstd = "zstd"
+	RelayCompressionNone = "none"
+)
+
+// Transfer relay.
+// The disk data is read from the source by a relay deployed
+// next to the source and streamed to the volume populators over
+// mutual TLS, compressed with zstd by default.
+type TransferRelay struct {
+	// Relay URL (https://host:port) reachable on the transfer network.
+	URL string `json:"url"`
+	// Name of the secret in the target namespace holding the
+	// client certificate (tls.crt, tls.key) and the CA (ca.crt)
+	// that signed the relay certificate.
+	Secret string `json:"secret"`
+	// Compression of the stream.
+	// Default: zstd.
+	// +kubebuilder:validation:Enum=zstd;none
+	// +optional
+	Compression string `json:"compression,omitempty"`
+}
+
+// The stream is compressed.
+func (r *TransferRelay) Compressed() bool {
+	return r.Compression != RelayCompressionNone
+}
@@ -0,0 +1,25 @@
+package plan
+
+import core "k8s.io/api/core/v1"
+
+// Test failover.
+// Rehearse the migration: a copy of each VM is migrated from a
+// crash-consistent snapshot while the source VM keeps running.
+// The source VM is not powered off and only the snapshot taken
+// by the rehearsal is removed. Rehearsal results are recorded
+// separately from the migration status.
+type TestFailover struct {
+	// Namespace the test VMs are created in.
+	// Must not be the plan target namespace.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Network map used for the test VMs.
+	// Maps the source networks to isolated test networks;
+	// the pod network and the networks mapped by the plan
+	// network map are not allowed.
+	Network *core.ObjectReference `json:"network"`
+	// Delete the test VMs and their disks.
+	// Rehearsals are not run while set.
+	// +optional
+	Cleanup bool `json:"cleanup,omitempty"`
+}
@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Bytes in the MB the disk transfer tasks are counted in.
+const MB = 0x100000
+
+// Weight of the latest measured rate in the moving average.
+const rateWeight = 0.5
+
+// Shortest interval (seconds) the rate is measured over.
+const rateInterval = 1.0
+
+// Progress of disk transfers.
+type TransferProgress struct {
+	// Bytes transferred.
+	BytesCompleted int64 `json:"bytesCompleted"`
+	// Bytes to transfer.
+	BytesTotal int64 `json:"bytesTotal"`
+	// Transfer rate (bytes per second).
+	// +optional
+	Rate int64 `json:"rate,omitempty"`
+	// Estimated time the transfer completes.
+	// +optional
+	ETA *meta.Time `json:"eta,omitempty"`
+	// Time the progress was last updated.
+	// +optional
+	Updated *meta.Time `json:"updated,omitempty"`
+}
+
+// Update the bytes transferred at the time. The rate is a
+// moving average of the rates measured between the updates.
+func (r *TransferProgress) Update(completed, total int64, now time.Time) {
+	r.BytesTotal = total
+	switch {
+	case r.Updated == nil, completed < r.BytesCompleted:
+		// First update or restarted transfer.
+		r.BytesCompleted = completed
+		r.Rate = 0
+		r.Updated = &meta.Time{Time: now}
+	case completed >= total:
+		r.BytesCompleted = completed
+		r.Rate = 0
+		r.Updated = &meta.Time{Time: now}
+	default:
+		elapsed := now.Sub(r.Updated.Time).Seconds()
+		if elapsed < rateInterval {
+			return
+		}
+		measured := float64(completed-r.BytesCompleted) / elapsed
+		if r.Rate == 0 {
+			r.Rate = int64(measured)
+		} else {
+			r.Rate = int64(rateWeight*measured + (1-rateWeight)*float64(r.Rate))
+		}
+		r.BytesCompleted = completed
+		r.Updated = &meta.Time{Time: now}
+	}
+	r.estimate(now)
+}
+
+// Remaining bytes.
+func (r *TransferProgress) Remaining() int64 {
+	return max(r.BytesTotal-r.BytesCompleted, 0)
+}
+
+// Estimate the completion time from the rate.
+func (r *TransferProgress) estimate(now time.Time) {
+	remaining := r.Remaining()
+	if r.Rate <= 0 || remaining == 0 {
+		r.ETA = nil
+		return
+	}
+	seconds := time.Duration(float64(remaining)/float64(r.Rate)) * time.Second
+	r.ETA = &meta.Time{Time: now.Add(seconds).Truncate(time.Second)}
+}
+
+// Rollup of concurrent transfers.
+// The rates are summed and the completion
+// is estimated from the summed rate.
+// Returns nil when there are no transfers.
+func Rollup(transfers []*TransferProgress, now time.Time) (rollup *TransferProgress) {
+	for _, transfer := range transfers {
+		if transfer == nil {
+			continue
+		}
+		if rollup == nil {
+			rollup = &TransferProgress{}
+		}
+		rollup.BytesCompleted += transfer.BytesCompleted
+		rollup.BytesTotal += transfer.BytesTotal
+		rollup.Rate += transfer.Rate
+		if transfer.Updated != nil && (rollup.Updated == nil || transfer.Updated.After(rollup.Updated.Time)) {
+			rollup.Updated = transfer.Updated.DeepCopy()
+		}
+	}
+	if rollup != nil {
+		rollup.estimate(now)
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	"github.com/onsi/gomega"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func transferStep() *Step {
+	return &Step{
+		Task: Task{Name: "DiskTransfer"},
+		Tasks: []*Task{
+			{Name: "disk1", Progress: libitr.Progress{Total: 1000}},
+			{Name: "disk2", Progress: libitr.Progress{Total: 1000}},
+		},
+	}
+}
+
+func TestTransferRate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	step := transferStep()
+
+	step.ReflectTransfers(start)
+	g.Expect(step.Transfer.BytesTotal).To(gomega.Equal(int64(2000 * MB)))
+	g.Expect(step.Transfer.Rate).To(gomega.BeZero())
+	g.Expect(step.Transfer.ETA).To(gomega.BeNil())
+
+	step.Tasks[0].UpdateTransfer(0, 1000*MB, start)
+	step.Tasks[1].UpdateTransfer(0, 1000*MB, start)
+	now := start.Add(10 * time.Second)
+	step.Tasks[0].UpdateTransfer(100*MB, 1000*MB, now)
+	step.Tasks[1].UpdateTransfer(100*MB, 1000*MB, now)
+	step.ReflectTransfers(now)
+	g.Expect(step.Tasks[0].Transfer.Rate).To(gomega.Equal(int64(10 * MB)))
+	g.Expect(step.Transfer.BytesCompleted).To(gomega.Equal(int64(200 * MB)))
+	g.Expect(step.Transfer.Rate).To(gomega.Equal(int64(20 * MB)))
+	g.Expect(step.Transfer.ETA.Time).To(gomega.Equal(now.Add(90 * time.Second)))
+}
+
+func TestTransferAverage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	task := &Task{Progress: libitr.Progress{Total: 1000}}
+
+	task.ReflectTransfer(start)
+	task.Progress.Completed = 100
+	task.ReflectTransfer(start.Add(10 * time.Second))
+	task.Progress.Completed = 400
+	task.ReflectTransfer(start.Add(20 * time.Second))
+	g.Expect(task.Transfer.Rate).To(gomega.Equal(int64(20 * MB)))
+}
+
+func TestTransferCompleted(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	task := &Task{Progress: libitr.Progress{Total: 1000}}
+
+	task.UpdateTransfer(0, 1500*MB, start)
+	task.CompleteTransfer(start.Add(10 * time.Second))
+	g.Expect(task.Transfer.BytesCompleted).To(gomega.Equal(int64(1500 * MB)))
+	g.Expect(task.Transfer.Rate).To(gomega.BeZero())
+	g.Expect(task.Transfer.ETA).To(gomega.BeNil())
+
+	// The reported bytes do not exceed the total.
+	task.UpdateTransfer(2000*MB, 1500*MB, start.Add(20*time.Second))
+	g.Expect(task.Transfer.Remaining()).To(gomega.BeZero())
+}
+
+func TestTransferRestarted(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	task := &Task{Progress: libitr.Progress{Total: 1000}}
+
+	task.UpdateTransfer(500*MB, 1000*MB, start)
+	task.UpdateTransfer(100*MB, 1000*MB, start.Add(10*time.Second))
+	g.Expect(task.Transfer.BytesCompleted).To(gomega.Equal(int64(100 * MB)))
+	g.Expect(task.Transfer.Rate).To(gomega.BeZero())
+}
+
+func TestTransferRollup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	step := transferStep()
+	step.Tasks[0].UpdateTransfer(250*MB, 1000*MB, start)
+	step.ReflectTransfers(start)
+	vm1 := &VMStatus{Transfer: step.Transfer.DeepCopy()}
+	vm2 := &VMStatus{}
+
+	status := &MigrationStatus{VMs: []*VMStatus{vm1, vm2}}
+	status.ReflectTransfers(start)
+	g.Expect(status.Transfer.BytesCompleted).To(gomega.Equal(int64(250 * MB)))
+	g.Expect(status.Transfer.BytesTotal).To(gomega.Equal(int64(2000 * MB)))
+}
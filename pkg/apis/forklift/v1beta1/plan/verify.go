@@ -0,0 +1,54 @@
+package plan
+
+// Post-migration verification.
+// The target VM is started (temporarily when the restored
+// power state is not On) and must reach Running within the
+// deadline and pass the enabled checks.
+type Verify struct {
+	// Deadline in seconds.
+	// Default: 600.
+	// +optional
+	Deadline int64 `json:"deadline,omitempty"`
+	// Wait for the guest agent to connect and report
+	// the hostname and IP addresses. The hostname and the
+	// (preserved) static IPs are compared with the source
+	// when known.
+	// +optional
+	GuestAgent bool `json:"guestAgent,omitempty"`
+	// Probe the VM (primary) IP address.
+	// The probe runs in a pod in the target namespace.
+	// +optional
+	Probe *VerifyProbe `json:"probe,omitempty"`
+	// Failure policy.
+	// Fail: the VM migration fails.
+	// Continue: the VM is flagged with a warning.
+	// Default: Fail.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +optional
+	OnFailure string `json:"onFailure,omitempty"`
+}
+
+// Deadline in seconds.
+func (r *Verify) DeadlineSeconds() int64 {
+	if r.Deadline > 0 {
+		return r.Deadline
+	}
+	return 600
+}
+
+// The migration continues when verification fails.
+func (r *Verify) ContinueOnFailure() bool {
+	return r.OnFailure == HookContinue
+}
+
+// Verification probe.
+// TCP connect unless the HTTP path is specified.
+type VerifyProbe struct {
+	// Port.
+	Port int32 `json:"port"`
+	// HTTP path.
+	// Absolute, without control or quote characters.
+	// The GET response status must be 2xx or 3xx.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
@@ -10,12 +10,29 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Hook failure policies.
+const (
+	// The VM migration fails.
+	HookFail = "Fail"
+	// The VM migration continues with a warning.
+	HookContinue = "Continue"
+)
+
 // Plan hook.
 type HookRef struct {
 	// Pipeline step.
 	Step string `json:"step"`
 	// Hook reference.
 	Hook core.ObjectReference `json:"hook" ref:"Hook"`
+	// Failure policy. Default: Fail.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +optional
+	OnFailure string `json:"onFailure,omitempty"`
+}
+
+// The migration continues when the hook fails.
+func (r *HookRef) ContinueOnFailure() bool {
+	return r.OnFailure == HookContinue
 }
 
 func (r *HookRef) String() string {
@@ -39,6 +56,21 @@ type VM struct {
 	// Selected InstanceType that will override the VM properties.
 	// +optional
 	InstanceType string `json:"instanceType,omitempty"`
+	// IDs of the (inventory) concerns acknowledged for the VM.
+	// Critical concerns block the plan unless acknowledged.
+	// +optional
+	AcknowledgedConcerns []string `json:"acknowledgedConcerns,omitempty"`
+}
+
+// Determine whether a concern has been acknowledged.
+func (r *VM) Acknowledged(id string) bool {
+	for _, ack := range r.AcknowledgedConcerns {
+		if ack == id {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Find a Hook for the specified step.
@@ -54,6 +86,18 @@ func (r *VM) FindHook(step string) (ref HookRef, found bool) {
 	return
 }
 
+// A concern acknowledged (waived) for a VM.
+type WaivedConcern struct {
+	// VM reference.
+	VM ref.Ref `json:"vm"`
+	// Concern ID.
+	ID string `json:"id"`
+	// Concern label.
+	Label string `json:"label,omitempty"`
+	// Concern category.
+	Category string `json:"category,omitempty"`
+}
+
 // VM Status
 type VMStatus struct {
 	Timed `json:",inline"`
@@ -72,6 +116,19 @@ type VMStatus struct {
 	Firmware string `json:"firmware,omitempty"`
 	// The Operating System detected by virt-v2v.
 	OperatingSystem string `json:"operatingSystem,omitempty"`
+	// Output reported by hooks.
+	// Available to the hooks run by later steps.
+	// +optional
+	HookOutputs map[string]string `json:"hookOutputs,omitempty"`
+	// Integrity of the transferred disks.
+	// +optional
+	Integrity []DiskIntegrity `json:"integrity,omitempty"`
+	// Disk transfer progress.
+	// +optional
+	Transfer *TransferProgress `json:"transfer,omitempty"`
+	// Disks cloned by the storage array.
+	// +optional
+	Offload *Offload `json:"offload,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strconv"
+	"strings"
+
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -63,6 +66,29 @@ const (
 	SDK     = "sdkEndpoint"
 	VCenter = "vcenter"
 	ESXI    = "esxi"
+	// Collection scope.
+	// Comma-separated list restricting the collected inventory
+	// and the VMs that may be migrated.
+	//   - vSphere: inventory paths of datacenters, clusters and
+	//     VM folders. Example: /dc1,/dc2/host/cluster1,/dc2/vm/team-a
+	//   - oVirt: cluster names (or IDs).
+	//   - OpenStack: project names (or IDs).
+	Scope = "scope"
+	// Max objects retrieved in each (collection) page.
+	PageSize = "pageSize"
+	// vSphere: copy the disks of cold migrations using the
+	// VSphereVolumePopulator (requires the VDDK image).
+	VolumePopulator = "useVolumePopulator"
+	// vSphere: name of the storage array driver cloning the
+	// disks of cold migrations on the array when the datastore
+	// and the storage class are on the same array. The disks
+	// the array cannot clone are copied. Used when the storage
+	// offload feature is enabled and the driver is registered
+	// with the controller, the disks are copied otherwise.
+	StorageOffload = "storageOffload"
+	// vSphere: name of the secret (in the provider namespace)
+	// with the settings and credentials of the storage array.
+	StorageOffloadSecret = "storageOffloadSecret"
 )
 
 const OvaProviderFinalizer = "forklift/ova-provider"
@@ -147,3 +173,28 @@ func (p *Provider) HasReconciled() bool {
 func (p *Provider) RequiresConversion() bool {
 	return p.Type() == VSphere || p.Type() == Ova
 }
+
+// The disks are transferred by a volume populator.
+// Parsed from the `useVolumePopulator` setting.
+func (p *Provider) UsesVolumePopulator() bool {
+	use, _ := strconv.ParseBool(p.Spec.Settings[VolumePopulator])
+	return use
+}
+
+// The disks are cloned by the storage array when supported.
+// Set by the `storageOffload` setting.
+func (p *Provider) UsesStorageOffload() bool {
+	return p.Spec.Settings[StorageOffload] != ""
+}
+
+// The collection scope.
+// Parsed from the `scope` setting.
+func (p *Provider) Scope() (scope []string) {
+	for _, part := range strings.Split(p.Spec.Settings[Scope], ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			scope = append(scope, part)
+		}
+	}
+	return
+}
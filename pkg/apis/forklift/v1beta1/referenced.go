@@ -21,6 +21,8 @@ type Referenced struct {
 		Network *NetworkMap
 		// Storage
 		Storage *StorageMap
+		// Network used by test failover.
+		TestNetwork *NetworkMap
 	}
 	// Hooks.
 	Hooks []*Hook
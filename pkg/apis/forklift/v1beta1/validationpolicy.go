@@ -0,0 +1,64 @@
+package v1beta1
+
+import (
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidationPolicy specification.
+type ValidationPolicySpec struct {
+	// Type of the (source) providers validated by the policy.
+	ProviderType ProviderType `json:"providerType"`
+	// Rego rules.
+	// Evaluated with the VM as `input` along with the
+	// provider policies (package). The package clause is
+	// assigned by the controller. Concerns are reported
+	// using the `concerns` set:
+	//   concerns[flag] {
+	//     count(input.nics) > 8
+	//     flag := {
+	//       "category": "Critical",
+	//       "label": "Too many NICs",
+	//       "assessment": "The VM has more than 8 NICs."
+	//     }
+	//   }
+	Rego string `json:"rego"`
+}
+
+// ValidationPolicy status.
+type ValidationPolicyStatus struct {
+	// Conditions.
+	libcnd.Conditions `json:",inline"`
+	// The most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// User-defined VM validation policy.
+// The policy applies to the VMs of the providers
+// of the type in the same namespace.
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.providerType"
+// +kubebuilder:printcolumn:name="READY",type=string,JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+type ValidationPolicy struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Spec            ValidationPolicySpec   `json:"spec,omitempty"`
+	Status          ValidationPolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ValidationPolicyList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []ValidationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ValidationPolicy{}, &ValidationPolicyList{})
+}
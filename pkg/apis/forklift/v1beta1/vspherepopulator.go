@@ -0,0 +1,59 @@
+package v1beta1
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var VSphereVolumePopulatorKind = "VSphereVolumePopulator"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={vsvp,vsvps}
+type VSphereVolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VSphereVolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status VSphereVolumePopulatorStatus `json:"status"`
+}
+
+type VSphereVolumePopulatorSpec struct {
+	// The vCenter or ESXi host the disk is read from.
+	ServerURL string `json:"serverUrl"`
+	// The secret containing the credentials of the server.
+	SecretName string `json:"secretName"`
+	// The SHA-1 thumbprint of the server certificate.
+	// +optional
+	Thumbprint string `json:"thumbprint,omitempty"`
+	// The VM managed object reference (moref).
+	VmID string `json:"vmId"`
+	// The VMDK backing file, for example "[datastore] vm/vm.vmdk".
+	BackingFile string `json:"backingFile"`
+	// The image providing the VDDK library.
+	VddkImage string `json:"vddkImage"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+	// Rate limit of the disk transfer.
+	// +optional
+	RateLimit *plan.RateLimit `json:"rateLimit,omitempty"`
+}
+
+type VSphereVolumePopulatorStatus struct {
+	// +optional
+	Progress string `json:"progress"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VSphereVolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []VSphereVolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VSphereVolumePopulator{}, &VSphereVolumePopulatorList{})
+}
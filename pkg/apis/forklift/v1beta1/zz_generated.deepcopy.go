@@ -57,12 +57,32 @@ func (in *DestinationStorage) DeepCopy() *DestinationStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestHook) DeepCopyInto(out *GuestHook) {
+	*out = *in
+	if in.Interpreter != nil {
+		in, out := &in.Interpreter, &out.Interpreter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestHook.
+func (in *GuestHook) DeepCopy() *GuestHook {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hook) DeepCopyInto(out *Hook) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -116,9 +136,72 @@ func (in *HookList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookRetry) DeepCopyInto(out *HookRetry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookRetry.
+func (in *HookRetry) DeepCopy() *HookRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(HookRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookSecret) DeepCopyInto(out *HookSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookSecret.
+func (in *HookSecret) DeepCopy() *HookSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(HookSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookSpec) DeepCopyInto(out *HookSpec) {
 	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]HookSecret, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HookRetry)
+		**out = **in
+	}
+	if in.Guest != nil {
+		in, out := &in.Guest, &out.Guest
+		*out = new(GuestHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookSpec.
@@ -241,6 +324,110 @@ func (in *HostStatus) DeepCopy() *HostStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpVolumePopulator) DeepCopyInto(out *HttpVolumePopulator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpVolumePopulator.
+func (in *HttpVolumePopulator) DeepCopy() *HttpVolumePopulator {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpVolumePopulator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HttpVolumePopulator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpVolumePopulatorList) DeepCopyInto(out *HttpVolumePopulatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HttpVolumePopulator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpVolumePopulatorList.
+func (in *HttpVolumePopulatorList) DeepCopy() *HttpVolumePopulatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpVolumePopulatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HttpVolumePopulatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpVolumePopulatorSpec) DeepCopyInto(out *HttpVolumePopulatorSpec) {
+	*out = *in
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.TransferRelay != nil {
+		in, out := &in.TransferRelay, &out.TransferRelay
+		*out = new(plan.TransferRelay)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(plan.RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpVolumePopulatorSpec.
+func (in *HttpVolumePopulatorSpec) DeepCopy() *HttpVolumePopulatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpVolumePopulatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpVolumePopulatorStatus) DeepCopyInto(out *HttpVolumePopulatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpVolumePopulatorStatus.
+func (in *HttpVolumePopulatorStatus) DeepCopy() *HttpVolumePopulatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpVolumePopulatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapStatus) DeepCopyInto(out *MapStatus) {
 	*out = *in
@@ -358,6 +545,11 @@ func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 			}
 		}
 	}
+	if in.Transfer != nil {
+		in, out := &in.Transfer, &out.Transfer
+		*out = new(plan.TransferProgress)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
@@ -535,6 +727,16 @@ func (in *OpenstackVolumePopulatorSpec) DeepCopyInto(out *OpenstackVolumePopulat
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.TransferRelay != nil {
+		in, out := &in.TransferRelay, &out.TransferRelay
+		*out = new(plan.TransferRelay)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(plan.RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenstackVolumePopulatorSpec.
@@ -629,6 +831,16 @@ func (in *OvirtVolumePopulatorSpec) DeepCopyInto(out *OvirtVolumePopulatorSpec)
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.TransferRelay != nil {
+		in, out := &in.TransferRelay, &out.TransferRelay
+		*out = new(plan.TransferRelay)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(plan.RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvirtVolumePopulatorSpec.
@@ -733,6 +945,31 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.TransferRelay != nil {
+		in, out := &in.TransferRelay, &out.TransferRelay
+		*out = new(plan.TransferRelay)
+		**out = **in
+	}
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		*out = new(plan.BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]plan.HookRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(plan.Verify)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(plan.TestFailover)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -750,6 +987,12 @@ func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
 	in.Migration.DeepCopyInto(&out.Migration)
+	if in.WaivedConcerns != nil {
+		in, out := &in.WaivedConcerns, &out.WaivedConcerns
+		*out = make([]plan.WaivedConcern, len(*in))
+		copy(*out, *in)
+	}
+	in.Rehearsal.DeepCopyInto(&out.Rehearsal)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
@@ -962,3 +1205,192 @@ func (in *StoragePair) DeepCopy() *StoragePair {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVolumePopulator) DeepCopyInto(out *VSphereVolumePopulator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVolumePopulator.
+func (in *VSphereVolumePopulator) DeepCopy() *VSphereVolumePopulator {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVolumePopulator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereVolumePopulator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVolumePopulatorList) DeepCopyInto(out *VSphereVolumePopulatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VSphereVolumePopulator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVolumePopulatorList.
+func (in *VSphereVolumePopulatorList) DeepCopy() *VSphereVolumePopulatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVolumePopulatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VSphereVolumePopulatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVolumePopulatorSpec) DeepCopyInto(out *VSphereVolumePopulatorSpec) {
+	*out = *in
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(plan.RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVolumePopulatorSpec.
+func (in *VSphereVolumePopulatorSpec) DeepCopy() *VSphereVolumePopulatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVolumePopulatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereVolumePopulatorStatus) DeepCopyInto(out *VSphereVolumePopulatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereVolumePopulatorStatus.
+func (in *VSphereVolumePopulatorStatus) DeepCopy() *VSphereVolumePopulatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereVolumePopulatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationPolicy) DeepCopyInto(out *ValidationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationPolicy.
+func (in *ValidationPolicy) DeepCopy() *ValidationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ValidationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationPolicyList) DeepCopyInto(out *ValidationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ValidationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationPolicyList.
+func (in *ValidationPolicyList) DeepCopy() *ValidationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ValidationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationPolicySpec) DeepCopyInto(out *ValidationPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationPolicySpec.
+func (in *ValidationPolicySpec) DeepCopy() *ValidationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationPolicyStatus) DeepCopyInto(out *ValidationPolicyStatus) {
+	*out = *in
+	in.Conditions.DeepCopyInto(&out.Conditions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationPolicyStatus.
+func (in *ValidationPolicyStatus) DeepCopy() *ValidationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
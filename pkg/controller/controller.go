@@ -24,6 +24,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/migration"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider"
+	"github.com/konveyor/forklift-controller/pkg/controller/validationpolicy"
 	"github.com/konveyor/forklift-controller/pkg/settings"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -48,6 +49,7 @@ var MainControllers = []AddFunction{
 // List of Inventory controllers
 var InventoryControllers = []AddFunction{
 	provider.Add,
+	validationpolicy.Add,
 }
 
 // Add controllers to the manager based on role.
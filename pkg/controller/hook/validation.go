@@ -2,14 +2,21 @@ package hook
 
 import (
 	"encoding/base64"
+	"path"
+
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Types
 const (
 	InvalidImage    = "InvalidImage"
 	InvalidPlaybook = "InvalidPlaybook"
+	InvalidCommand  = "InvalidCommand"
+	InvalidSecret   = "InvalidSecret"
+	InvalidRetry    = "InvalidRetry"
+	InvalidGuest    = "InvalidGuest"
 )
 
 // Categories
@@ -26,6 +33,7 @@ const (
 	NotSet   = "NotSet"
 	NotFound = "NotFound"
 	DataErr  = "DataError"
+	Conflict = "Conflict"
 )
 
 // Statuses
@@ -44,11 +52,30 @@ func (r *Reconciler) validate(hook *api.Hook) (err error) {
 	if err != nil {
 		return
 	}
+	err = r.validateCommand(hook)
+	if err != nil {
+		return
+	}
+	err = r.validateSecrets(hook)
+	if err != nil {
+		return
+	}
+	err = r.validateRetry(hook)
+	if err != nil {
+		return
+	}
+	err = r.validateGuest(hook)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // Validate the hook.
 func (r *Reconciler) validateImage(hook *api.Hook) (err error) {
+	if hook.Spec.Guest != nil && hook.Spec.Image == "" {
+		return
+	}
 	match := ReferenceRegexp.MatchString(hook.Spec.Image)
 	if !match {
 		hook.Status.SetCondition(libcnd.Condition{
@@ -76,3 +103,85 @@ func (r Reconciler) validatePlaybook(hook *api.Hook) (err error) {
 
 	return
 }
+
+// Validate the command.
+func (r Reconciler) validateCommand(hook *api.Hook) (err error) {
+	if len(hook.Spec.Command) > 0 && len(hook.Spec.Playbook) > 0 {
+		hook.Status.SetCondition(libcnd.Condition{
+			Type:     InvalidCommand,
+			Status:   True,
+			Reason:   Conflict,
+			Category: Critical,
+			Message:  "Either `Command` or `Playbook` may be specified.",
+		})
+	}
+
+	return
+}
+
+// Validate the secrets.
+func (r Reconciler) validateSecrets(hook *api.Hook) (err error) {
+	notValid := libcnd.Condition{
+		Type:     InvalidSecret,
+		Status:   True,
+		Reason:   NotSet,
+		Category: Critical,
+		Message:  "The secret `Name` must be a valid name; `MountPath` must be absolute.",
+		Items:    []string{},
+	}
+	for _, secret := range hook.Spec.Secrets {
+		if len(k8svalidation.IsDNS1123Subdomain(secret.Name)) > 0 ||
+			!path.IsAbs(secret.Path()) {
+			notValid.Items = append(notValid.Items, secret.Name)
+		}
+	}
+	if len(notValid.Items) > 0 {
+		hook.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+// Validate the retry policy.
+func (r Reconciler) validateRetry(hook *api.Hook) (err error) {
+	if hook.Spec.Retry != nil && hook.Spec.Retry.Limit < 0 {
+		hook.Status.SetCondition(libcnd.Condition{
+			Type:     InvalidRetry,
+			Status:   True,
+			Reason:   DataErr,
+			Category: Critical,
+			Message:  "The retry `Limit` must not be negative.",
+		})
+	}
+
+	return
+}
+
+// Validate the in-guest hook.
+func (r Reconciler) validateGuest(hook *api.Hook) (err error) {
+	guest := hook.Spec.Guest
+	if guest == nil {
+		return
+	}
+	if guest.Script == "" {
+		hook.Status.SetCondition(libcnd.Condition{
+			Type:     InvalidGuest,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "The in-guest hook `Script` must be specified.",
+		})
+		return
+	}
+	if len(hook.Spec.Command) > 0 || len(hook.Spec.Playbook) > 0 {
+		hook.Status.SetCondition(libcnd.Condition{
+			Type:     InvalidGuest,
+			Status:   True,
+			Reason:   Conflict,
+			Category: Critical,
+			Message:  "`Guest` may not be specified with `Command` or `Playbook`.",
+		})
+	}
+
+	return
+}
@@ -176,10 +176,16 @@ func (r *Reconciler) reflectPlan(plan *api.Plan, migration *api.Migration) {
 	if migration.Status.HasAnyCondition(Canceled, Succeeded, Failed) {
 		return
 	}
-	found, snapshot := plan.Status.Migration.SnapshotWithMigration(migration.UID)
+	status := plan.Status.FindMigration(migration.UID)
+	found, snapshot := status.SnapshotWithMigration(migration.UID)
 	if !found {
 		return
 	}
+	for _, kind := range []string{PlanPreHook, PlanPostHook} {
+		if cnd := snapshot.FindCondition(kind); cnd != nil {
+			migration.Status.SetCondition(*cnd)
+		}
+	}
 	if cnd := snapshot.FindCondition(Canceled); cnd != nil {
 		cnd.Durable = true
 		migration.Status.SetCondition(*cnd)
@@ -215,5 +221,6 @@ func (r *Reconciler) reflectPlan(plan *api.Plan, migration *api.Migration) {
 			Durable:  true,
 		})
 	}
-	migration.Status.VMs = plan.Status.Migration.VMs
+	migration.Status.VMs = status.VMs
+	migration.Status.Transfer = status.Transfer
 }
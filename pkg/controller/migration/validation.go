@@ -25,6 +25,8 @@ const (
 	Succeeded    = plancnt.Succeeded
 	Failed       = plancnt.Failed
 	Canceled     = plancnt.Canceled
+	PlanPreHook  = plancnt.PlanPreHook
+	PlanPostHook = plancnt.PlanPostHook
 )
 
 // Categories
@@ -6,6 +6,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/util"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	core "k8s.io/api/core/v1"
 	cnv "kubevirt.io/api/core/v1"
@@ -27,6 +28,10 @@ const (
 	// Set the source PVC of the conversion, used on the DV for filtering
 	AnnConversionSourcePVC = "forklift.konveyor.io/conversionSourcePVC"
 
+	// Set on the PVs and PVCs of disks cloned by the storage array,
+	// contains the name of the storage offload driver.
+	AnnOffload = "forklift.konveyor.io/offload"
+
 	// CDI
 
 	// Causes the importer pod to be retained after import.
@@ -90,6 +95,18 @@ type Builder interface {
 	GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error)
 	// Get the virtual machine preference name
 	PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error)
+	// Guest identity the migrated VM is expected to report.
+	GuestIdentity(vmRef ref.Ref) (identity GuestIdentity, err error)
+}
+
+// Guest identity.
+// Reported by the guest agent of the migrated VM.
+// Empty fields are not verified.
+type GuestIdentity struct {
+	// Hostname.
+	HostName string
+	// IP addresses.
+	IPs []string
 }
 
 // Client API.
@@ -128,6 +145,8 @@ type Validator interface {
 	StorageMapped(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's direct LUN/FC has the required details (oVirt only)
 	DirectStorage(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's disks can be cloned by the storage array (vSphere only).
+	StorageOffload(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's networks have been mapped.
 	NetworksMapped(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's Host isn't in maintenance mode.
@@ -138,8 +157,15 @@ type Validator interface {
 	PodNetwork(vmRef ref.Ref) (bool, error)
 	// Validate that we have information about static IPs for every virtual NIC
 	StaticIPs(vmRef ref.Ref) (bool, error)
+	// Validate that a VM is within the (source) provider scope.
+	InScope(vmRef ref.Ref) (bool, error)
+	// List the concerns reported by the inventory for a VM.
+	Concerns(vmRef ref.Ref) ([]Concern, error)
 }
 
+// VM concern.
+type Concern = model.Concern
+
 // DestinationClient API.
 // Performs provider-specific actions on the Destination cluster
 type DestinationClient interface {
@@ -3,6 +3,12 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
@@ -17,8 +23,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Port the image-converter serves the progress metric on.
+const metricsPort = 2112
+
+// Timeout of the progress requests, the progress is
+// read while reconciling.
+const metricsTimeout = 2 * time.Second
+
+// Progress metric served by the image-converter.
+var progressRegex = regexp.MustCompile(`(?m)^image_converter_progress (\d+(?:\.\d+)?(?:e[+-]?\d+)?)`)
+
+// Client reading the progress.
+var metricsClient = &http.Client{Timeout: metricsTimeout}
+
 type filterFn func(pvc *v1.PersistentVolumeClaim) bool
 type srcFormatFn func(pvc *v1.PersistentVolumeClaim) string
+type progressFn func(pvc *v1.PersistentVolumeClaim, percent float64)
 
 type PVCConverter interface {
 	ConvertPVCs(pvcs []*v1.PersistentVolumeClaim, srcFormat srcFormatFn, dstFormat string) (ready bool, err error)
@@ -29,6 +49,8 @@ type Converter struct {
 	Log         logging.LevelLogger
 	Labels      map[string]string
 	FilterFn    filterFn
+	// Called with the percent of each PVC converted.
+	ProgressFn progressFn
 }
 
 func NewConverter(destination *plancontext.Destination, logger logging.LevelLogger, labels map[string]string) *Converter {
@@ -47,63 +69,165 @@ func (c *Converter) ConvertPVCs(pvcs []*v1.PersistentVolumeClaim, srcFormat srcF
 			continue
 		}
 
-		scratchDV, err := c.ensureScratchDV(pvc)
+		var converted bool
+		converted, err = c.convertPVC(pvc, srcFormat(pvc), dstFormat)
 		if err != nil {
-			return false, err
+			return
+		}
+		if converted {
+			completed++
+		}
+	}
+
+	ready = completed == len(pvcs)
+	return
+}
+
+// Convert the PVC using a scratch DV the image is converted
+// into before it is copied back. Returns whether the PVC has
+// been converted.
+func (c *Converter) convertPVC(pvc *v1.PersistentVolumeClaim, srcFormat, dstFormat string) (converted bool, err error) {
+	convertJob, err := c.findJob(pvc)
+	if err != nil {
+		return
+	}
+	if convertJob == nil {
+		scratchDV, dvErr := c.ensureScratchDV(pvc)
+		if dvErr != nil {
+			err = dvErr
+			return
 		}
 
 		switch scratchDV.Status.Phase {
 		case cdi.ImportScheduled, cdi.Pending:
 			c.Log.Info("Scratch DV is not ready", "dv", scratchDV.Name, "status", scratchDV.Status.Phase)
-			return false, nil
+			return
 		case cdi.ImportInProgress:
 			c.Log.Info("Scratch DV import in progress", "dv", scratchDV.Name)
-			return false, nil
+			return
 		case cdi.Succeeded:
 			c.Log.Info("Scratch DV is ready", "dv", scratchDV.Name)
 		default:
 			c.Log.Info("Scratch DV is not ready", "dv", scratchDV.Name, "status", scratchDV.Status.Phase)
-			return false, nil
+			return
 		}
 
-		convertJob, err := c.ensureJob(pvc, scratchDV, srcFormat(pvc), dstFormat)
+		convertJob, err = c.ensureJob(pvc, scratchDV, srcFormat, dstFormat)
 		if err != nil {
-			return false, err
+			return
 		}
+	}
 
-		c.Log.Info("Convert job status", "pvc", pvc.Name, "status", convertJob.Status)
-		for _, condition := range convertJob.Status.Conditions {
-			switch condition.Type {
-			case batchv1.JobComplete:
-				c.Log.Info("Convert job completed", "pvc", pvc.Name)
-				c.deleteScratchDV(scratchDV)
-				return true, nil
-
-			case batchv1.JobFailed:
-				if convertJob.Status.Failed >= 3 {
-					c.deleteScratchDV(scratchDV)
-					return true, liberr.New("convert job failed")
-				}
+	c.Log.Info("Convert job status", "pvc", pvc.Name, "status", convertJob.Status)
+	for _, condition := range convertJob.Status.Conditions {
+		switch condition.Type {
+		case batchv1.JobComplete:
+			c.Log.Info("Convert job completed", "pvc", pvc.Name)
+			c.reportProgress(pvc, 100)
+			c.deleteScratchDVs(pvc)
+			converted = true
+			return
+
+		case batchv1.JobFailed:
+			if convertJob.Status.Failed >= 3 {
+				c.deleteScratchDVs(pvc)
+				err = liberr.New("convert job failed")
+				return
 			}
 		}
 	}
 
-	if completed == len(pvcs) {
-		return true, nil
+	c.updateProgress(pvc, convertJob)
+	return
+}
+
+// Update the progress from the metrics served by the running job pod.
+// The pods of remote destinations are not reachable from the controller.
+func (c *Converter) updateProgress(pvc *v1.PersistentVolumeClaim, job *batchv1.Job) {
+	if c.ProgressFn == nil {
+		return
+	}
+	if provider := c.Destination.Provider; provider != nil && !provider.IsHost() {
+		return
 	}
+	pods := &v1.PodList{}
+	err := c.Destination.Client.List(
+		context.TODO(),
+		pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name})
+	if err != nil {
+		c.Log.Error(err, "Failed to list the convert job pods", "job", job.Name)
+		return
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		percent, err := scrapeProgress(pod.Status.PodIP)
+		if err != nil {
+			c.Log.Info("Failed to read the conversion progress", "pod", pod.Name, "error", err.Error())
+			return
+		}
+		c.reportProgress(pvc, percent)
+		return
+	}
+}
 
-	return false, nil
+// Report the progress of the PVC conversion.
+func (c *Converter) reportProgress(pvc *v1.PersistentVolumeClaim, percent float64) {
+	if c.ProgressFn != nil {
+		c.ProgressFn(pvc, percent)
+	}
 }
 
-func (c *Converter) deleteScratchDV(scratchDV *cdi.DataVolume) {
-	err := c.Destination.Client.Delete(context.Background(), scratchDV)
+// Read the progress (percent) served by the image-converter.
+func scrapeProgress(podIP string) (percent float64, err error) {
+	return scrapeMetrics(fmt.Sprintf("http://%s/metrics", net.JoinHostPort(podIP, strconv.Itoa(metricsPort))))
+}
+
+// Read the progress (percent) from the metrics.
+func scrapeMetrics(url string) (percent float64, err error) {
+	resp, err := metricsClient.Get(url)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.Log.Error(err, "Failed to delete scratch DV", "DV", scratchDV.Name)
+		return
 	}
+	match := progressRegex.FindStringSubmatch(string(body))
+	if match == nil {
+		return
+	}
+	percent, err = strconv.ParseFloat(match[1], 64)
+	if percent > 100 {
+		percent = 100
+	}
+	return
 }
 
-func (c *Converter) ensureJob(pvc *v1.PersistentVolumeClaim, dv *cdi.DataVolume, srcFormat, dstFormat string) (*batchv1.Job, error) {
-	// Find existing job by label
+// Delete the scratch DVs of the PVC.
+func (c *Converter) deleteScratchDVs(pvc *v1.PersistentVolumeClaim) {
+	dvList := &cdi.DataVolumeList{}
+	label := client.MatchingLabels{planbase.AnnConversionSourcePVC: pvc.Name}
+	err := c.Destination.Client.List(context.Background(), dvList, client.InNamespace(pvc.Namespace), label)
+	if err != nil {
+		c.Log.Error(err, "Failed to list scratch DVs", "pvc", pvc.Name)
+		return
+	}
+	for i := range dvList.Items {
+		scratchDV := &dvList.Items[i]
+		err = c.Destination.Client.Delete(context.Background(), scratchDV)
+		if err != nil {
+			c.Log.Error(err, "Failed to delete scratch DV", "DV", scratchDV.Name)
+		}
+	}
+}
+
+// Find the convert job of the PVC.
+func (c *Converter) findJob(pvc *v1.PersistentVolumeClaim) (*batchv1.Job, error) {
 	jobList := &batchv1.JobList{}
 	label := client.MatchingLabels{planbase.AnnConversionSourcePVC: pvc.Name}
 	err := c.Destination.Client.List(context.Background(), jobList, client.InNamespace(pvc.Namespace), label)
@@ -118,8 +242,17 @@ func (c *Converter) ensureJob(pvc *v1.PersistentVolumeClaim, dv *cdi.DataVolume,
 		return nil, liberr.New("multiple convert jobs found for pvc", "pvc", pvc.Name)
 	}
 
+	return nil, nil
+}
+
+func (c *Converter) ensureJob(pvc *v1.PersistentVolumeClaim, dv *cdi.DataVolume, srcFormat, dstFormat string) (*batchv1.Job, error) {
+	job, err := c.findJob(pvc)
+	if err != nil || job != nil {
+		return job, err
+	}
+
 	// Job doesn't exist, create it
-	job := createConvertJob(pvc, dv, srcFormat, dstFormat, c.Labels)
+	job = createConvertJob(pvc, dv, srcFormat, dstFormat, c.Labels)
 	c.Log.Info("Creating convert job", "pvc", pvc.Name, "srcFormat", srcFormat, "dstFormat", dstFormat)
 	err = c.Destination.Client.Create(context.Background(), job)
 	if err != nil {
@@ -215,11 +348,19 @@ func makeConversionContainer(pvc *v1.PersistentVolumeClaim, srcFormat, dstFormat
 			"-src-format", srcFormat,
 			"-dst-format", dstFormat,
 			"-volume-mode", string(volumeMode),
+			"-metrics-address", fmt.Sprintf(":%d", metricsPort),
+		},
+		Ports: []v1.ContainerPort{
+			{
+				Name:          "metrics",
+				ContainerPort: metricsPort,
+				Protocol:      v1.ProtocolTCP,
+			},
 		},
 	}
 
 	// Determine source path based on volumeMode
-	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == v1.PersistentVolumeBlock {
+	if rawBlock {
 		container.VolumeDevices = []v1.VolumeDevice{
 			{
 				Name:       "source",
@@ -2,7 +2,10 @@ package adapter
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
@@ -15,6 +18,7 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -87,6 +91,32 @@ var _ = Describe("Converter tests", func() {
 			Expect(ready).To(BeTrue())
 		})
 
+		It("Should not be ready until all jobs are ready", func() {
+			otherPVC := qcow2PVC.DeepCopy()
+			otherPVC.Name = "other-pvc"
+			completedJob := convertJob.DeepCopy()
+			completedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete}}
+			completed := map[string]float64{}
+			converter = createFakeConverter(qcow2PVC, otherPVC, completedJob)
+			converter.ProgressFn = func(pvc *v1.PersistentVolumeClaim, percent float64) {
+				completed[pvc.Name] = percent
+			}
+			ready, err := converter.ConvertPVCs([]*v1.PersistentVolumeClaim{qcow2PVC, otherPVC}, srcFormatFn, "raw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ready).To(BeFalse())
+			Expect(completed).To(Equal(map[string]float64{pvcName: 100}))
+		})
+
+		It("Should read the progress served by the job", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("# TYPE image_converter_progress gauge\nimage_converter_progress 42.5\n"))
+			}))
+			defer server.Close()
+			percent, err := scrapeMetrics(server.URL + "/metrics")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(percent).To(Equal(42.5))
+		})
+
 		It("Should create job if it does not exist", func() {
 			converter = createFakeConverter(qcow2PVC)
 			dv := &cdi.DataVolume{
@@ -100,6 +130,37 @@ var _ = Describe("Converter tests", func() {
 			Expect(job).ToNot(BeNil())
 		})
 
+		It("Should convert block volumes through a scratch DV", func() {
+			blockPVC := qcow2PVC.DeepCopy()
+			blockPVC.Spec.VolumeMode = ptr.To(v1.PersistentVolumeBlock)
+			converter = createFakeConverter(blockPVC)
+			ready, err := converter.ConvertPVCs([]*v1.PersistentVolumeClaim{blockPVC}, srcFormatFn, "raw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ready).To(BeFalse())
+			dvs := &cdi.DataVolumeList{}
+			Expect(converter.Destination.Client.List(context.TODO(), dvs)).To(Succeed())
+			Expect(dvs.Items).To(HaveLen(1))
+			job, err := converter.findJob(blockPVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job).To(BeNil())
+		})
+
+		It("Should not read the progress of remote destinations", func() {
+			converter = createFakeConverter(qcow2PVC)
+			converter.Destination.Provider = &api.Provider{
+				Spec: api.ProviderSpec{
+					Type: ptr.To(api.OpenShift),
+					URL:  "https://remote.example.com:6443",
+				},
+			}
+			called := false
+			converter.ProgressFn = func(pvc *v1.PersistentVolumeClaim, percent float64) {
+				called = true
+			}
+			converter.updateProgress(qcow2PVC, convertJob)
+			Expect(called).To(BeFalse())
+		})
+
 		It("Should create scratch DV if it does not exist", func() {
 			converter = createFakeConverter(qcow2PVC)
 			dv, err := converter.ensureScratchDV(qcow2PVC)
@@ -641,3 +641,9 @@ func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.Persiste
 	// do nothing
 	return
 }
+
+// Guest identity the migrated VM is expected to report.
+// Not known for this provider.
+func (r *Builder) GuestIdentity(vmRef ref.Ref) (identity planbase.GuestIdentity, err error) {
+	return
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	ocpclient "github.com/konveyor/forklift-controller/pkg/lib/client/openshift"
 	core "k8s.io/api/core/v1"
@@ -183,6 +184,11 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	return true, nil
 }
 
+// NO-OP
+func (r *Validator) StorageOffload(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
 // NO-OP
 func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 	return true, nil
@@ -192,3 +198,14 @@ func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
 	return true, nil
 }
+
+// NO-OP
+func (r *Validator) InScope(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// List the concerns reported for a VM.
+// Not reported by the (OpenShift) inventory.
+func (r *Validator) Concerns(vmRef ref.Ref) ([]planbase.Concern, error) {
+	return nil, nil
+}
@@ -961,7 +961,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 
 	for _, image := range images {
 		if imageID, ok := image.Properties[forkliftPropertyOriginalImageID]; ok && imageID == workload.ImageID {
-			if image.DiskFormat != "raw" {
+			if image.DiskFormat != "raw" && populatorFormat(workload, &image) == "" {
 				r.Log.Info("this image will require conversion as it's not raw", "image", image.Name, "diskFormat", image.DiskFormat)
 				annotations[planbase.AnnRequiresConversion] = "true"
 				annotations[planbase.AnnSourceFormat] = image.DiskFormat
@@ -996,7 +996,11 @@ func (r *Builder) ensureVolumePopulator(workload *model.Workload, image *model.I
 			err = liberr.Wrap(err)
 			return
 		}
-		return r.createVolumePopulatorCR(*image, secretName, workload.ID)
+		disks := len(workload.Volumes)
+		if workload.ImageID != "" {
+			disks++
+		}
+		return r.createVolumePopulatorCR(*image, secretName, workload, disks)
 	}
 	populatorCR = &volumePopulatorCR
 	return
@@ -1092,13 +1096,13 @@ func (r *Builder) getImagesFromVolumes(workload *model.Workload) (images []model
 	return
 }
 
-func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId string) (populatorCR *api.OpenstackVolumePopulator, err error) {
+func (r *Builder) createVolumePopulatorCR(image model.Image, secretName string, workload *model.Workload, disks int) (populatorCR *api.OpenstackVolumePopulator, err error) {
 	populatorCR = &api.OpenstackVolumePopulator{
 		ObjectMeta: meta.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-", image.Name),
 			Namespace:    r.Plan.Spec.TargetNamespace,
 			Labels: map[string]string{
-				"vmID":      vmId,
+				"vmID":      workload.ID,
 				"migration": getMigrationID(r.Context),
 				"imageID":   image.ID,
 			},
@@ -1107,7 +1111,10 @@ func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId st
 			IdentityURL:     r.Source.Provider.Spec.URL,
 			SecretName:      secretName,
 			ImageID:         image.ID,
+			Format:          populatorFormat(workload, &image),
 			TransferNetwork: r.Plan.Spec.TransferNetwork,
+			TransferRelay:   r.Plan.Spec.TransferRelay,
+			RateLimit:       r.Context.RateLimit(disks),
 		},
 	}
 	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
@@ -1118,6 +1125,21 @@ func (r *Builder) createVolumePopulatorCR(image model.Image, secretName, vmId st
 	return
 }
 
+// Format the populator converts the VM snapshot image from
+// while it populates the volume, streaming it into the volume
+// rather than through a scratch volume. Empty when the image is
+// stored as is.
+func populatorFormat(workload *model.Workload, image *model.Image) (format string) {
+	if image.Properties[forkliftPropertyOriginalImageID] != workload.ImageID || workload.ImageID == "" {
+		return
+	}
+	switch image.DiskFormat {
+	case api.ImageFormatQcow2, api.ImageFormatVmdk, api.ImageFormatVhd, api.ImageFormatVhdx, api.ImageFormatVdi:
+		format = image.DiskFormat
+	}
+	return
+}
+
 func (r *Builder) getVolumeType(workload *model.Workload, volumeID string) (volumeType string) {
 	for _, volume := range workload.Volumes {
 		if volume.ID == volumeID {
@@ -1409,3 +1431,9 @@ func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskNam
 	taskName = image.Name
 	return
 }
+
+// Guest identity the migrated VM is expected to report.
+// Not known for this provider.
+func (r *Builder) GuestIdentity(vmRef ref.Ref) (identity planbase.GuestIdentity, err error) {
+	return
+}
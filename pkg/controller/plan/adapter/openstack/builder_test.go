@@ -2,6 +2,7 @@ package openstack
 
 import (
 	v1beta1 "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -23,3 +24,23 @@ var _ = Describe("OpenStack Glance const test", func() {
 		Expect(v1beta1.GlanceSource).Should(Equal("glance"))
 	})
 })
+
+var _ = Describe("OpenStack populator format", func() {
+	workload := &model.Workload{}
+	workload.ImageID = "snapshot"
+	image := func(originalID, format string) *model.Image {
+		image := &model.Image{}
+		image.DiskFormat = format
+		image.Properties = map[string]interface{}{forkliftPropertyOriginalImageID: originalID}
+		return image
+	}
+	DescribeTable("should", func(originalID, format, expected string) {
+		Expect(populatorFormat(workload, image(originalID, format))).Should(Equal(expected))
+	},
+		Entry("convert the qcow2 snapshot", "snapshot", "qcow2", "qcow2"),
+		Entry("convert the vhdx snapshot", "snapshot", "vhdx", "vhdx"),
+		Entry("not convert the raw snapshot", "snapshot", "raw", ""),
+		Entry("not convert unsupported formats", "snapshot", "ploop", ""),
+		Entry("not convert the volume images", "volume", "qcow2", ""),
+	)
+})
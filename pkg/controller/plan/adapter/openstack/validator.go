@@ -3,6 +3,7 @@ package openstack
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -105,6 +106,11 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// NO-OP
+func (r *Validator) StorageOffload(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
 // NO-OP
 func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 	return true, nil
@@ -115,3 +121,47 @@ func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
 	// the guest operating system is not modified during the migration so static IPs should be preserved
 	return true, nil
 }
+
+// Validate that a VM is within the provider scope.
+// The VM project must be listed (by name or ID).
+func (r *Validator) InScope(vmRef ref.Ref) (ok bool, err error) {
+	scope := r.plan.Referenced.Provider.Source.Scope()
+	if len(scope) == 0 {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	project := &model.Project{}
+	projectRef := ref.Ref{ID: vm.TenantID}
+	err = r.inventory.Find(project, projectRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String(), "project", projectRef.String())
+		return
+	}
+	for _, name := range scope {
+		if name == project.Name || name == project.ID {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// List the concerns reported by the inventory for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planbase.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	concerns = vm.Concerns
+
+	return
+}
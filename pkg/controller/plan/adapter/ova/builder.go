@@ -568,3 +568,9 @@ func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskNam
 	err = planbase.VolumePopulatorNotSupportedError
 	return
 }
+
+// Guest identity the migrated VM is expected to report.
+// Not known for this provider.
+func (r *Builder) GuestIdentity(vmRef ref.Ref) (identity planbase.GuestIdentity, err error) {
+	return
+}
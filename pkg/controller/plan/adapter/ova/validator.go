@@ -3,6 +3,7 @@ package ova
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -96,6 +97,11 @@ func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// NO-OP
+func (r *Validator) StorageOffload(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
 // NO-OP
 func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 	return true, nil
@@ -105,3 +111,22 @@ func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
 	return true, nil
 }
+
+// NO-OP
+func (r *Validator) InScope(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// List the concerns reported by the inventory for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planbase.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	concerns = vm.Concerns
+
+	return
+}
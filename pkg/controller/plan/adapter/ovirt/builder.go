@@ -713,7 +713,7 @@ func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.Persiste
 }
 
 func (r *Builder) SupportsVolumePopulators() bool {
-	return !r.Context.Plan.Spec.Warm && r.Context.Plan.Provider.Destination.IsHost()
+	return !r.Context.Plan.SnapshotCopy() && r.Context.Plan.Provider.Destination.IsHost()
 }
 
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
@@ -724,6 +724,12 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		return
 	}
 
+	disks := 0
+	for _, diskAttachment := range workload.DiskAttachments {
+		if diskAttachment.Disk.StorageType != "lun" {
+			disks++
+		}
+	}
 	var sdToStorageClass map[string]string
 	for _, diskAttachment := range workload.DiskAttachments {
 		if diskAttachment.Disk.StorageType == "lun" {
@@ -736,7 +742,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				return
 			}
 			var populatorName string
-			populatorName, err = r.createVolumePopulatorCR(diskAttachment, secretName, vmRef.ID)
+			populatorName, err = r.createVolumePopulatorCR(diskAttachment, secretName, vmRef.ID, disks)
 			if err != nil {
 				err = liberr.Wrap(err)
 				return
@@ -804,7 +810,7 @@ func (r *Builder) getVolumePopulator(diskID string) (populatorCr api.OvirtVolume
 	return
 }
 
-func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment, secretName, vmId string) (name string, err error) {
+func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment, secretName, vmId string, disks int) (name string, err error) {
 	migrationId := string(r.Migration.UID)
 	providerURL, err := url.Parse(r.Source.Provider.Spec.URL)
 	if err != nil {
@@ -830,6 +836,8 @@ func (r *Builder) createVolumePopulatorCR(diskAttachment model.XDiskAttachment,
 			EngineSecretName: secretName,
 			DiskID:           diskAttachment.Disk.ID,
 			TransferNetwork:  r.Plan.Spec.TransferNetwork,
+			TransferRelay:    r.Plan.Spec.TransferRelay,
+			RateLimit:        r.Context.RateLimit(disks),
 		},
 	}
 	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
@@ -994,3 +1002,9 @@ func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskNam
 	taskName = pvc.Annotations[planbase.AnnDiskSource]
 	return
 }
+
+// Guest identity the migrated VM is expected to report.
+// Not known for this provider.
+func (r *Builder) GuestIdentity(vmRef ref.Ref) (identity planbase.GuestIdentity, err error) {
+	return
+}
@@ -5,6 +5,7 @@ import (
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
@@ -106,6 +107,11 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// NO-OP
+func (r *Validator) StorageOffload(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
 // Validates oVirt version in case we use direct LUN/FC storage
 func (r *Validator) DirectStorage(vmRef ref.Ref) (ok bool, err error) {
 	vm := &model.Workload{}
@@ -171,3 +177,47 @@ func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
 	// the guest operating system is not modified during the migration so static IPs should be preserved
 	return true, nil
 }
+
+// Validate that a VM is within the provider scope.
+// The VM cluster must be listed (by name or ID).
+func (r *Validator) InScope(vmRef ref.Ref) (ok bool, err error) {
+	scope := r.plan.Referenced.Provider.Source.Scope()
+	if len(scope) == 0 {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	cluster := &model.Cluster{}
+	clusterRef := ref.Ref{ID: vm.Cluster}
+	err = r.inventory.Find(cluster, clusterRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String(), "cluster", clusterRef.String())
+		return
+	}
+	for _, name := range scope {
+		if name == cluster.Name || name == cluster.ID {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// List the concerns reported by the inventory for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planbase.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	concerns = vm.Concerns
+
+	return
+}
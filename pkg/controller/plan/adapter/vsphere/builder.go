@@ -8,12 +8,14 @@ import (
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/vsphere/offload"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	utils "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
 	container "github.com/konveyor/forklift-controller/pkg/controller/provider/container/vsphere"
@@ -30,7 +32,11 @@ import (
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	cnv "kubevirt.io/api/core/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
@@ -140,6 +146,11 @@ type Builder struct {
 	hosts map[string]*api.Host
 	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
 	macConflictsMap map[string]string
+	// Storage offload driver.
+	// Not set when the disks are copied.
+	offload offload.Driver
+	// Clones polled by the reconcile. k=name, v=volume (nil until completed)
+	clones map[string]*offload.Volume
 }
 
 // Get list of destination VMs with mac addresses that would
@@ -241,12 +252,20 @@ func (r *Builder) mapMacStaticIps(vm *model.VM) string {
 		return ""
 	}
 	configurations := []string{}
+	for _, guestNetwork := range staticIPs(vm) {
+		configurations = append(configurations, fmt.Sprintf("%s:ip:%s", guestNetwork.MAC, guestNetwork.IP))
+	}
+	return strings.Join(configurations, "_")
+}
+
+// Guest networks with a static (manual) IP address.
+func staticIPs(vm *model.VM) (networks []vsphere.GuestNetwork) {
 	for _, guestNetwork := range vm.GuestNetworks {
 		if guestNetwork.Origin == string(types.NetIpConfigInfoIpAddressOriginManual) {
-			configurations = append(configurations, fmt.Sprintf("%s:ip:%s", guestNetwork.MAC, guestNetwork.IP))
+			networks = append(networks, guestNetwork)
 		}
 	}
-	return strings.Join(configurations, "_")
+	return
 }
 
 func isWindows(vm *model.VM) bool {
@@ -396,6 +415,15 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 		}
 		for _, disk := range vm.Disks {
 			if disk.Datastore.ID == ds.ID {
+				cloned, oErr := r.isOffloaded(vm, disk)
+				if oErr != nil {
+					err = oErr
+					return
+				}
+				if cloned {
+					// Cloned by the storage array.
+					continue
+				}
 				storageClass := mapped.Destination.StorageClass
 				var dvSource cdi.DataVolumeSource
 				el9, el9Err := r.Context.Plan.VSphereUsesEl9VirtV2v()
@@ -478,7 +506,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 				vmRef.String()))
 		return
 	}
-	if r.Plan.Spec.Warm && !vm.ChangeTrackingEnabled {
+	if r.Plan.SnapshotCopy() && !vm.ChangeTrackingEnabled {
 		err = liberr.New(
 			fmt.Sprintf(
 				"Changed Block Tracking (CBT) is disabled for VM %s",
@@ -790,6 +818,9 @@ func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
 
 // Return a stable identifier for a PersistentDataVolume.
 func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	if source, found := pvc.Annotations[planbase.AnnDiskSource]; found {
+		return source
+	}
 	return r.baseVolume(pvc.Annotations[AnnImportBackingFile])
 }
 
@@ -799,6 +830,10 @@ func (r *Builder) Load() (err error) {
 	if err != nil {
 		return
 	}
+	err = r.loadOffload()
+	if err != nil {
+		return
+	}
 
 	return
 }
@@ -899,8 +934,8 @@ func trimBackingFileName(fileName string) string {
 }
 
 func (r *Builder) baseVolume(fileName string) string {
-	if r.Plan.Spec.Warm {
-		// for warm migrations, we return the very first volume of the disk
+	if r.Plan.SnapshotCopy() {
+		// for warm migrations and test failovers, we return the very first volume of the disk
 		// as the base volume and CBT will be used to transfer later changes
 		return trimBackingFileName(fileName)
 	} else {
@@ -915,42 +950,297 @@ func (r *Builder) baseVolume(fileName string) string {
 }
 
 // Build LUN PVs.
+// The PVs expose the disks cloned by the storage array.
 func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
-	// do nothing
+	err = r.offloadVolumes(vmRef, func(vm *model.VM, disk *plan.OffloadedDisk, volume *offload.Volume) {
+		pvs = append(pvs, r.offloadPersistentVolume(vm, disk, volume))
+	})
 	return
 }
 
 // Build LUN PVCs.
+// The PVCs are bound to the PVs exposing the disks
+// cloned by the storage array.
 func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
-	// do nothing
+	err = r.offloadVolumes(vmRef, func(vm *model.VM, disk *plan.OffloadedDisk, volume *offload.Volume) {
+		pvcs = append(pvcs, r.offloadPersistentVolumeClaim(vm, disk, volume))
+	})
 	return
 }
 
 func (r *Builder) SupportsVolumePopulators() bool {
-	return false
+	return r.Source.Provider.UsesVolumePopulator() &&
+		!r.Context.Plan.SnapshotCopy() &&
+		r.Context.Plan.Provider.Destination.IsHost()
 }
 
+// Create a VSphereVolumePopulator and a PVC referencing it for each disk.
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
-	err = planbase.VolumePopulatorNotSupportedError
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	dsMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range dsMapIn {
+		mapped := &dsMapIn[i]
+		ds := &model.Datastore{}
+		err = r.Source.Inventory.Find(ds, mapped.Source)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, disk := range vm.Disks {
+			if disk.Datastore.ID != ds.ID {
+				continue
+			}
+			var cloned bool
+			cloned, err = r.isOffloaded(vm, disk)
+			if err != nil {
+				return
+			}
+			if cloned {
+				// Cloned by the storage array.
+				continue
+			}
+			diskKey := strconv.Itoa(int(disk.Key))
+			_, err = r.getVolumePopulator(vmRef.ID, diskKey)
+			if err == nil {
+				continue
+			}
+			if !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			var populatorName string
+			populatorName, err = r.createVolumePopulatorCR(vm, disk, secretName)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			var pvc *core.PersistentVolumeClaim
+			pvc, err = r.persistentVolumeClaimWithSourceRef(vmRef, disk, mapped, populatorName, annotations)
+			if err != nil {
+				if !k8serr.IsAlreadyExists(err) {
+					err = liberr.Wrap(err, "disk", disk.File, "populator", populatorName)
+					return
+				}
+				err = nil
+				continue
+			}
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	return
+}
+
+// Get the VSphereVolumePopulator CustomResource based on the VM and disk key.
+func (r *Builder) getVolumePopulator(vmID, diskKey string) (populatorCr api.VSphereVolumePopulator, err error) {
+	list := api.VSphereVolumePopulatorList{}
+	err = r.Destination.Client.List(context.TODO(), &list, &client.ListOptions{
+		Namespace: r.Plan.Spec.TargetNamespace,
+		LabelSelector: k8slabels.SelectorFromSet(map[string]string{
+			"migration": string(r.Migration.UID),
+			"vmID":      vmID,
+			"diskID":    diskKey,
+		}),
+	})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) == 0 {
+		err = k8serr.NewNotFound(api.SchemeGroupVersion.WithResource("VSphereVolumePopulator").GroupResource(), diskKey)
+		return
+	}
+	if len(list.Items) > 1 {
+		err = liberr.New("Multiple VSphereVolumePopulator CRs found for the same disk", "vmID", vmID, "diskID", diskKey)
+		return
+	}
+	populatorCr = list.Items[0]
+	return
+}
+
+func (r *Builder) createVolumePopulatorCR(vm *model.VM, disk vsphere.Disk, secretName string) (name string, err error) {
+	populatorCR := &api.VSphereVolumePopulator{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", vm.ID),
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Labels: map[string]string{
+				"vmID":      vm.ID,
+				"migration": string(r.Migration.UID),
+				"diskID":    strconv.Itoa(int(disk.Key)),
+			},
+		},
+		Spec: api.VSphereVolumePopulatorSpec{
+			ServerURL:       r.Source.Provider.Spec.URL,
+			SecretName:      secretName,
+			Thumbprint:      r.Source.Provider.Status.Fingerprint,
+			VmID:            vm.ID,
+			BackingFile:     r.baseVolume(disk.File),
+			VddkImage:       r.Source.Provider.Spec.Settings[api.VDDK],
+			TransferNetwork: r.Plan.Spec.TransferNetwork,
+			RateLimit:       r.Context.RateLimit(len(vm.Disks)),
+		},
+	}
+	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	name = populatorCR.Name
+	return
+}
+
+// Access and volume modes set in the storage map or
+// otherwise defined by the storage profile.
+func (r *Builder) volumeAndAccessMode(mapped *api.StoragePair) (accessModes []core.PersistentVolumeAccessMode, volumeMode *core.PersistentVolumeMode, err error) {
+	if mapped.Destination.AccessMode != "" {
+		accessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
+	}
+	if mapped.Destination.VolumeMode != "" {
+		volumeMode = &mapped.Destination.VolumeMode
+	}
+	if accessModes != nil && volumeMode != nil {
+		return
+	}
+	storageClassName := mapped.Destination.StorageClass
+	storageProfile := &cdi.StorageProfile{}
+	err = r.Client.Get(context.TODO(), k8stypes.NamespacedName{Name: storageClassName}, storageProfile)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	claimProperties := storageProfile.Status.ClaimPropertySets
+	if len(claimProperties) == 0 || len(claimProperties[0].AccessModes) == 0 {
+		err = liberr.New("no accessMode defined on StorageProfile for StorageClass", "storageName", storageClassName)
+		return
+	}
+	if accessModes == nil {
+		accessModes = claimProperties[0].AccessModes
+	}
+	if volumeMode == nil {
+		volumeMode = claimProperties[0].VolumeMode
+		if volumeMode == nil {
+			filesystemMode := core.PersistentVolumeFilesystem
+			volumeMode = &filesystemMode
+		}
+	}
+	return
+}
+
+// Build a PersistentVolumeClaim with DataSourceRef for VolumePopulator
+func (r *Builder) persistentVolumeClaimWithSourceRef(
+	vmRef ref.Ref,
+	disk vsphere.Disk,
+	mapped *api.StoragePair,
+	populatorName string,
+	annotations map[string]string) (pvc *core.PersistentVolumeClaim, err error) {
+	accessModes, volumeMode, err := r.volumeAndAccessMode(mapped)
+	if err != nil {
+		return
+	}
+	diskSize := utils.CalculateSpaceWithOverhead(disk.Capacity, volumeMode)
+	pvcAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		pvcAnnotations[k] = v
+	}
+	pvcAnnotations[planbase.AnnDiskSource] = r.baseVolume(disk.File)
+	storageClassName := mapped.Destination.StorageClass
+
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", vmRef.ID),
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Annotations:  pvcAnnotations,
+			Labels: map[string]string{
+				"migration": string(r.Migration.UID),
+				"vmID":      vmRef.ID,
+				"diskID":    strconv.Itoa(int(disk.Key)),
+			},
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: core.ResourceRequirements{
+				Requests: map[core.ResourceName]resource.Quantity{
+					core.ResourceStorage: *resource.NewQuantity(diskSize, resource.BinarySI)},
+			},
+			StorageClassName: &storageClassName,
+			VolumeMode:       volumeMode,
+			DataSourceRef: &core.TypedObjectReference{
+				APIGroup: &api.SchemeGroupVersion.Group,
+				Kind:     api.VSphereVolumePopulatorKind,
+				Name:     populatorName,
+			},
+		},
+	}
+
+	err = r.Client.Create(context.TODO(), pvc, &client.CreateOptions{})
 	return
 }
 
 func (r *Builder) PrePopulateActions(c planbase.Client, vmRef ref.Ref) (ready bool, err error) {
-	err = planbase.VolumePopulatorNotSupportedError
+	ready = true
 	return
 }
 
-func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
-	err = planbase.VolumePopulatorNotSupportedError
+func (r *Builder) PopulatorTransferredBytes(pvc *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	populatorCr, err := r.getVolumePopulator(pvc.Labels["vmID"], pvc.Labels["diskID"])
+	if err != nil {
+		return
+	}
+	progressPercentage, err := strconv.ParseInt(populatorCr.Status.Progress, 10, 64)
+	if err != nil {
+		r.Log.Error(err, "Couldn't parse the progress percentage.", "pvcName", pvc.Name, "progress", populatorCr.Status.Progress)
+		err = nil
+		return
+	}
+	pvcSize := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	transferredBytes = (progressPercentage * pvcSize.Value()) / 100
 	return
 }
 
+// Sets the VSphereVolumePopulator CRs with VM ID and migration ID into the labels.
 func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.PersistentVolumeClaim) (err error) {
-	err = planbase.VolumePopulatorNotSupportedError
+	migrationID := string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID)
+	for _, pvc := range pvcs {
+		populatorCr, gErr := r.getVolumePopulator(vmRef.ID, pvc.Labels["diskID"])
+		if gErr != nil {
+			continue
+		}
+		populatorCrCopy := populatorCr.DeepCopy()
+		populatorCr.Labels["vmID"] = vmRef.ID
+		populatorCr.Labels["migration"] = migrationID
+		pErr := r.Destination.Client.Patch(context.TODO(), &populatorCr, client.MergeFrom(populatorCrCopy))
+		if pErr != nil {
+			r.Log.Error(pErr, "Couldn't update the Populator Custom Resource labels.",
+				"vmID", vmRef.ID, "migrationID", migrationID, "VSphereVolumePopulator", populatorCr.Name)
+		}
+	}
 	return
 }
 
 func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
-	err = planbase.VolumePopulatorNotSupportedError
+	taskName = pvc.Annotations[planbase.AnnDiskSource]
+	return
+}
+
+// Guest identity the migrated VM is expected to report.
+// The hostname reported by VMware tools and the static
+// IP addresses when they are preserved.
+func (r *Builder) GuestIdentity(vmRef ref.Ref) (identity planbase.GuestIdentity, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	identity.HostName = vm.HostName
+	if r.Plan.Spec.PreserveStaticIPs {
+		for _, network := range staticIPs(vm) {
+			identity.IPs = append(identity.IPs, network.IP)
+		}
+	}
 	return
 }
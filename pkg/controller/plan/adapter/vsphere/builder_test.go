@@ -1,16 +1,27 @@
 package vsphere
 
 import (
+	"errors"
+
 	v1beta1 "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/vsphere/offload"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/vmware/govmomi/vim25/types"
 	v1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -42,8 +53,258 @@ var _ = Describe("vSphere builder", func() {
 		Entry("non windows vm", &model.VM{GuestID: "other", GuestNetworks: []vsphere.GuestNetwork{{MAC: "00:50:56:83:25:47", IP: "172.29.3.193", Origin: ManualOrigin}}}, ""),
 		Entry("no OS vm", &model.VM{GuestNetworks: []vsphere.GuestNetwork{{MAC: "00:50:56:83:25:47", IP: "172.29.3.193", Origin: ManualOrigin}}}, ""),
 	)
+
+	Describe("volume populator", func() {
+		labels := map[string]string{
+			"migration": "m1",
+			"vmID":      "vm-1",
+			"diskID":    "2000",
+		}
+		pvc := &core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pvc",
+				Namespace:   "test",
+				Labels:      labels,
+				Annotations: map[string]string{planbase.AnnDiskSource: "[ds1] vm-1/vm-1.vmdk"},
+			},
+			Spec: core.PersistentVolumeClaimSpec{
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: *resource.NewQuantity(1024, resource.BinarySI),
+					},
+				},
+			},
+		}
+
+		It("should report the transferred bytes", func() {
+			builder := createBuilder(&v1beta1.VSphereVolumePopulator{
+				ObjectMeta: metav1.ObjectMeta{Name: "populator", Namespace: "test", Labels: labels},
+				Status:     v1beta1.VSphereVolumePopulatorStatus{Progress: "25"},
+			})
+			builder.Migration = &v1beta1.Migration{ObjectMeta: metav1.ObjectMeta{UID: "m1"}}
+			transferred, err := builder.PopulatorTransferredBytes(pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transferred).To(Equal(int64(256)))
+			taskName, err := builder.GetPopulatorTaskName(pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(taskName).To(Equal("[ds1] vm-1/vm-1.vmdk"))
+		})
+
+		It("should be enabled by the provider setting", func() {
+			builder := createBuilder()
+			builder.Source.Provider = &v1beta1.Provider{}
+			openShift := v1beta1.OpenShift
+			builder.Plan.Provider.Destination = &v1beta1.Provider{Spec: v1beta1.ProviderSpec{Type: &openShift}}
+			Expect(builder.SupportsVolumePopulators()).To(BeFalse())
+			builder.Source.Provider.Spec.Settings = map[string]string{v1beta1.VolumePopulator: "true"}
+			Expect(builder.SupportsVolumePopulators()).To(BeTrue())
+			builder.Plan.Spec.Warm = true
+			Expect(builder.SupportsVolumePopulators()).To(BeFalse())
+		})
+	})
+
+	Describe("storage offload", func() {
+		var builder *Builder
+		var array *offload.Fake
+		var vm *plan.VMStatus
+
+		BeforeEach(func() {
+			array = &offload.Fake{
+				Devices:        []string{"naa.1"},
+				StorageClasses: []string{"array"},
+			}
+			offload.Register("fake", array.Factory)
+			settings.Settings.Features.VsphereStorageOffload = true
+			vSphere := v1beta1.VSphere
+			openShift := v1beta1.OpenShift
+			builder = createBuilder()
+			builder.Source.Provider = &v1beta1.Provider{
+				Spec: v1beta1.ProviderSpec{
+					Type:     &vSphere,
+					Settings: map[string]string{v1beta1.StorageOffload: "fake"},
+				},
+			}
+			builder.Source.Inventory = &offloadInventory{}
+			builder.Plan.Referenced.Provider.Source = builder.Source.Provider
+			builder.Plan.Referenced.Provider.Destination = &v1beta1.Provider{Spec: v1beta1.ProviderSpec{Type: &openShift}}
+			builder.Map.Storage = &v1beta1.StorageMap{
+				Spec: v1beta1.StorageMapSpec{
+					Map: []v1beta1.StoragePair{
+						{Source: ref.Ref{ID: "ds-1"}, Destination: v1beta1.DestinationStorage{StorageClass: "array"}},
+						{Source: ref.Ref{ID: "ds-2"}, Destination: v1beta1.DestinationStorage{StorageClass: "array"}},
+					},
+				},
+			}
+			builder.Migration = &v1beta1.Migration{ObjectMeta: metav1.ObjectMeta{UID: "m1"}}
+			vm = &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+			builder.Plan.Status.Migration.VMs = []*plan.VMStatus{vm}
+			Expect(builder.loadOffload()).To(Succeed())
+		})
+
+		AfterEach(func() {
+			settings.Settings.Features.VsphereStorageOffload = false
+		})
+
+		It("should copy the disks with CDI", func() {
+			el9, err := builder.Plan.VSphereUsesEl9VirtV2v()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(el9).To(BeFalse())
+		})
+
+		It("should clone the disks on the array", func() {
+			pvcs, err := builder.LunPersistentVolumeClaims(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			pvs, err := builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(array.Clones).To(Equal(1))
+			Expect(pvs).To(HaveLen(1))
+			Expect(pvcs).To(HaveLen(1))
+			pv, pvc := pvs[0], pvcs[0]
+			Expect(pv.Name).To(Equal("vm-1-2000-m1"))
+			Expect(pv.Spec.ISCSI).ToNot(BeNil())
+			Expect(pv.Spec.StorageClassName).To(Equal("array"))
+			Expect(pvc.Spec.VolumeName).To(Equal(pv.Name))
+			Expect(*pvc.Spec.StorageClassName).To(Equal("array"))
+			Expect(pvc.Annotations).To(HaveKeyWithValue(planbase.AnnOffload, "fake"))
+			Expect(builder.ResolvePersistentVolumeClaimIdentifier(&pvc)).To(Equal("[ds1] vm-1/vm-1.vmdk"))
+			Expect(vm.Offload.Disks).To(HaveLen(1))
+			Expect(vm.Offload.Disks[0].Phase).To(Equal(plan.CloneCompleted))
+		})
+
+		It("should report the progress of the clones", func() {
+			array.Step = 0x10000000
+			pvcs, err := builder.LunPersistentVolumeClaims(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvcs).To(BeEmpty())
+			pvs, err := builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvs).To(BeEmpty())
+			disk := vm.Offload.Disks[0]
+			Expect(disk.Phase).To(Equal(plan.CloneRunning))
+			Expect(disk.Completed).To(Equal(int64(0x10000000)))
+			// next reconcile
+			builder.clones = nil
+			_, err = builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vm.Offload.Disks[0].Completed).To(Equal(int64(0x20000000)))
+			Expect(array.Clones).To(Equal(1))
+		})
+
+		It("should ask the array once which disks it clones", func() {
+			_, err := builder.DataVolumes(ref.Ref{ID: "vm-1"}, &core.Secret{}, nil, &cdi.DataVolume{})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = builder.LunPersistentVolumeClaims(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(array.Queries).To(Equal(2))
+			Expect(vm.Offload.Driver).To(Equal("fake"))
+			Expect(vm.Offload.Disks[0].File).To(Equal("[ds1] vm-1/vm-1.vmdk"))
+		})
+
+		It("should record the failed clones", func() {
+			array.CloneErr = "extent offline"
+			pvs, err := builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvs).To(BeEmpty())
+			Expect(vm.Offload.Disks[0].Phase).To(Equal(plan.CloneFailed))
+			Expect(vm.Offload.Disks[0].Error).To(Equal("extent offline"))
+		})
+
+		It("should copy the disks not on the array", func() {
+			dvs, err := builder.DataVolumes(ref.Ref{ID: "vm-1"}, &core.Secret{}, nil, &cdi.DataVolume{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvs).To(HaveLen(1))
+			Expect(dvs[0].Annotations).To(HaveKeyWithValue(planbase.AnnDiskSource, "[ds2] vm-1/vm-1_1.vmdk"))
+			Expect(dvs[0].Spec.Source.VDDK).ToNot(BeNil())
+		})
+
+		It("should copy all the disks of warm migrations", func() {
+			builder.Plan.Spec.Warm = true
+			pvs, err := builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvs).To(BeEmpty())
+			Expect(array.Clones).To(BeZero())
+		})
+
+		It("should copy the disks when the feature is disabled", func() {
+			settings.Settings.Features.VsphereStorageOffload = false
+			builder.offload = nil
+			Expect(builder.loadOffload()).To(Succeed())
+			dvs, err := builder.DataVolumes(ref.Ref{ID: "vm-1"}, &core.Secret{}, nil, &cdi.DataVolume{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvs).To(HaveLen(2))
+			Expect(array.Queries).To(BeZero())
+		})
+
+		It("should copy the disks when the driver is not registered", func() {
+			builder.Source.Provider.Spec.Settings[v1beta1.StorageOffload] = "unknown"
+			builder.offload = nil
+			Expect(builder.loadOffload()).To(Succeed())
+			dvs, err := builder.DataVolumes(ref.Ref{ID: "vm-1"}, &core.Secret{}, nil, &cdi.DataVolume{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvs).To(HaveLen(2))
+		})
+
+		It("should copy the disks the array fails to evaluate", func() {
+			array.QueryErr = errors.New("array offline")
+			dvs, err := builder.DataVolumes(ref.Ref{ID: "vm-1"}, &core.Secret{}, nil, &cdi.DataVolume{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dvs).To(HaveLen(2))
+			Expect(array.Clones).To(BeZero())
+		})
+
+		It("should validate the datastores are backed by devices", func() {
+			inventory := &offloadInventory{}
+			validator := &Validator{plan: builder.Plan, inventory: inventory}
+			ok, err := validator.StorageOffload(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			inventory.nfs = "ds-2"
+			ok, err = validator.StorageOffload(ref.Ref{ID: "vm-1"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should fail when the array fails to clone", func() {
+			array.Err = errors.New("array offline")
+			_, err := builder.LunPersistentVolumes(ref.Ref{ID: "vm-1"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
 
+// Inventory of a VM with disks on two datastores,
+// the first backed by a device on the array.
+type offloadInventory struct {
+	mockInventory
+	// Datastore not backed by devices (NFS).
+	nfs string
+}
+
+func (m *offloadInventory) Find(resource interface{}, ref ref.Ref) error {
+	switch res := resource.(type) {
+	case *model.VM:
+		res.ID = ref.ID
+		res.Disks = []vsphere.Disk{
+			{Key: 2000, File: "[ds1] vm-1/vm-1.vmdk", Datastore: vsphere.Ref{ID: "ds-1"}, Capacity: 0x40000000},
+			{Key: 2001, File: "[ds2] vm-1/vm-1_1.vmdk", Datastore: vsphere.Ref{ID: "ds-2"}, Capacity: 0x40000000},
+		}
+	case *model.Datastore:
+		res.ID = ref.ID
+		switch ref.ID {
+		case "ds-1":
+			res.BackingDevices = []string{"naa.1"}
+		case "ds-2":
+			res.BackingDevices = []string{"naa.2"}
+		}
+		if ref.ID == m.nfs {
+			res.BackingDevices = nil
+		}
+	}
+	return nil
+}
+
 //nolint:errcheck
 func createBuilder(objs ...runtime.Object) *Builder {
 	scheme := runtime.NewScheme()
@@ -1,20 +1,136 @@
 package vsphere
 
 import (
+	"context"
+	"path"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type DestinationClient struct {
 	*plancontext.Context
 }
 
-func (d *DestinationClient) DeletePopulatorDataSource(vm *plan.VMStatus) error {
-	// not supported - do nothing
+// Delete the VSphereVolumePopulator CustomResources of the VM.
+func (r *DestinationClient) DeletePopulatorDataSource(vm *plan.VMStatus) error {
+	populatorCrList, err := r.getPopulatorCrList(map[string]string{"vmID": vm.ID})
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for i := range populatorCrList.Items {
+		populatorCr := &populatorCrList.Items[i]
+		err = r.DeleteObject(populatorCr, vm, "Deleted VSpherePopulator CR.", "VSphereVolumePopulator")
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
 	return nil
 }
 
+// Set the VSphereVolumePopulator CustomResource Ownership.
 func (r *DestinationClient) SetPopulatorCrOwnership() (err error) {
-	// not supported - do nothing
+	populatorCrList, err := r.getPopulatorCrList(nil)
+	if err != nil {
+		return
+	}
+
+	for i := range populatorCrList.Items {
+		populatorCr := &populatorCrList.Items[i]
+		pvc, err := r.findPVCByCR(populatorCr)
+		if err != nil {
+			continue
+		}
+
+		populatorCrCopy := populatorCr.DeepCopy()
+		err = k8sutil.SetOwnerReference(pvc, populatorCr, r.Scheme())
+		if err != nil {
+			continue
+		}
+		patch := client.MergeFrom(populatorCrCopy)
+		err = r.Destination.Client.Patch(context.TODO(), populatorCr, patch)
+		if err != nil {
+			continue
+		}
+	}
+	return
+}
+
+// Get the VSphereVolumePopulator CustomResource List.
+func (r *DestinationClient) getPopulatorCrList(selector map[string]string) (populatorCrList v1beta1.VSphereVolumePopulatorList, err error) {
+	set := map[string]string{"migration": string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID)}
+	for k, v := range selector {
+		set[k] = v
+	}
+	populatorCrList = v1beta1.VSphereVolumePopulatorList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		&populatorCrList,
+		&client.ListOptions{
+			Namespace:     r.Plan.Spec.TargetNamespace,
+			LabelSelector: labels.SelectorFromSet(set),
+		})
+	return
+}
+
+// Deletes an object from destination cluster associated with the VM.
+func (r *DestinationClient) DeleteObject(object client.Object, vm *plan.VMStatus, message, objType string) (err error) {
+	err = r.Destination.Client.Delete(context.TODO(), object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			return liberr.Wrap(err)
+		}
+	} else {
+		r.Log.Info(
+			message,
+			objType,
+			path.Join(
+				object.GetNamespace(),
+				object.GetName()),
+			"vm",
+			vm.String())
+	}
+	return
+}
+
+func (r *DestinationClient) findPVCByCR(cr *v1beta1.VSphereVolumePopulator) (pvc *core.PersistentVolumeClaim, err error) {
+	pvcList := core.PersistentVolumeClaimList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		&pvcList,
+		&client.ListOptions{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			LabelSelector: labels.SelectorFromSet(map[string]string{
+				"migration": string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID),
+				"vmID":      cr.Labels["vmID"],
+				"diskID":    cr.Labels["diskID"],
+			}),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	if len(pvcList.Items) == 0 {
+		err = liberr.New("PVC not found", "vmID", cr.Labels["vmID"], "diskID", cr.Labels["diskID"])
+		return
+	}
+
+	if len(pvcList.Items) > 1 {
+		err = liberr.New("Multiple PVCs found", "vmID", cr.Labels["vmID"], "diskID", cr.Labels["diskID"])
+		return
+	}
+
+	pvc = &pvcList.Items[0]
+
 	return
 }
@@ -0,0 +1,303 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/vsphere/offload"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Load the storage offload driver named by
+// the provider `storageOffload` setting.
+// The disks are copied when the feature is disabled or
+// the driver is not registered, as reported by the plan
+// validation.
+func (r *Builder) loadOffload() (err error) {
+	provider := r.Source.Provider
+	if !offloadEnabled(provider) {
+		return
+	}
+	secret := &core.Secret{}
+	if name := provider.Spec.Settings[api.StorageOffloadSecret]; name != "" {
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: provider.Namespace,
+				Name:      name,
+			},
+			secret)
+		if err != nil {
+			err = liberr.Wrap(err, "secret", name)
+			return
+		}
+	}
+	r.offload, err = offload.New(provider.Spec.Settings[api.StorageOffload], secret)
+	return
+}
+
+// The storage offload is used by the provider.
+// Set by the `storageOffload` setting naming a registered
+// driver, with the storage offload feature enabled.
+func offloadEnabled(provider *api.Provider) bool {
+	return settings.Settings.Features.VsphereStorageOffload &&
+		provider.UsesStorageOffload() &&
+		offload.Registered(provider.Spec.Settings[api.StorageOffload])
+}
+
+// The disks of the VM cloned by the storage array.
+// Recorded on the VM status when first evaluated so that
+// the array is asked once which disks it can clone.
+// Returns nil when the disks are copied: the storage offload
+// is not used or the plan copies a snapshot.
+func (r *Builder) offloaded(vm *model.VM) (offloaded *plan.Offload, err error) {
+	if r.offload == nil || r.Plan.SnapshotCopy() {
+		return
+	}
+	vmStatus, found := r.Plan.Status.Migration.FindVM(ref.Ref{ID: vm.ID})
+	if found && vmStatus.Offload != nil {
+		offloaded = vmStatus.Offload
+		return
+	}
+	offloaded = &plan.Offload{
+		Driver: r.Source.Provider.Spec.Settings[api.StorageOffload],
+	}
+	for _, disk := range vm.Disks {
+		var request *offload.CloneRequest
+		request, err = r.offloadRequest(vm, disk)
+		if err != nil {
+			return
+		}
+		if request == nil {
+			continue
+		}
+		offloaded.Disks = append(
+			offloaded.Disks,
+			plan.OffloadedDisk{
+				Name:         request.Name,
+				File:         request.File,
+				StorageClass: request.StorageClass,
+				Devices:      request.Devices,
+				Capacity:     request.Capacity,
+				Phase:        plan.ClonePending,
+			})
+	}
+	if found {
+		vmStatus.Offload = offloaded
+	}
+	return
+}
+
+// The disk is cloned by the storage array.
+func (r *Builder) isOffloaded(vm *model.VM, disk vsphere.Disk) (cloned bool, err error) {
+	offloaded, err := r.offloaded(vm)
+	if err != nil || offloaded == nil {
+		return
+	}
+	_, cloned = offloaded.FindDisk(r.baseVolume(disk.File))
+	return
+}
+
+// Request to clone the disk on the storage array.
+// Returns nil when the array does not support the disk.
+func (r *Builder) offloadRequest(vm *model.VM, disk vsphere.Disk) (request *offload.CloneRequest, err error) {
+	if disk.RDM {
+		return
+	}
+	mapped, ds, err := r.storagePair(disk)
+	if err != nil || mapped == nil {
+		return
+	}
+	candidate := &offload.CloneRequest{
+		Name:         fmt.Sprintf("%s-%d-%s", vm.ID, disk.Key, r.Migration.UID),
+		File:         r.baseVolume(disk.File),
+		Capacity:     disk.Capacity,
+		Devices:      ds.BackingDevices,
+		StorageClass: mapped.Destination.StorageClass,
+	}
+	// The disks the array fails to evaluate are copied.
+	supported, sErr := r.offload.Supports(candidate)
+	if sErr != nil {
+		r.Log.Error(sErr, "Storage offload not evaluated, the disk is copied.", "disk", disk.File)
+		return
+	}
+	if supported {
+		request = candidate
+	}
+	return
+}
+
+// Find the storage map pair and the datastore of the disk.
+func (r *Builder) storagePair(disk vsphere.Disk) (mapped *api.StoragePair, ds *model.Datastore, err error) {
+	dsMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range dsMapIn {
+		ds = &model.Datastore{}
+		err = r.Source.Inventory.Find(ds, dsMapIn[i].Source)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if ds.ID == disk.Datastore.ID {
+			mapped = &dsMapIn[i]
+			return
+		}
+	}
+	ds = nil
+	return
+}
+
+// Build the volumes of the disks cloned by the array.
+// The clones are started on the array and polled once per
+// reconcile, the clone state being recorded on the disks.
+// The volumes are built for the completed clones.
+func (r *Builder) offloadVolumes(vmRef ref.Ref, build func(*model.VM, *plan.OffloadedDisk, *offload.Volume)) (err error) {
+	if r.offload == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	offloaded, err := r.offloaded(vm)
+	if err != nil || offloaded == nil {
+		return
+	}
+	for i := range offloaded.Disks {
+		disk := &offloaded.Disks[i]
+		var volume *offload.Volume
+		volume, err = r.cloneVolume(disk)
+		if err != nil {
+			return
+		}
+		if volume != nil {
+			build(vm, disk, volume)
+		}
+	}
+	return
+}
+
+// Clone the disk on the array.
+// Starts the clone when pending and updates the disk
+// with the status of the clone. Returns the volume when
+// the clone has completed.
+func (r *Builder) cloneVolume(disk *plan.OffloadedDisk) (volume *offload.Volume, err error) {
+	volume, polled := r.clones[disk.Name]
+	if polled {
+		return
+	}
+	if disk.Phase == plan.CloneFailed {
+		return
+	}
+	request := &offload.CloneRequest{
+		Name:         disk.Name,
+		File:         disk.File,
+		Capacity:     disk.Capacity,
+		Devices:      disk.Devices,
+		StorageClass: disk.StorageClass,
+	}
+	if disk.Phase == plan.ClonePending {
+		err = r.offload.Start(request)
+		if err != nil {
+			err = liberr.Wrap(err, "disk", disk.File)
+			return
+		}
+		disk.Phase = plan.CloneRunning
+	}
+	status, err := r.offload.Status(request)
+	if err != nil {
+		err = liberr.Wrap(err, "disk", disk.File)
+		return
+	}
+	disk.Completed = status.Completed
+	switch {
+	case status.Error != "":
+		disk.Phase = plan.CloneFailed
+		disk.Error = status.Error
+	case status.Volume != nil:
+		disk.Phase = plan.CloneCompleted
+		disk.Completed = disk.Capacity
+		volume = status.Volume
+	}
+	if r.clones == nil {
+		r.clones = map[string]*offload.Volume{}
+	}
+	r.clones[disk.Name] = volume
+	return
+}
+
+// Build the PV exposing a cloned volume.
+func (r *Builder) offloadPersistentVolume(vm *model.VM, disk *plan.OffloadedDisk, volume *offload.Volume) core.PersistentVolume {
+	volMode := core.PersistentVolumeBlock
+	return core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        volume.Name,
+			Annotations: r.offloadAnnotations(disk),
+			Labels:      r.offloadLabels(vm, disk),
+		},
+		Spec: core.PersistentVolumeSpec{
+			PersistentVolumeSource: volume.Source,
+			Capacity: core.ResourceList{
+				core.ResourceStorage: *resource.NewQuantity(volume.Capacity, resource.BinarySI),
+			},
+			AccessModes:      volume.AccessModes,
+			StorageClassName: disk.StorageClass,
+			VolumeMode:       &volMode,
+		},
+	}
+}
+
+// Build the PVC bound to the PV exposing a cloned volume.
+func (r *Builder) offloadPersistentVolumeClaim(vm *model.VM, disk *plan.OffloadedDisk, volume *offload.Volume) core.PersistentVolumeClaim {
+	volMode := core.PersistentVolumeBlock
+	storageClass := disk.StorageClass
+	return core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        disk.Name,
+			Namespace:   r.Plan.Spec.TargetNamespace,
+			Annotations: r.offloadAnnotations(disk),
+			Labels:      r.offloadLabels(vm, disk),
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: volume.AccessModes,
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: *resource.NewQuantity(volume.Capacity, resource.BinarySI),
+				},
+			},
+			StorageClassName: &storageClass,
+			VolumeMode:       &volMode,
+			VolumeName:       volume.Name,
+		},
+	}
+}
+
+// Annotations of the PV and PVC of a cloned volume.
+func (r *Builder) offloadAnnotations(disk *plan.OffloadedDisk) map[string]string {
+	return map[string]string{
+		planbase.AnnDiskSource: disk.File,
+		planbase.AnnOffload:    r.Source.Provider.Spec.Settings[api.StorageOffload],
+	}
+}
+
+// Labels of the PV and PVC of a cloned volume.
+func (r *Builder) offloadLabels(vm *model.VM, disk *plan.OffloadedDisk) map[string]string {
+	return map[string]string{
+		"volume":    disk.Name,
+		"vmID":      vm.ID,
+		"plan":      string(r.Plan.UID),
+		"migration": string(r.Migration.UID),
+	}
+}
@@ -0,0 +1,142 @@
+package offload
+
+import (
+	"slices"
+	"sync"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+)
+
+// Fake array driver (for tests).
+// Clones the disks on the array devices into
+// iSCSI volumes served by the fake array.
+type Fake struct {
+	// Devices (NAA IDs) on the array.
+	Devices []string
+	// Storage classes provisioned on the array.
+	StorageClasses []string
+	// Error returned by Start.
+	Err error
+	// Error returned by Supports.
+	QueryErr error
+	// Error reported by the clones.
+	CloneErr string
+	// Bytes cloned between status calls,
+	// the clones complete at once when not set.
+	Step int64
+	// Cloned volumes by name.
+	Volumes map[string]*Volume
+	// Bytes cloned by name.
+	Cloned map[string]int64
+	// Number of clones started on the array.
+	Clones int
+	// Number of calls to Supports.
+	Queries int
+	mutex   sync.Mutex
+}
+
+// Factory returning the fake driver.
+func (r *Fake) Factory(*core.Secret) (Driver, error) {
+	return r, nil
+}
+
+// The devices and storage class are on the array.
+func (r *Fake) Supports(request *CloneRequest) (supported bool, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Queries++
+	if r.QueryErr != nil {
+		err = r.QueryErr
+		return
+	}
+	supported = r.supports(request)
+	return
+}
+
+// The devices and storage class are on the array.
+func (r *Fake) supports(request *CloneRequest) bool {
+	if len(request.Devices) == 0 || !slices.Contains(r.StorageClasses, request.StorageClass) {
+		return false
+	}
+	for _, device := range request.Devices {
+		if !slices.Contains(r.Devices, device) {
+			return false
+		}
+	}
+	return true
+}
+
+// Start cloning the disk.
+func (r *Fake) Start(request *CloneRequest) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.Err != nil {
+		err = r.Err
+		return
+	}
+	if _, found := r.Cloned[request.Name]; found {
+		return
+	}
+	if !r.supports(request) {
+		err = liberr.New("disk not on the array.", "file", request.File)
+		return
+	}
+	if r.Cloned == nil {
+		r.Cloned = map[string]int64{}
+	}
+	r.Clones++
+	r.Cloned[request.Name] = 0
+	return
+}
+
+// Status of the clone, advanced by the step.
+// The clone completes into an iSCSI volume.
+func (r *Fake) Status(request *CloneRequest) (status *CloneStatus, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	cloned, found := r.Cloned[request.Name]
+	if !found {
+		err = liberr.New("clone not started.", "name", request.Name)
+		return
+	}
+	status = &CloneStatus{}
+	if r.CloneErr != "" {
+		status.Completed = cloned
+		status.Error = r.CloneErr
+		return
+	}
+	if r.Step > 0 {
+		cloned = min(cloned+r.Step, request.Capacity)
+	} else {
+		cloned = request.Capacity
+	}
+	r.Cloned[request.Name] = cloned
+	status.Completed = cloned
+	if cloned < request.Capacity {
+		return
+	}
+	volume, found := r.Volumes[request.Name]
+	if !found {
+		if r.Volumes == nil {
+			r.Volumes = map[string]*Volume{}
+		}
+		volume = &Volume{
+			Name:     request.Name,
+			Capacity: request.Capacity,
+			AccessModes: []core.PersistentVolumeAccessMode{
+				core.ReadWriteMany,
+			},
+			Source: core.PersistentVolumeSource{
+				ISCSI: &core.ISCSIPersistentVolumeSource{
+					TargetPortal: "fake-array:3260",
+					IQN:          "iqn.2024-01.io.konveyor.forklift:fake-array",
+					Lun:          int32(len(r.Volumes) + 1),
+				},
+			},
+		}
+		r.Volumes[request.Name] = volume
+	}
+	status.Volume = volume
+	return
+}
@@ -0,0 +1,127 @@
+package offload
+
+import (
+	"sort"
+	"sync"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+)
+
+// Storage array driver.
+// Clones the extents backing a VMDK directly into a new
+// volume on the array (XCOPY / array-side clone) when the
+// VMFS datastore and the destination storage class are
+// on the same array. The clones run on the array, they are
+// started and then polled until they complete.
+type Driver interface {
+	// The disk can be cloned by the array.
+	// The datastore devices and the storage class must both
+	// be on the array. Must be deterministic for a request.
+	Supports(request *CloneRequest) (bool, error)
+	// Start cloning the disk into a volume.
+	// Idempotent: starting a clone already started
+	// does not start it again.
+	Start(request *CloneRequest) error
+	// Status of the clone started for the request.
+	Status(request *CloneRequest) (*CloneStatus, error)
+}
+
+// Driver factory.
+// Builds a driver using the settings and the array
+// credentials found in the secret.
+type Factory func(secret *core.Secret) (Driver, error)
+
+// Request to clone a disk.
+type CloneRequest struct {
+	// Name of the volume.
+	// Stable across retries.
+	Name string
+	// VMDK (base) backing file.
+	// Example: [datastore1] vm/vm.vmdk
+	File string
+	// Disk capacity (bytes).
+	Capacity int64
+	// Devices (NAA IDs) backing the VMFS datastore.
+	Devices []string
+	// Destination storage class.
+	StorageClass string
+}
+
+// Volume cloned by the array.
+type Volume struct {
+	// Name.
+	Name string
+	// Capacity (bytes).
+	Capacity int64
+	// Access modes supported by the volume.
+	AccessModes []core.PersistentVolumeAccessMode
+	// Source of the PV exposing the volume (block).
+	Source core.PersistentVolumeSource
+}
+
+// Status of a clone.
+type CloneStatus struct {
+	// Bytes cloned.
+	Completed int64
+	// Volume, set when the clone has completed.
+	Volume *Volume
+	// Error reported by the array when the clone failed.
+	Error string
+}
+
+// Registered driver factories.
+var drivers = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{
+	factories: map[string]Factory{},
+}
+
+// Register a driver factory by name.
+// Drivers are expected to register when initialized.
+func Register(name string, factory Factory) {
+	drivers.Lock()
+	defer drivers.Unlock()
+	drivers.factories[name] = factory
+}
+
+// Names of the registered drivers.
+func Drivers() (names []string) {
+	drivers.RLock()
+	defer drivers.RUnlock()
+	for name := range drivers.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// The named driver is registered.
+func Registered(name string) bool {
+	drivers.RLock()
+	defer drivers.RUnlock()
+	_, found := drivers.factories[name]
+	return found
+}
+
+// Build the named driver.
+func New(name string, secret *core.Secret) (driver Driver, err error) {
+	drivers.RLock()
+	factory, found := drivers.factories[name]
+	drivers.RUnlock()
+	if !found {
+		err = liberr.New(
+			"storage offload driver not registered.",
+			"driver",
+			name,
+			"registered",
+			Drivers())
+		return
+	}
+	driver, err = factory(secret)
+	if err != nil {
+		err = liberr.Wrap(err, "driver", name)
+	}
+	return
+}
@@ -0,0 +1,13 @@
+package offload
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOffload(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Storage Offload Suite")
+}
@@ -0,0 +1,95 @@
+package offload
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage offload", func() {
+	var fake *Fake
+	var request *CloneRequest
+
+	BeforeEach(func() {
+		fake = &Fake{
+			Devices:        []string{"naa.1", "naa.2"},
+			StorageClasses: []string{"array"},
+		}
+		request = &CloneRequest{
+			Name:         "vm-1-2000",
+			File:         "[ds1] vm-1/vm-1.vmdk",
+			Capacity:     1024,
+			Devices:      []string{"naa.1"},
+			StorageClass: "array",
+		}
+	})
+
+	It("should build the registered driver", func() {
+		Register("fake", fake.Factory)
+		driver, err := New("fake", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(driver).To(BeIdenticalTo(fake))
+		Expect(Drivers()).To(ContainElement("fake"))
+		_, err = New("unknown", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("should support disks on the array", func(devices []string, storageClass string, supported bool) {
+		request.Devices = devices
+		request.StorageClass = storageClass
+		Expect(fake.Supports(request)).To(Equal(supported))
+	},
+		Entry("on the array", []string{"naa.1", "naa.2"}, "array", true),
+		Entry("device not on the array", []string{"naa.1", "naa.3"}, "array", false),
+		Entry("storage class not on the array", []string{"naa.1"}, "other", false),
+		Entry("devices not known", nil, "array", false),
+	)
+
+	It("should clone the disk once", func() {
+		Expect(fake.Start(request)).To(Succeed())
+		status, err := fake.Status(request)
+		Expect(err).ToNot(HaveOccurred())
+		volume := status.Volume
+		Expect(volume.Name).To(Equal(request.Name))
+		Expect(volume.Capacity).To(Equal(request.Capacity))
+		Expect(volume.Source.ISCSI).ToNot(BeNil())
+		Expect(fake.Start(request)).To(Succeed())
+		again, err := fake.Status(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again.Volume).To(BeIdenticalTo(volume))
+		Expect(fake.Clones).To(Equal(1))
+	})
+
+	It("should report the progress of the clone", func() {
+		fake.Step = 512
+		Expect(fake.Start(request)).To(Succeed())
+		status, err := fake.Status(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Completed).To(Equal(int64(512)))
+		Expect(status.Volume).To(BeNil())
+		status, err = fake.Status(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Completed).To(Equal(int64(1024)))
+		Expect(status.Volume).ToNot(BeNil())
+	})
+
+	It("should report the failed clones", func() {
+		fake.CloneErr = "extent offline"
+		Expect(fake.Start(request)).To(Succeed())
+		status, err := fake.Status(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Error).To(Equal("extent offline"))
+		Expect(status.Volume).To(BeNil())
+	})
+
+	It("should not clone unsupported disks", func() {
+		request.StorageClass = "other"
+		Expect(fake.Start(request)).ToNot(Succeed())
+		_, err := fake.Status(request)
+		Expect(err).To(HaveOccurred())
+		fake.Err = errors.New("array offline")
+		request.StorageClass = "array"
+		Expect(fake.Start(request)).To(MatchError("array offline"))
+	})
+})
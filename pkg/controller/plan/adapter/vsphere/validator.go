@@ -1,8 +1,11 @@
 package vsphere
 
 import (
+	"strings"
+
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -128,6 +131,39 @@ func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
 	return true, nil
 }
 
+// Validate that a VM's disks are on datastores backed by
+// devices the storage array may clone. The disks on other
+// datastores (NFS, vSAN) are copied.
+func (r *Validator) StorageOffload(vmRef ref.Ref) (ok bool, err error) {
+	if !offloadEnabled(r.plan.Referenced.Provider.Source) || r.plan.SnapshotCopy() {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		if disk.RDM {
+			continue
+		}
+		ds := &model.Datastore{}
+		dsRef := ref.Ref{ID: disk.Datastore.ID}
+		err = r.inventory.Find(ds, dsRef)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vmRef.String(), "datastore", dsRef.String())
+			return
+		}
+		if len(ds.BackingDevices) == 0 {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
 // Validate that we have information about static IPs for every virtual NIC
 func (r *Validator) StaticIPs(vmRef ref.Ref) (ok bool, err error) {
 	if !r.plan.Spec.PreserveStaticIPs {
@@ -158,3 +194,55 @@ func (r *Validator) StaticIPs(vmRef ref.Ref) (ok bool, err error) {
 	ok = true
 	return
 }
+
+// Validate that a VM is within the provider scope.
+// The VM (folder) path or the path of the host on
+// which it runs must be within a scope path.
+func (r *Validator) InScope(vmRef ref.Ref) (ok bool, err error) {
+	scope := r.plan.Referenced.Provider.Source.Scope()
+	if len(scope) == 0 {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	paths := []string{vm.Path}
+	if vm.Host != "" {
+		host := &model.Host{}
+		hostRef := ref.Ref{ID: vm.Host}
+		err = r.inventory.Find(host, hostRef)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vmRef.String(), "host", hostRef.String())
+			return
+		}
+		paths = append(paths, host.Path)
+	}
+	for _, scoped := range scope {
+		scoped = "/" + strings.Trim(scoped, "/") + "/"
+		for _, p := range paths {
+			if strings.HasPrefix(p, scoped) {
+				ok = true
+				return
+			}
+		}
+	}
+	return
+}
+
+// List the concerns reported by the inventory for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planbase.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	concerns = vm.Concerns
+
+	return
+}
@@ -36,6 +36,11 @@ func (m *mockInventory) Find(resource interface{}, ref ref.Ref) error {
 		if ref.Name == "not_windows_guest" {
 			res.VM.GuestID = "rhel8_64Guest"
 		}
+	case *model.VM:
+		res.Path = "/dc1/vm/" + ref.Name
+		res.Host = "host-1"
+	case *model.Host:
+		res.Path = "/dc1/host/cluster1/esx1"
 	}
 	return nil
 }
@@ -104,6 +109,31 @@ var _ = Describe("vsphere validation tests", func() {
 			Entry("when the vm doesn't have static ips, and the plan set without static ip, vm is non-windows", "not_windows_guest", true, false),
 		)
 	})
+	Describe("validateInScope", func() {
+		DescribeTable("should validate the provider scope",
+			func(scope string, inScope bool) {
+				plan := createPlan()
+				plan.Referenced.Provider.Source = &v1beta1.Provider{
+					Spec: v1beta1.ProviderSpec{
+						Settings: map[string]string{v1beta1.Scope: scope},
+					},
+				}
+				validator := &Validator{
+					plan:      plan,
+					inventory: &mockInventory{},
+				}
+				ok, err := validator.InScope(ref.Ref{Name: "test"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ok).To(Equal(inScope))
+			},
+			Entry("when the provider is not scoped", "", true),
+			Entry("when the datacenter is scoped", "/dc1", true),
+			Entry("when the folder is scoped", "/dc1/vm", true),
+			Entry("when the host cluster is scoped", "/dc2/host/other, /dc1/host/cluster1", true),
+			Entry("when another cluster is scoped", "/dc1/host/cluster2", false),
+			Entry("when a name prefix is scoped", "/dc", false),
+		)
+	})
 })
 
 func createPlan() *v1beta1.Plan {
@@ -5,11 +5,14 @@ import (
 	"path"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	ocp "github.com/konveyor/forklift-controller/pkg/lib/client/openshift"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -159,6 +162,8 @@ type Destination struct {
 	Provider *api.Provider
 	// Provider API client.
 	Inventory web.Client
+	// REST configuration.
+	RestCfg *rest.Config
 }
 
 // Build.
@@ -190,12 +195,14 @@ func (r *Destination) build(ctx *Context) (err error) {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.RestCfg = ocp.RestCfg(r.Provider, secret)
 	} else {
 		r.Client, err = ocp.Client(r.Provider, nil)
 		if err != nil {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.RestCfg = ocp.RestCfg(r.Provider, nil)
 	}
 	r.Inventory, err = web.NewClient(r.Provider)
 	if err != nil {
@@ -218,3 +225,21 @@ func (r *Context) FindHook(ref core.ObjectReference) (hook *api.Hook, found bool
 
 	return
 }
+
+// Rate limit of each disk transfer of a VM with the number of disks.
+// The plan bandwidth is split evenly between the VMs that may be
+// migrated concurrently (at most the max in-flight). The split is
+// static, a best-effort per-VM share: the running transfers are not
+// sped up when fewer VMs are left to migrate.
+// Returns nil when the bandwidth is not limited.
+func (r *Context) RateLimit(disks int) *planapi.RateLimit {
+	limit := r.Plan.Spec.BandwidthLimit
+	if limit == nil {
+		return nil
+	}
+	vms := len(r.Plan.Spec.VMs)
+	if inFlight := settings.Settings.Migration.MaxInFlight; inFlight > 0 && vms > inFlight {
+		vms = inFlight
+	}
+	return limit.RateLimit(vms, disks)
+}
@@ -180,6 +180,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("Plan deleted.")
+			destinationCache.Delete(request.NamespacedName)
 			err = nil
 		}
 		return
@@ -224,8 +225,15 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		r.archive(plan)
 	}
 
+	// Clean up the test failover.
+	if plan.Spec.Test != nil && plan.Spec.Test.Cleanup {
+		r.cleanupRehearsal(plan)
+	} else {
+		plan.Status.DeleteCondition(RehearsalCleanedUp)
+	}
+
 	// Ready condition.
-	if !plan.Status.HasBlockerCondition() && !plan.Status.HasCondition(Archived) && !plan.Status.HasCondition(ValidatingVDDK) {
+	if !plan.Status.HasBlockerCondition() && !plan.Status.HasAnyCondition(Archived, ValidatingVDDK, RehearsalCleanedUp) {
 		plan.Status.SetCondition(libcnd.Condition{
 			Type:     libcnd.Ready,
 			Status:   True,
@@ -315,6 +323,41 @@ func (r *Reconciler) archive(plan *api.Plan) {
 	r.Log.Info("Plan archived.")
 }
 
+// Clean up the test failover.
+// Deletes the test VMs once the rehearsal is no longer
+// executing. The cleanup is recorded (durable condition)
+// and is not repeated until requested again.
+func (r *Reconciler) cleanupRehearsal(plan *api.Plan) {
+	if plan.Status.HasCondition(RehearsalCleanedUp) {
+		return
+	}
+	if plan.Status.Rehearsal.ActiveSnapshot().HasCondition(Executing) {
+		r.Log.Info("Rehearsal cleanup postponed; rehearsal executing.")
+		return
+	}
+	restore := plan.Rehearse()
+	defer restore()
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		r.Log.Error(err, "Couldn't construct plan context while cleaning up rehearsal.")
+		return
+	}
+	runner := Migration{Context: ctx}
+	if !runner.CleanupRehearsal() {
+		return
+	}
+	plan.Status.SetCondition(
+		libcnd.Condition{
+			Type:     RehearsalCleanedUp,
+			Status:   True,
+			Category: Advisory,
+			Reason:   UserRequested,
+			Message:  "The test failover has been cleaned up.",
+			Durable:  true,
+		})
+	r.Log.Info("Rehearsal cleaned up.")
+}
+
 // Execute the plan.
 //  1. Find active (current) migration.
 //  2. If found, update the context and match the snapshot.
@@ -324,7 +367,7 @@ func (r *Reconciler) archive(plan *api.Plan) {
 //  6. Run the migration.
 func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	conditionRequiresReQ := plan.Status.HasReQCondition()
-	if plan.Status.HasBlockerCondition() || plan.Status.HasCondition(Archived) || conditionRequiresReQ {
+	if plan.Status.HasBlockerCondition() || plan.Status.HasAnyCondition(Archived, RehearsalCleanedUp) || conditionRequiresReQ {
 		if conditionRequiresReQ {
 			reQ = base.SlowReQ
 		}
@@ -338,6 +381,10 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 			}
 		}
 	}()
+	//
+	// Test failover runs against the rehearsal status.
+	restore := plan.Rehearse()
+	defer restore()
 	ctx, err := plancontext.New(r, plan, r.Log)
 	if err != nil {
 		return
@@ -403,7 +450,11 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		migration = pending[0]
 		ctx.SetMigration(migration)
 		snapshot = r.newSnapshot(ctx)
-		plan.Status.DeleteCondition(Failed, Canceled)
+		if plan.Spec.Test != nil {
+			plan.Status.DeleteCondition(Rehearsed)
+		} else {
+			plan.Status.DeleteCondition(Failed, Canceled)
+		}
 		r.Log.Info(
 			"Found (new) migration.",
 			"migration",
@@ -432,6 +483,24 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	snapshot.EndStagingConditions()
 
 	// Reflect the active snapshot status on the plan.
+	if plan.Spec.Test != nil {
+		r.reflectRehearsal(plan, snapshot)
+	} else {
+		r.reflectSnapshot(plan, snapshot)
+	}
+	if len(pending) > 1 && reQ == 0 {
+		r.Log.V(1).Info(
+			"Found pending migrations.",
+			"count",
+			len(pending))
+		reQ = base.FastReQ
+	}
+
+	return
+}
+
+// Reflect the active snapshot status on the plan.
+func (r *Reconciler) reflectSnapshot(plan *api.Plan, snapshot *planapi.Snapshot) {
 	for _, t := range []string{Executing, Succeeded, Failed, Canceled} {
 		if cnd := snapshot.FindCondition(t); cnd != nil {
 			r.Log.V(2).Info(
@@ -447,15 +516,31 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 				t)
 		}
 	}
-	if len(pending) > 1 && reQ == 0 {
-		r.Log.V(1).Info(
-			"Found pending migrations.",
-			"count",
-			len(pending))
-		reQ = base.FastReQ
-	}
+}
 
-	return
+// Reflect the active (rehearsal) snapshot status on the plan.
+// The outcome is reported by the Rehearsed condition so the
+// migration conditions are not affected.
+func (r *Reconciler) reflectRehearsal(plan *api.Plan, snapshot *planapi.Snapshot) {
+	if cnd := snapshot.FindCondition(Executing); cnd != nil {
+		plan.Status.SetCondition(*cnd)
+	} else {
+		plan.Status.DeleteCondition(Executing)
+	}
+	for _, t := range []string{Succeeded, Failed, Canceled} {
+		if snapshot.HasCondition(t) {
+			plan.Status.SetCondition(
+				libcnd.Condition{
+					Type:     Rehearsed,
+					Status:   True,
+					Category: Advisory,
+					Reason:   t,
+					Message:  "The test failover has completed.",
+					Durable:  true,
+				})
+			break
+		}
+	}
 }
 
 // Create a new snapshot.
@@ -0,0 +1,396 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	ocp "github.com/konveyor/forklift-controller/pkg/lib/client/openshift"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations.
+const (
+	// Default storage class.
+	AnnDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+	// Network attachment device (plugin) resource.
+	AnnNetResourceName = "k8s.v1.cni.cncf.io/resourceName"
+)
+
+// Destination validation TTL.
+// The VMs are validated again (with a new destination
+// context) after the TTL so that changes to the destination
+// cluster are reported.
+var DestinationTTL = time.Minute * 10
+
+// Destination validations by plan (singleton).
+var destinationCache = DestinationCache{}
+
+// Validation of the plan VMs with the destination context.
+type DestinationValidation struct {
+	// Plan UID.
+	UID types.UID
+	// Plan generation.
+	Generation int64
+	// Policy version.
+	Version int
+	// Expiration.
+	Expiration time.Time
+	// The destination context was not built.
+	Failed bool
+	// Concerns by VM (reference).
+	Concerns map[string][]model.Concern
+	// VMs (references) not validated.
+	NotValidated map[string]bool
+}
+
+// Cached validation is current.
+func (r *DestinationValidation) Current(plan *api.Plan, version int) bool {
+	return r.UID == plan.UID &&
+		r.Generation == plan.Generation &&
+		r.Version == version &&
+		time.Now().Before(r.Expiration)
+}
+
+// Destination validation cache.
+type DestinationCache struct {
+	mutex   sync.Mutex
+	content map[types.NamespacedName]*DestinationValidation
+}
+
+// Get the validation for the plan.
+func (r *DestinationCache) Get(plan *api.Plan) (validation *DestinationValidation, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	validation, found = r.content[client.ObjectKeyFromObject(plan)]
+	return
+}
+
+// Put the validation for the plan.
+func (r *DestinationCache) Put(plan *api.Plan, validation *DestinationValidation) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.content == nil {
+		r.content = make(map[types.NamespacedName]*DestinationValidation)
+	}
+	r.content[client.ObjectKeyFromObject(plan)] = validation
+}
+
+// Delete the validation for the plan.
+func (r *DestinationCache) Delete(key types.NamespacedName) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.content, key)
+}
+
+// Validate the plan VMs with the destination context.
+// The validation is cached by plan generation and policy
+// version. Returns nil when the policy agent is not enabled
+// or the source provider is not validated by policies.
+func (r *Reconciler) destinationValidation(plan *api.Plan) (validation *DestinationValidation, err error) {
+	if !Settings.PolicyAgent.Enabled() {
+		return
+	}
+	source := plan.Referenced.Provider.Source
+	if source == nil || plan.Referenced.Provider.Destination == nil {
+		return
+	}
+	versionPath, found := policy.VersionPath(source.Type())
+	if !found {
+		return
+	}
+	version, vErr := policy.Agent.Version(versionPath)
+	if vErr != nil {
+		r.Log.Error(vErr, "Policy version not found.")
+		validation = &DestinationValidation{Failed: true}
+		return
+	}
+	if cached, found := destinationCache.Get(plan); found && cached.Current(plan, version) {
+		validation = cached
+		return
+	}
+	validation = &DestinationValidation{
+		UID:          plan.UID,
+		Generation:   plan.Generation,
+		Version:      version,
+		Expiration:   time.Now().Add(DestinationTTL),
+		Concerns:     map[string][]model.Concern{},
+		NotValidated: map[string]bool{},
+	}
+	destination, dErr := r.destination(plan)
+	if dErr != nil {
+		r.Log.Error(dErr, "Destination context not built.")
+		validation.Failed = true
+		return
+	}
+	inventory, err := web.NewClient(source)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range plan.Spec.VMs {
+		ref := plan.Spec.VMs[i].Ref
+		if ref.NotSet() {
+			continue
+		}
+		concerns, pErr := r.planConcerns(plan, inventory, ref, destination)
+		if pErr != nil {
+			r.Log.Error(pErr, "VM not validated with destination context.")
+			validation.NotValidated[ref.String()] = true
+			continue
+		}
+		validation.Concerns[ref.String()] = concerns
+	}
+	destinationCache.Put(plan, validation)
+
+	return
+}
+
+// Build the destination context used to validate the VMs.
+// Describes the capabilities of the destination cluster.
+func (r *Reconciler) destination(plan *api.Plan) (destination *policy.Destination, err error) {
+	provider := plan.Referenced.Provider.Destination
+	var secret *core.Secret
+	if !provider.IsHost() {
+		secret = &core.Secret{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: provider.Spec.Secret.Namespace,
+				Name:      provider.Spec.Secret.Name,
+			},
+			secret)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	dClient, err := ocp.Client(provider, secret)
+	if err != nil {
+		return
+	}
+	destination = &policy.Destination{
+		FeatureGates:   []string{},
+		Nodes:          []policy.Node{},
+		StorageClasses: []policy.StorageClass{},
+		Networks:       []policy.Network{},
+	}
+	err = r.destinationFeatureGates(dClient, destination)
+	if err != nil {
+		return
+	}
+	err = r.destinationNodes(dClient, destination)
+	if err != nil {
+		return
+	}
+	err = r.destinationStorage(dClient, destination)
+	if err != nil {
+		return
+	}
+	err = r.destinationNetworks(dClient, destination)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// KubeVirt feature gates.
+func (r *Reconciler) destinationFeatureGates(dClient client.Client, destination *policy.Destination) (err error) {
+	list := &cnv.KubeVirtList{}
+	err = dClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, kv := range list.Items {
+		config := kv.Spec.Configuration.DeveloperConfiguration
+		if config != nil {
+			destination.FeatureGates = append(
+				destination.FeatureGates,
+				config.FeatureGates...)
+		}
+	}
+
+	return
+}
+
+// Schedulable nodes.
+func (r *Reconciler) destinationNodes(dClient client.Client, destination *policy.Destination) (err error) {
+	list := &core.NodeList{}
+	err = dClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, node := range list.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		destination.Nodes = append(
+			destination.Nodes,
+			policy.Node{
+				Name:   node.Name,
+				CPU:    node.Status.Allocatable.Cpu().Value(),
+				Memory: node.Status.Allocatable.Memory().Value(),
+				Labels: node.Labels,
+			})
+	}
+
+	return
+}
+
+// Storage classes.
+// The volume and access modes are reported by the CDI storage profiles.
+func (r *Reconciler) destinationStorage(dClient client.Client, destination *policy.Destination) (err error) {
+	list := &storage.StorageClassList{}
+	err = dClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	profiles := &cdi.StorageProfileList{}
+	err = dClient.List(context.TODO(), profiles)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	profileMap := map[string]*cdi.StorageProfile{}
+	for i := range profiles.Items {
+		profile := &profiles.Items[i]
+		profileMap[profile.Name] = profile
+	}
+	for _, sc := range list.Items {
+		class := policy.StorageClass{
+			Name:        sc.Name,
+			Provisioner: sc.Provisioner,
+			Default:     sc.Annotations[AnnDefaultStorageClass] == "true",
+			VolumeModes: []string{},
+			AccessModes: []string{},
+		}
+		if profile, found := profileMap[sc.Name]; found {
+			volumeModes := map[string]bool{}
+			accessModes := map[string]bool{}
+			for _, set := range profile.Status.ClaimPropertySets {
+				if set.VolumeMode != nil && !volumeModes[string(*set.VolumeMode)] {
+					volumeModes[string(*set.VolumeMode)] = true
+					class.VolumeModes = append(class.VolumeModes, string(*set.VolumeMode))
+				}
+				for _, mode := range set.AccessModes {
+					if !accessModes[string(mode)] {
+						accessModes[string(mode)] = true
+						class.AccessModes = append(class.AccessModes, string(mode))
+					}
+				}
+			}
+		}
+		destination.StorageClasses = append(destination.StorageClasses, class)
+	}
+
+	return
+}
+
+// Networks (network attachment definitions).
+func (r *Reconciler) destinationNetworks(dClient client.Client, destination *policy.Destination) (err error) {
+	list := &net.NetworkAttachmentDefinitionList{}
+	err = dClient.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, nad := range list.Items {
+		destination.Networks = append(
+			destination.Networks,
+			policy.Network{
+				Namespace:    nad.Namespace,
+				Name:         nad.Name,
+				Type:         cniType(nad.Spec.Config),
+				ResourceName: nad.Annotations[AnnNetResourceName],
+			})
+	}
+
+	return
+}
+
+// Concerns reported for a VM by validating it with the
+// destination context. The inventory client is shared by
+// the VMs of the plan.
+func (r *Reconciler) planConcerns(
+	plan *api.Plan,
+	inventory web.Client,
+	vmRef refapi.Ref,
+	destination *policy.Destination) (concerns []model.Concern, err error) {
+	//
+	provider := plan.Referenced.Provider.Source
+	path, found := policy.ValidationPath(provider.Type())
+	if !found {
+		return
+	}
+	workload, err := inventory.Workload(&vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	input, err := policy.Input(workload, destination)
+	if err != nil {
+		return
+	}
+	concerns, err = policy.Agent.Validate(path, provider.Namespace, input)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+	}
+
+	return
+}
+
+// Merge the concerns.
+// The concerns reported for the plan replace the
+// (inventory) concerns with the same ID.
+func mergeConcerns(inventory, planned []model.Concern) (merged []model.Concern) {
+	setOf := map[string]bool{}
+	for _, concern := range planned {
+		setOf[concern.ID] = true
+		merged = append(merged, concern)
+	}
+	for _, concern := range inventory {
+		if !setOf[concern.ID] {
+			merged = append(merged, concern)
+		}
+	}
+
+	return
+}
+
+// CNI plugin type.
+// The type of the first plugin when the config is a list.
+func cniType(config string) (kind string) {
+	cni := struct {
+		Type    string `json:"type"`
+		Plugins []struct {
+			Type string `json:"type"`
+		} `json:"plugins"`
+	}{}
+	err := json.Unmarshal([]byte(config), &cni)
+	if err != nil {
+		return
+	}
+	kind = cni.Type
+	if kind == "" && len(cni.Plugins) > 0 {
+		kind = cni.Plugins[0].Type
+	}
+
+	return
+}
@@ -0,0 +1,486 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// In-guest hook step annotations.
+const (
+	// PID of the command started by the guest agent.
+	AnnGuestPID = "guestPID"
+	// Command exit code.
+	AnnGuestExitCode = "exitCode"
+	// Command stdout.
+	AnnGuestStdout = "stdout"
+	// Command stderr.
+	AnnGuestStderr = "stderr"
+	// Number of retries.
+	AnnGuestRetries = "retries"
+	// Time (RFC3339) the next retry is started.
+	AnnGuestRetryAt = "retryAt"
+)
+
+// Deadline used when the hook does not specify one.
+const GuestDeadline = 10 * time.Minute
+
+// Retry back-off.
+const (
+	GuestRetryDelay    = 10 * time.Second
+	GuestRetryMaxDelay = 6 * time.Minute
+)
+
+// Output (bytes) recorded on the step.
+const GuestOutputLimit = 4096
+
+// Libvirt URI used by virsh in the virt-launcher (compute) container.
+const LauncherLibvirtURI = "qemu+unix:///session?socket=/var/run/libvirt/virtqemud-sock"
+
+// Guest agent endpoint.
+// Transport for qemu-guest-agent commands.
+type GuestAgentEndpoint interface {
+	// The agent is connected.
+	Connected() (connected bool, err error)
+	// Send a command and return the reply.
+	Command(request []byte) (reply []byte, err error)
+}
+
+// Guest agent exec status.
+type GuestExecStatus struct {
+	// The command has exited.
+	Exited bool `json:"exited"`
+	// Exit code.
+	ExitCode int `json:"exitcode,omitempty"`
+	// Signal that terminated the command.
+	Signal int `json:"signal,omitempty"`
+	// Base64 encoded stdout.
+	OutData string `json:"out-data,omitempty"`
+	// Base64 encoded stderr.
+	ErrData string `json:"err-data,omitempty"`
+}
+
+// Decoded stdout.
+func (r *GuestExecStatus) Stdout() string {
+	b, _ := base64.StdEncoding.DecodeString(r.OutData)
+	return string(b)
+}
+
+// Decoded stderr.
+func (r *GuestExecStatus) Stderr() string {
+	b, _ := base64.StdEncoding.DecodeString(r.ErrData)
+	return string(b)
+}
+
+// Guest agent.
+// Executes commands in the guest using the qemu-guest-agent
+// `guest-exec` and `guest-exec-status` commands.
+type GuestAgent struct {
+	Endpoint GuestAgentEndpoint
+}
+
+// Start a command in the guest.
+// The input is written to the command stdin.
+func (r *GuestAgent) Exec(command []string, input string) (pid int, err error) {
+	arguments := map[string]interface{}{
+		"path":           command[0],
+		"arg":            command[1:],
+		"capture-output": true,
+	}
+	if input != "" {
+		arguments["input-data"] = base64.StdEncoding.EncodeToString([]byte(input))
+	}
+	reply := struct {
+		PID int `json:"pid"`
+	}{}
+	err = r.send("guest-exec", arguments, &reply)
+	if err != nil {
+		return
+	}
+	pid = reply.PID
+	return
+}
+
+// Get the status of a command started by Exec.
+func (r *GuestAgent) ExecStatus(pid int) (status *GuestExecStatus, err error) {
+	status = &GuestExecStatus{}
+	err = r.send("guest-exec-status", map[string]interface{}{"pid": pid}, status)
+	return
+}
+
+// Get the guest hostname.
+func (r *GuestAgent) HostName() (name string, err error) {
+	reply := struct {
+		HostName string `json:"host-name"`
+	}{}
+	err = r.send("guest-get-host-name", map[string]interface{}{}, &reply)
+	if err != nil {
+		return
+	}
+	name = reply.HostName
+	return
+}
+
+// Send a command.
+func (r *GuestAgent) send(command string, arguments interface{}, reply interface{}) (err error) {
+	request, err := json.Marshal(
+		map[string]interface{}{
+			"execute":   command,
+			"arguments": arguments,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	b, err := r.Endpoint.Command(request)
+	if err != nil {
+		return
+	}
+	envelope := struct {
+		Return json.RawMessage `json:"return"`
+		Error  *struct {
+			Class string `json:"class"`
+			Desc  string `json:"desc"`
+		} `json:"error"`
+	}{}
+	err = json.Unmarshal(b, &envelope)
+	if err != nil {
+		err = liberr.Wrap(err, "reply", string(b))
+		return
+	}
+	if envelope.Error != nil {
+		err = liberr.New(
+			"Guest agent command failed.",
+			"command",
+			command,
+			"class",
+			envelope.Error.Class,
+			"error",
+			envelope.Error.Desc)
+		return
+	}
+	err = json.Unmarshal(envelope.Return, reply)
+	if err != nil {
+		err = liberr.Wrap(err, "reply", string(b))
+	}
+	return
+}
+
+// Launcher clientsets (cache).
+// Keyed by destination REST configuration.
+var launcherClientsets struct {
+	cache map[string]kubernetes.Interface
+	mutex sync.Mutex
+}
+
+// Guest agent endpoint provided by the virt-launcher pod.
+// Commands are sent using `virsh qemu-agent-command` executed
+// in the compute container.
+type LauncherEndpoint struct {
+	// Destination client.
+	Client client.Client
+	// Destination REST configuration.
+	RestCfg *rest.Config
+	// VMI namespace.
+	Namespace string
+	// VMI name.
+	Name string
+}
+
+// Build the endpoint for the migrated VM.
+func NewLauncherEndpoint(ctx *plancontext.Context, vm *planapi.VMStatus) *LauncherEndpoint {
+	return &LauncherEndpoint{
+		Client:    ctx.Destination.Client,
+		RestCfg:   ctx.Destination.RestCfg,
+		Namespace: ctx.Plan.Spec.TargetNamespace,
+		Name:      vm.Name,
+	}
+}
+
+// The agent is connected.
+func (r *LauncherEndpoint) Connected() (connected bool, err error) {
+	vmi := &cnv.VirtualMachineInstance{}
+	err = r.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Namespace,
+			Name:      r.Name,
+		},
+		vmi)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, cnd := range vmi.Status.Conditions {
+		if cnd.Type == cnv.VirtualMachineInstanceAgentConnected {
+			connected = cnd.Status == core.ConditionTrue
+			break
+		}
+	}
+	return
+}
+
+// Send a command and return the reply.
+func (r *LauncherEndpoint) Command(request []byte) (reply []byte, err error) {
+	pod, err := r.pod()
+	if err != nil {
+		return
+	}
+	clientset, err := r.clientset()
+	if err != nil {
+		return
+	}
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(
+			&core.PodExecOptions{
+				Container: "compute",
+				Command: []string{
+					"virsh",
+					"--connect",
+					LauncherLibvirtURI,
+					"qemu-agent-command",
+					r.Namespace + "_" + r.Name,
+					string(request),
+				},
+				Stdout: true,
+				Stderr: true,
+			},
+			scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(r.RestCfg, "POST", req.URL())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Minute)
+	defer cancel()
+	err = executor.StreamWithContext(
+		ctx,
+		remotecommand.StreamOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		})
+	if err != nil {
+		err = liberr.Wrap(err, "stderr", stderr.String())
+		return
+	}
+	reply = stdout.Bytes()
+	return
+}
+
+// Clientset for the destination.
+// Built once for each REST configuration.
+func (r *LauncherEndpoint) clientset() (clientset kubernetes.Interface, err error) {
+	digest := sha256.New()
+	digest.Write([]byte(r.RestCfg.Host))
+	digest.Write([]byte(r.RestCfg.BearerToken))
+	digest.Write(r.RestCfg.TLSClientConfig.CAData)
+	key := hex.EncodeToString(digest.Sum(nil))
+	launcherClientsets.mutex.Lock()
+	defer launcherClientsets.mutex.Unlock()
+	if launcherClientsets.cache == nil {
+		launcherClientsets.cache = make(map[string]kubernetes.Interface)
+	}
+	clientset, found := launcherClientsets.cache[key]
+	if found {
+		return
+	}
+	built, err := kubernetes.NewForConfig(r.RestCfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	launcherClientsets.cache[key] = built
+	clientset = built
+	return
+}
+
+// Find the (running) virt-launcher pod.
+func (r *LauncherEndpoint) pod() (pod *core.Pod, err error) {
+	list := &core.PodList{}
+	err = r.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(
+				map[string]string{
+					"kubevirt.io":         "virt-launcher",
+					"vm.kubevirt.io/name": r.Name,
+				}),
+			Namespace: r.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		if list.Items[i].Status.Phase == core.PodRunning {
+			pod = &list.Items[i]
+			return
+		}
+	}
+	err = liberr.New(
+		"virt-launcher pod not found.",
+		"vmi",
+		r.Namespace+"/"+r.Name)
+	return
+}
+
+// Run the in-guest hook.
+// Waits for the guest agent to be connected, starts the script
+// and polls for the exit status. The exit code and output are
+// recorded as step annotations. Failed runs are retried
+// according to the hook retry policy.
+func (r *HookRunner) runGuest(step *planapi.Step) (err error) {
+	step.MarkStarted()
+	if r.deadlineExceeded(step) {
+		step.AddError("Deadline exceeded.")
+		step.MarkCompleted()
+		return
+	}
+	agent := GuestAgent{Endpoint: r.endpoint}
+	if agent.Endpoint == nil {
+		agent.Endpoint = NewLauncherEndpoint(r.Context, r.vm)
+	}
+	if step.Annotations == nil {
+		step.Annotations = make(map[string]string)
+	}
+	pidAnn, started := step.Annotations[AnnGuestPID]
+	if !started {
+		if r.retryPending(step) {
+			return
+		}
+		connected, cErr := agent.Endpoint.Connected()
+		if cErr != nil || !connected {
+			err = cErr
+			return
+		}
+		guest := r.hook.Spec.Guest
+		pid, xErr := agent.Exec(guest.Command(), guest.Script)
+		if xErr != nil {
+			r.guestFailed(step, xErr.Error())
+			return
+		}
+		step.Annotations[AnnGuestPID] = strconv.Itoa(pid)
+		return
+	}
+	pid, err := strconv.Atoi(pidAnn)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	status, err := agent.ExecStatus(pid)
+	if err != nil {
+		return
+	}
+	if !status.Exited {
+		return
+	}
+	stdout := status.Stdout()
+	step.Annotations[AnnGuestExitCode] = strconv.Itoa(status.ExitCode)
+	step.Annotations[AnnGuestStdout] = truncate(stdout, GuestOutputLimit)
+	step.Annotations[AnnGuestStderr] = truncate(status.Stderr(), GuestOutputLimit)
+	switch {
+	case status.Signal != 0:
+		r.guestFailed(step, fmt.Sprintf("Guest command terminated by signal: %d.", status.Signal))
+	case status.ExitCode != 0:
+		r.guestFailed(step, fmt.Sprintf("Guest command failed with exit code: %d.", status.ExitCode))
+	default:
+		r.mergeOutput(stdout)
+		step.Progress.Completed = 1
+		step.MarkCompleted()
+	}
+	return
+}
+
+// The guest command failed.
+// The command is scheduled to be retried with an exponential
+// back-off until the retry limit is reached.
+func (r *HookRunner) guestFailed(step *planapi.Step, reason string) {
+	retries, _ := strconv.Atoi(step.Annotations[AnnGuestRetries])
+	if retries >= r.retryLimit() {
+		step.AddError(reason)
+		step.MarkCompleted()
+		return
+	}
+	delay := GuestRetryDelay << retries
+	if delay > GuestRetryMaxDelay || delay <= 0 {
+		delay = GuestRetryMaxDelay
+	}
+	r.Log.Info(
+		"Guest command failed, retrying.",
+		"reason",
+		reason,
+		"retry",
+		retries+1,
+		"delay",
+		delay.String())
+	delete(step.Annotations, AnnGuestPID)
+	step.Annotations[AnnGuestRetries] = strconv.Itoa(retries + 1)
+	step.Annotations[AnnGuestRetryAt] = time.Now().Add(delay).Format(time.RFC3339)
+}
+
+// A scheduled retry is pending.
+func (r *HookRunner) retryPending(step *planapi.Step) bool {
+	retryAt, found := step.Annotations[AnnGuestRetryAt]
+	if !found {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, retryAt)
+	if err != nil {
+		return false
+	}
+	return time.Now().Before(t)
+}
+
+// The hook deadline has been exceeded.
+// In-guest hooks without a deadline use the default
+// so a guest agent that never connects cannot block
+// the migration.
+func (r *HookRunner) deadlineExceeded(step *planapi.Step) bool {
+	if step.Started == nil {
+		return false
+	}
+	deadline := GuestDeadline
+	if r.hook.Spec.Deadline > 0 {
+		deadline = time.Duration(r.hook.Spec.Deadline) * time.Second
+	}
+	return time.Since(step.Started.Time) > deadline
+}
+
+// Truncate to the last n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
@@ -3,8 +3,11 @@ package plan
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
@@ -16,8 +19,10 @@ import (
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -27,15 +32,37 @@ const (
 	kStep = "step"
 )
 
+// Hook (pod) log lines searched for output.
+const HookLogTail = 1000
+
+// ID used to label plan-level hook resources.
+const PlanHookID = "plan"
+
+// Host clientset (singleton).
+// The hook jobs run on the host cluster. Used to
+// fetch the hook (pod) logs.
+var hostClientset struct {
+	kubernetes.Interface
+	mutex sync.Mutex
+}
+
 // Hook runner.
 type HookRunner struct {
 	*plancontext.Context
 	// VM.
+	// Not set for plan-level hooks.
 	vm *planapi.VMStatus
+	// Step.
+	step *planapi.Step
 	// Hook.
 	hookRef *planapi.HookRef
 	// Hook.
 	hook *api.Hook
+	// Guest agent endpoint (in-guest hooks).
+	endpoint GuestAgentEndpoint
+	// Clientset used to fetch the hook logs.
+	// The host clientset when not set.
+	clientset kubernetes.Interface
 }
 
 // Run.
@@ -46,6 +73,7 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		err = liberr.New("Step not found.")
 		return
 	}
+	r.step = step
 	if ref, found := vm.FindHook(vm.Phase); found {
 		if r.hook, found = r.FindHook(ref.Hook); !found {
 			step.Error = &planapi.Error{
@@ -58,6 +86,33 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		step.MarkedCompleted()
 		return
 	}
+	if r.hook.Spec.Guest != nil {
+		err = r.runGuest(step)
+		return
+	}
+	err = r.runJob()
+
+	return
+}
+
+// Run a plan-level hook.
+func (r *HookRunner) RunPlan(step *planapi.Step, ref *planapi.HookRef) (err error) {
+	r.step = step
+	r.hookRef = ref
+	hook, found := r.FindHook(ref.Hook)
+	if !found {
+		step.AddError("Hook not found.")
+		step.MarkCompleted()
+		return
+	}
+	r.hook = hook
+	err = r.runJob()
+	return
+}
+
+// Run the hook job and reflect the job status on the step.
+func (r *HookRunner) runJob() (err error) {
+	step := r.step
 	job, err := r.ensureJob()
 	if err != nil {
 		return
@@ -75,10 +130,14 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 	if conditions.HasCondition("Failed") {
 		step.AddError(conditions.FindCondition("Failed").Message)
 		step.MarkCompleted()
-	} else if int(job.Status.Failed) > Settings.Migration.HookRetry {
+	} else if int(job.Status.Failed) > r.retryLimit() {
 		step.AddError("Retry limit exceeded.")
 		step.MarkCompleted()
 	} else if job.Status.Succeeded > 0 {
+		err = r.collectOutput(job)
+		if err != nil {
+			return
+		}
 		step.Progress.Completed = 1
 		step.MarkCompleted()
 	}
@@ -86,6 +145,142 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 	return
 }
 
+// Number of retries.
+// The hook retry policy when specified.
+func (r *HookRunner) retryLimit() int {
+	if r.hook.Spec.Retry != nil {
+		return int(r.hook.Spec.Retry.Limit)
+	}
+	return Settings.Migration.HookRetry
+}
+
+// Collect the output reported by the hook (pod) and
+// merge it into the VM hook outputs.
+// Logs that cannot be fetched are reported as a
+// warning and the output is not collected.
+func (r *HookRunner) collectOutput(job *batch.Job) (err error) {
+	podList := core.PodList{}
+	err = r.Client.List(
+		context.TODO(),
+		&podList,
+		&client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(
+				map[string]string{
+					"job-name": job.Name,
+				}),
+			Namespace: job.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	var pod *core.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Status.Phase == core.PodSucceeded {
+			pod = &podList.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return
+	}
+	var b []byte
+	clientset, lErr := r.logClientset()
+	if lErr == nil {
+		tail := int64(HookLogTail)
+		b, lErr = clientset.CoreV1().Pods(pod.Namespace).GetLogs(
+			pod.Name,
+			&core.PodLogOptions{
+				Container: "hook",
+				TailLines: &tail,
+			}).Do(context.TODO()).Raw()
+	}
+	if lErr != nil {
+		r.Log.Info(
+			"Hook output not collected.",
+			"hook",
+			path.Join(
+				r.hook.Namespace,
+				r.hook.Name),
+			"error",
+			lErr.Error())
+		return
+	}
+	r.mergeOutput(string(b))
+
+	return
+}
+
+// Clientset used to fetch the hook logs.
+// The host clientset is built once and shared.
+func (r *HookRunner) logClientset() (clientset kubernetes.Interface, err error) {
+	if r.clientset != nil {
+		clientset = r.clientset
+		return
+	}
+	hostClientset.mutex.Lock()
+	defer hostClientset.mutex.Unlock()
+	if hostClientset.Interface == nil {
+		cfg, cErr := config.GetConfig()
+		if cErr != nil {
+			err = liberr.Wrap(cErr)
+			return
+		}
+		built, bErr := kubernetes.NewForConfig(cfg)
+		if bErr != nil {
+			err = liberr.Wrap(bErr)
+			return
+		}
+		hostClientset.Interface = built
+	}
+	clientset = hostClientset.Interface
+	return
+}
+
+// Merge the output reported in the log into the VM hook outputs.
+// Output that is not valid is logged and ignored.
+func (r *HookRunner) mergeOutput(log string) {
+	output, err := hookOutput(log)
+	if err != nil {
+		r.Log.Info(
+			"Hook output not valid.",
+			"hook",
+			path.Join(
+				r.hook.Namespace,
+				r.hook.Name),
+			"error",
+			err.Error())
+		return
+	}
+	if len(output) == 0 {
+		return
+	}
+	outputs := r.hookOutputs()
+	if *outputs == nil {
+		*outputs = make(map[string]string)
+	}
+	for k, v := range output {
+		(*outputs)[k] = v
+	}
+}
+
+// Outputs reported by hooks.
+// The VM outputs or the plan outputs for plan-level hooks.
+func (r *HookRunner) hookOutputs() *map[string]string {
+	if r.vm != nil {
+		return &r.vm.HookOutputs
+	}
+	return &r.Plan.Status.Migration.HookOutputs
+}
+
+// ID used to name and label created resources.
+func (r *HookRunner) id() string {
+	if r.vm != nil {
+		return r.vm.ID
+	}
+	return PlanHookID
+}
+
 // Ensure the job.
 func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 	mp, err := r.ensureConfigMap()
@@ -147,6 +342,9 @@ func (r *HookRunner) ensureJob() (job *batch.Job, err error) {
 func (r *HookRunner) job(mp *core.ConfigMap) (job *batch.Job, err error) {
 	template := r.template(mp)
 	backOff := int32(1)
+	if r.hook.Spec.Retry != nil {
+		backOff = r.hook.Spec.Retry.Limit
+	}
 	job = &batch.Job{
 		Spec: batch.JobSpec{
 			Template:     *template,
@@ -157,8 +355,8 @@ func (r *HookRunner) job(mp *core.ConfigMap) (job *batch.Job, err error) {
 			GenerateName: strings.ToLower(
 				strings.Join([]string{
 					r.Plan.Name,
-					r.vm.ID,
-					r.vm.Phase},
+					r.id(),
+					r.step.Name},
 					"-") + "-"),
 			Labels: r.labels(),
 		},
@@ -211,8 +409,10 @@ func (r *HookRunner) template(mp *core.ConfigMap) (template *core.PodTemplateSpe
 	if len(sa) > 0 {
 		template.Spec.ServiceAccountName = sa
 	}
-	if len(r.hook.Spec.Playbook) > 0 {
-		container := &template.Spec.Containers[0]
+	container := &template.Spec.Containers[0]
+	if len(r.hook.Spec.Command) > 0 {
+		container.Command = r.hook.Spec.Command
+	} else if len(r.hook.Spec.Playbook) > 0 {
 		container.Command = []string{
 			"/bin/entrypoint",
 			"ansible-runner",
@@ -222,6 +422,30 @@ func (r *HookRunner) template(mp *core.ConfigMap) (template *core.PodTemplateSpe
 			"/tmp/hook/playbook.yml",
 		}
 	}
+	container.Args = r.hook.Spec.Args
+	container.Env = r.hook.Spec.Env
+	container.Resources = r.hook.Spec.Resources
+	for i, secret := range r.hook.Spec.Secrets {
+		// Secret names may not be valid (volume) labels.
+		name := "secret-" + strconv.Itoa(i)
+		template.Spec.Volumes = append(
+			template.Spec.Volumes,
+			core.Volume{
+				Name: name,
+				VolumeSource: core.VolumeSource{
+					Secret: &core.SecretVolumeSource{
+						SecretName: secret.Name,
+					},
+				},
+			})
+		container.VolumeMounts = append(
+			container.VolumeMounts,
+			core.VolumeMount{
+				Name:      name,
+				MountPath: secret.Path(),
+				ReadOnly:  true,
+			})
+	}
 
 	return
 }
@@ -283,6 +507,10 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 	if err != nil {
 		return
 	}
+	outputs, err := r.outputs()
+	if err != nil {
+		return
+	}
 	mp = &core.ConfigMap{
 		ObjectMeta: meta.ObjectMeta{
 			Labels:    r.labels(),
@@ -290,14 +518,15 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 			GenerateName: strings.ToLower(
 				strings.Join([]string{
 					r.Plan.Name,
-					r.vm.ID,
-					r.vm.Phase},
+					r.id(),
+					r.step.Name},
 					"-")) + "-",
 		},
 		Data: map[string]string{
 			"workload.yml": workload,
 			"playbook.yml": playbook,
 			"plan.yml":     plan,
+			"outputs.yml":  outputs,
 		},
 	}
 
@@ -305,7 +534,11 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 }
 
 // Workload
+// Empty for plan-level hooks.
 func (r *HookRunner) workload() (workload string, err error) {
+	if r.vm == nil {
+		return
+	}
 	inventory := r.Source.Inventory
 	object, err := inventory.Workload(&r.vm.Ref)
 	if err != nil {
@@ -346,12 +579,62 @@ func (r *HookRunner) plan() (plan string, err error) {
 	return
 }
 
+// Outputs reported by the hooks run by earlier steps (yaml).
+func (r *HookRunner) outputs() (outputs string, err error) {
+	b, err := yaml.Marshal(*r.hookOutputs())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	outputs = string(b)
+	return
+}
+
+// Parse the output reported by a hook.
+// Lines (stdout) with the output prefix contain a JSON object.
+// Values that are not strings are stored as JSON.
+func hookOutput(log string) (output map[string]string, err error) {
+	output = make(map[string]string)
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, api.HookOutputPrefix) {
+			continue
+		}
+		object := map[string]interface{}{}
+		err = json.Unmarshal(
+			[]byte(strings.TrimPrefix(line, api.HookOutputPrefix)),
+			&object)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for k, v := range object {
+			if s, cast := v.(string); cast {
+				output[k] = s
+				continue
+			}
+			b, jErr := json.Marshal(v)
+			if jErr != nil {
+				err = liberr.Wrap(jErr)
+				return
+			}
+			output[k] = string(b)
+		}
+	}
+
+	return
+}
+
 // Labels for created resources.
-func (r *HookRunner) labels() map[string]string {
-	return map[string]string{
+func (r *HookRunner) labels() (labels map[string]string) {
+	labels = map[string]string{
 		kPlan:      string(r.Plan.UID),
 		kMigration: string(r.Migration.UID),
-		kVM:        r.vm.ID,
-		kStep:      r.vm.Phase,
+		kVM:        r.id(),
+		kStep:      r.step.Name,
+	}
+	if r.Plan.Rehearsing() {
+		labels[kRehearsal] = "true"
 	}
+	return
 }
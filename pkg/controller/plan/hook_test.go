@@ -0,0 +1,468 @@
+package plan
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Hook runner", func() {
+	mp := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Name: "hook-map"},
+	}
+
+	ginkgo.It("should run the playbook", func() {
+		runner := &HookRunner{
+			hook: &api.Hook{
+				Spec: api.HookSpec{
+					Image:    "quay.io/konveyor/hook-runner",
+					Playbook: "LS0tCg==",
+				},
+			},
+		}
+		template := runner.template(mp)
+		container := template.Spec.Containers[0]
+		gomega.Expect(container.Command).To(gomega.ContainElement("ansible-runner"))
+		gomega.Expect(template.Spec.Volumes).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should run the command with env, secrets and resources", func() {
+		runner := &HookRunner{
+			hook: &api.Hook{
+				Spec: api.HookSpec{
+					Image:   "quay.io/example/tools",
+					Command: []string{"/bin/sh", "-c"},
+					Args:    []string{"ssh -i /tmp/secret/ssh/key guest true"},
+					Env: []core.EnvVar{
+						{Name: "TOKEN", Value: "x"},
+					},
+					Secrets: []api.HookSecret{
+						{Name: "ssh"},
+						{Name: "api", MountPath: "/etc/api"},
+					},
+					Resources: core.ResourceRequirements{
+						Limits: core.ResourceList{
+							core.ResourceCPU: resource.MustParse("500m"),
+						},
+					},
+				},
+			},
+		}
+		template := runner.template(mp)
+		container := template.Spec.Containers[0]
+		gomega.Expect(container.Command).To(gomega.Equal([]string{"/bin/sh", "-c"}))
+		gomega.Expect(container.Args).To(gomega.HaveLen(1))
+		gomega.Expect(container.Env).To(gomega.HaveLen(1))
+		gomega.Expect(container.Resources.Limits.Cpu().String()).To(gomega.Equal("500m"))
+		gomega.Expect(template.Spec.Volumes).To(gomega.HaveLen(3))
+		paths := []string{}
+		for _, mount := range container.VolumeMounts {
+			paths = append(paths, mount.MountPath)
+		}
+		gomega.Expect(paths).To(gomega.ConsistOf("/tmp/hook", "/tmp/secret/ssh", "/etc/api"))
+	})
+
+	ginkgo.It("should name the secret volumes as labels", func() {
+		long := "forklift.example.com-" + strings.Repeat("x", 60)
+		runner := &HookRunner{
+			hook: &api.Hook{
+				Spec: api.HookSpec{
+					Image: "quay.io/example/tools",
+					Secrets: []api.HookSecret{
+						{Name: "ssh.example.com"},
+						{Name: long},
+					},
+				},
+			},
+		}
+		template := runner.template(mp)
+		for _, volume := range template.Spec.Volumes {
+			gomega.Expect(validation.IsDNS1123Label(volume.Name)).To(gomega.BeEmpty())
+		}
+		container := template.Spec.Containers[0]
+		mounts := map[string]string{}
+		for _, mount := range container.VolumeMounts {
+			mounts[mount.MountPath] = mount.Name
+		}
+		secrets := map[string]string{}
+		for _, volume := range template.Spec.Volumes {
+			if volume.Secret != nil {
+				secrets[volume.Name] = volume.Secret.SecretName
+			}
+		}
+		gomega.Expect(secrets).To(gomega.HaveLen(2))
+		gomega.Expect(secrets[mounts["/tmp/secret/ssh.example.com"]]).To(gomega.Equal("ssh.example.com"))
+		gomega.Expect(secrets[mounts["/tmp/secret/"+long]]).To(gomega.Equal(long))
+	})
+
+	ginkgo.It("should parse the hook output", func() {
+		log := "starting\n" +
+			"forklift-output: {\"ip\": \"10.0.0.1\"}\n" +
+			"noise forklift-output: {\"ignored\": true}\n" +
+			"  forklift-output: {\"ips\": [\"10.0.0.1\", \"10.0.0.2\"], \"ready\": true}\n"
+		output, err := hookOutput(log)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(output).To(gomega.Equal(map[string]string{
+			"ip":    "10.0.0.1",
+			"ips":   "[\"10.0.0.1\",\"10.0.0.2\"]",
+			"ready": "true",
+		}))
+		_, err = hookOutput("forklift-output: {not json}")
+		gomega.Expect(err).To(gomega.HaveOccurred())
+	})
+})
+
+var _ = ginkgo.Describe("Hook steps", func() {
+	hook := func(step, onFailure string) planapi.HookRef {
+		return planapi.HookRef{
+			Step:      step,
+			Hook:      core.ObjectReference{Namespace: "test", Name: step},
+			OnFailure: onFailure,
+		}
+	}
+	context := func() *plancontext.Context {
+		plan := &api.Plan{}
+		plan.Referenced.Provider.Source = createProvider("source", "test", "https://source", api.VSphere, &core.ObjectReference{})
+		plan.Referenced.Provider.Destination = createProvider("destination", "test", "", api.OpenShift, &core.ObjectReference{})
+		return &plancontext.Context{
+			Plan: plan,
+			Log:  logging.WithName("test"),
+		}
+	}
+	migration := func() *Migration {
+		return &Migration{Context: context()}
+	}
+
+	ginkgo.It("should allow steps for configured hooks", func() {
+		vm := &planapi.VM{
+			Hooks: []planapi.HookRef{
+				hook(PostPowerOffHook, ""),
+				hook(PostConversionHook, ""),
+			},
+		}
+		predicate := &Predicate{vm: vm, context: context()}
+		for flag, allowed := range map[libitr.Flag]bool{
+			HasPostPowerOffHook:   true,
+			HasPostConversionHook: true,
+			HasPreCutoverHook:     false,
+			HasFailureHook:        false,
+		} {
+			evaluated, err := predicate.Evaluate(flag)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(evaluated).To(gomega.Equal(allowed))
+		}
+	})
+
+	ginkgo.It("should continue with a warning when the hook allows it", func() {
+		vm := &planapi.VMStatus{
+			VM: planapi.VM{
+				Hooks: []planapi.HookRef{hook(PostPowerOffHook, planapi.HookContinue)},
+			},
+		}
+		step := &planapi.Step{Task: planapi.Task{Name: PostPowerOffHook}}
+		step.AddError("job failed.")
+		migration().hookFailed(vm, step)
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(vm.HasCondition(HookFailed)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should fail the VM by default", func() {
+		vm := &planapi.VMStatus{
+			VM: planapi.VM{
+				Hooks: []planapi.HookRef{hook(PostConversionHook, "")},
+			},
+		}
+		step := &planapi.Step{Task: planapi.Task{Name: PostConversionHook}}
+		step.AddError("job failed.")
+		migration().hookFailed(vm, step)
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+		gomega.Expect(vm.HasCondition(HookFailed)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should run the failure hook once", func() {
+		vm := &planapi.VMStatus{
+			VM: planapi.VM{
+				Hooks: []planapi.HookRef{hook(FailureHook, "")},
+			},
+		}
+		m := migration()
+		gomega.Expect(m.failureHook(vm)).To(gomega.BeTrue())
+		gomega.Expect(vm.Phase).To(gomega.Equal(FailureHook))
+		step, found := vm.FindStep(FailureHook)
+		gomega.Expect(found).To(gomega.BeTrue())
+		step.MarkCompleted()
+		gomega.Expect(m.failureHook(vm)).To(gomega.BeFalse())
+		gomega.Expect(vm.Pipeline).To(gomega.HaveLen(1))
+	})
+})
+
+// Fake guest agent endpoint.
+type fakeGuestAgent struct {
+	connected bool
+	exited    bool
+	exitCode  int
+	stdout    string
+	input     string
+}
+
+func (r *fakeGuestAgent) Connected() (bool, error) {
+	return r.connected, nil
+}
+
+func (r *fakeGuestAgent) Command(request []byte) (reply []byte, err error) {
+	command := struct {
+		Execute   string                 `json:"execute"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}{}
+	err = json.Unmarshal(request, &command)
+	if err != nil {
+		return
+	}
+	switch command.Execute {
+	case "guest-exec":
+		b, _ := base64.StdEncoding.DecodeString(command.Arguments["input-data"].(string))
+		r.input = string(b)
+		reply = []byte(`{"return":{"pid":42}}`)
+	case "guest-get-host-name":
+		reply = []byte(`{"return":{"host-name":"vm1"}}`)
+	case "guest-exec-status":
+		reply, err = json.Marshal(
+			map[string]interface{}{
+				"return": GuestExecStatus{
+					Exited:   r.exited,
+					ExitCode: r.exitCode,
+					OutData:  base64.StdEncoding.EncodeToString([]byte(r.stdout)),
+				},
+			})
+	default:
+		reply = []byte(`{"error":{"class":"CommandNotFound","desc":"not supported"}}`)
+	}
+	return
+}
+
+var _ = ginkgo.Describe("In-guest hook", func() {
+	runner := func(agent *fakeGuestAgent) (*HookRunner, *planapi.VMStatus) {
+		hook := &api.Hook{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "guest"},
+			Spec: api.HookSpec{
+				Guest: &api.GuestHook{Script: "hostnamectl set-hostname vm1"},
+			},
+		}
+		vm := &planapi.VMStatus{
+			Phase: PostHook,
+			VM: planapi.VM{
+				Hooks: []planapi.HookRef{
+					{
+						Step: PostHook,
+						Hook: core.ObjectReference{Namespace: "test", Name: "guest"},
+					},
+				},
+			},
+			Pipeline: []*planapi.Step{
+				{Task: planapi.Task{Name: PostHook}},
+			},
+		}
+		return &HookRunner{
+			Context: &plancontext.Context{
+				Hooks: []*api.Hook{hook},
+				Log:   logging.WithName("test"),
+			},
+			endpoint: agent,
+		}, vm
+	}
+
+	ginkgo.It("should wait for the agent and run the script", func() {
+		agent := &fakeGuestAgent{}
+		r, vm := runner(agent)
+		step := vm.Pipeline[0]
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.Annotations).ToNot(gomega.HaveKey(AnnGuestPID))
+		agent.connected = true
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.Annotations[AnnGuestPID]).To(gomega.Equal("42"))
+		gomega.Expect(agent.input).To(gomega.Equal("hostnamectl set-hostname vm1"))
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		agent.exited = true
+		agent.stdout = "done\nforklift-output: {\"hostname\": \"vm1\"}\n"
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(step.Annotations[AnnGuestExitCode]).To(gomega.Equal("0"))
+		gomega.Expect(vm.HookOutputs).To(gomega.HaveKeyWithValue("hostname", "vm1"))
+	})
+
+	ginkgo.It("should fail the step on a non-zero exit code", func() {
+		agent := &fakeGuestAgent{connected: true, exited: true, exitCode: 3}
+		r, vm := runner(agent)
+		r.Hooks[0].Spec.Retry = &api.HookRetry{Limit: 0}
+		step := vm.Pipeline[0]
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+		gomega.Expect(step.Annotations[AnnGuestExitCode]).To(gomega.Equal("3"))
+	})
+
+	ginkgo.It("should retry a failed script", func() {
+		agent := &fakeGuestAgent{connected: true, exited: true, exitCode: 3}
+		r, vm := runner(agent)
+		r.Hooks[0].Spec.Retry = &api.HookRetry{Limit: 1}
+		step := vm.Pipeline[0]
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		gomega.Expect(step.Annotations).ToNot(gomega.HaveKey(AnnGuestPID))
+		gomega.Expect(step.Annotations[AnnGuestRetries]).To(gomega.Equal("1"))
+		// Back-off pending.
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.Annotations).ToNot(gomega.HaveKey(AnnGuestPID))
+		step.Annotations[AnnGuestRetryAt] = time.Now().Format(time.RFC3339)
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.Annotations[AnnGuestPID]).To(gomega.Equal("42"))
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+	})
+
+	ginkgo.It("should apply the default deadline", func() {
+		agent := &fakeGuestAgent{}
+		r, vm := runner(agent)
+		step := vm.Pipeline[0]
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		step.Started.Time = time.Now().Add(-GuestDeadline - time.Second)
+		gomega.Expect(r.Run(vm)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+	})
+})
+
+var _ = ginkgo.Describe("Plan-level hooks", func() {
+	hookRef := func(step, onFailure string) planapi.HookRef {
+		return planapi.HookRef{
+			Step:      step,
+			Hook:      core.ObjectReference{Namespace: "test", Name: "wave"},
+			OnFailure: onFailure,
+		}
+	}
+	migration := func(refs ...planapi.HookRef) *Migration {
+		plan := &api.Plan{}
+		plan.Spec.Hooks = refs
+		plan.Status.Migration.History = []planapi.Snapshot{{}}
+		m := &Migration{
+			Context: &plancontext.Context{
+				Plan: plan,
+				Log:  logging.WithName("test"),
+			},
+		}
+		plan.Status.Migration.Hooks = m.planHooks()
+		return m
+	}
+
+	ginkgo.It("should build steps for configured hooks", func() {
+		m := migration(hookRef(PostHook, ""))
+		gomega.Expect(m.Plan.Status.Migration.Hooks).To(gomega.HaveLen(1))
+		_, found := m.Plan.Status.Migration.FindHook(PostHook)
+		gomega.Expect(found).To(gomega.BeTrue())
+		running, failed, err := m.runPlanHook(PreHook)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(running).To(gomega.BeFalse())
+		gomega.Expect(failed).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should fail the plan when the hook fails", func() {
+		m := migration(hookRef(PreHook, ""))
+		m.Plan.Status.Migration.VMs = []*planapi.VMStatus{{}}
+		running, failed, err := m.runPlanHook(PreHook)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(running).To(gomega.BeFalse())
+		gomega.Expect(failed).To(gomega.BeTrue())
+		snapshot := m.Plan.Status.Migration.ActiveSnapshot()
+		gomega.Expect(snapshot.FindCondition(PlanPreHook).Reason).To(gomega.Equal(Failed))
+		m.failVMs("The plan pre-migration hook failed.")
+		vm := m.Plan.Status.Migration.VMs[0]
+		gomega.Expect(vm.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(vm.HasCondition(Failed)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should continue when the hook allows it", func() {
+		m := migration(hookRef(PostHook, planapi.HookContinue))
+		_, failed, err := m.runPlanHook(PostHook)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(failed).To(gomega.BeFalse())
+		snapshot := m.Plan.Status.Migration.ActiveSnapshot()
+		gomega.Expect(snapshot.FindCondition(PlanPostHook).Category).To(gomega.Equal(Warn))
+	})
+})
+
+var _ = ginkgo.Describe("Hook output", func() {
+	job := &batch.Job{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "hook-job"},
+	}
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "hook-pod",
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: core.PodStatus{Phase: core.PodSucceeded},
+	}
+	runner := func(status int, log string) (r *HookRunner, vm *planapi.VMStatus) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(log))
+		}))
+		ginkgo.DeferCleanup(server.Close)
+		clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		scheme := runtime.NewScheme()
+		_ = core.AddToScheme(scheme)
+		vm = &planapi.VMStatus{}
+		r = &HookRunner{
+			Context: &plancontext.Context{
+				Client: fakeClient.NewClientBuilder().
+					WithScheme(scheme).
+					WithRuntimeObjects(pod).
+					Build(),
+				Log: logging.WithName("test"),
+			},
+			vm:        vm,
+			hook:      &api.Hook{},
+			clientset: clientset,
+		}
+		return
+	}
+
+	ginkgo.It("should merge the output reported in the log", func() {
+		r, vm := runner(http.StatusOK, "starting\n"+api.HookOutputPrefix+`{"ip": "10.0.0.1"}`+"\n")
+		gomega.Expect(r.collectOutput(job)).To(gomega.Succeed())
+		gomega.Expect(vm.HookOutputs).To(gomega.HaveKeyWithValue("ip", "10.0.0.1"))
+	})
+	ginkgo.It("should not fail when the log is not available", func() {
+		r, vm := runner(http.StatusInternalServerError, "")
+		gomega.Expect(r.collectOutput(job)).To(gomega.Succeed())
+		gomega.Expect(vm.HookOutputs).To(gomega.BeEmpty())
+	})
+})
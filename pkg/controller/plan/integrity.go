@@ -0,0 +1,582 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	liburl "net/url"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Integrity pod labels.
+const (
+	// PVC label (value=UID)
+	kPVC = "pvcUID"
+	// Checksum label (value=source|target)
+	kChecksum = "checksum"
+	// App label value.
+	integrityApp = "integrity"
+)
+
+// Checksum label values.
+const (
+	sourceChecksum = "source"
+	targetChecksum = "target"
+)
+
+// Block hash computed by ImageIO, reported and computed
+// by the oVirt populator.
+const blockHashAlgorithm = "blkhash"
+
+// Checksum algorithms supported by the integrity pod.
+var integrityAlgorithms = map[string]bool{
+	"md5":    true,
+	"sha1":   true,
+	"sha256": true,
+	"sha384": true,
+	"sha512": true,
+}
+
+// Integrity.
+// Verifies the content of the target PVCs matches the checksum
+// of the source disk. The checksum is reported by the volume
+// populator or computed by a pod reading the source of the
+// DataVolume: the VMDK (snapshot) through VDDK or the ImageIO
+// checksum of the oVirt disk.
+type Integrity struct {
+	*plancontext.Context
+	kubevirt *KubeVirt
+}
+
+// Run the integrity check.
+// A pod computes the checksum of each target PVC for which the
+// source checksum is known, after the source pod computed it when
+// not reported by a populator. The step is marked completed when
+// all of the pods have terminated and fails on checksum mismatch.
+func (r *Integrity) Run(vm *planapi.VMStatus, step *planapi.Step) (err error) {
+	step.MarkStarted()
+	pvcs, err := r.kubevirt.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	dvs, err := r.kubevirt.getDVs(vm)
+	if err != nil {
+		return
+	}
+	pods, err := r.kubevirt.GetPodsWithLabels(r.kubevirt.integrityLabels(vm.Ref))
+	if err != nil {
+		return
+	}
+	unverified := []string{}
+	offloaded := []string{}
+	completed := int64(0)
+	for _, pvc := range pvcs {
+		if _, ok := pvc.Annotations[base.AnnOffload]; ok {
+			// cloned by the storage array
+			offloaded = append(offloaded, pvc.Name)
+			completed++
+			continue
+		}
+		dv := findDataVolume(dvs, pvc)
+		integrity, found := vm.FindIntegrity(pvc.Name)
+		if !found {
+			integrity, err = r.source(vm, pvc, dv)
+			if err != nil {
+				return
+			}
+			if integrity == nil {
+				unverified = append(unverified, pvc.Name)
+				completed++
+				continue
+			}
+			vm.Integrity = append(vm.Integrity, *integrity)
+			integrity = &vm.Integrity[len(vm.Integrity)-1]
+		}
+		if integrity.Target != "" {
+			completed++
+			continue
+		}
+		if integrity.Source == "" {
+			pod := r.findPod(pods.Items, pvc, sourceChecksum)
+			if pod == nil {
+				err = r.createSourcePod(vm, pvc, dv, integrity)
+				if err != nil {
+					return
+				}
+				continue
+			}
+			switch pod.Status.Phase {
+			case core.PodSucceeded:
+				checksum, pErr := checkpoint.ParseChecksum(terminatedMessage(pod))
+				if pErr != nil || checksum.Algorithm != integrity.Algorithm || checksum.Size <= 0 {
+					step.AddError(fmt.Sprintf("Failed to compute the source checksum of PVC %s.", pvc.Name))
+					completed++
+					continue
+				}
+				integrity.Source = checksum.Digest
+				integrity.Size = checksum.Size
+			case core.PodFailed:
+				step.AddError(fmt.Sprintf("Failed to compute the source checksum of PVC %s.", pvc.Name))
+				completed++
+				continue
+			default:
+				continue
+			}
+		}
+		pod := r.findPod(pods.Items, pvc, targetChecksum)
+		if pod == nil {
+			err = r.createPod(vm, pvc, integrity)
+			if err != nil {
+				return
+			}
+			continue
+		}
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+			integrity.Target = podChecksum(pod)
+			integrity.Verified = integrity.Target == integrity.Source
+			if !integrity.Verified {
+				step.AddError(
+					fmt.Sprintf(
+						"The %s checksum of PVC %s does not match the source: expected %s, got %s.",
+						integrity.Algorithm,
+						pvc.Name,
+						integrity.Source,
+						integrity.Target))
+			}
+			completed++
+		case core.PodFailed:
+			step.AddError(fmt.Sprintf("Failed to compute the checksum of PVC %s.", pvc.Name))
+			completed++
+		}
+	}
+	step.Progress.Total = int64(len(pvcs))
+	step.Progress.Completed = completed
+	if completed < step.Progress.Total {
+		return
+	}
+	if len(unverified) > 0 {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMIntegrityNotVerified,
+				Status:   True,
+				Category: Warn,
+				Reason:   NotSupported,
+				Message:  "The source checksum of the disks is not known; the integrity was not verified.",
+				Items:    unverified,
+				Durable:  true,
+			})
+	}
+	if len(offloaded) > 0 {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMOffloadNotVerified,
+				Status:   True,
+				Category: Warn,
+				Reason:   NotSupported,
+				Message:  "The disks cloned by the storage array were not verified.",
+				Items:    offloaded,
+				Durable:  true,
+			})
+	}
+	err = r.kubevirt.DeleteIntegrityPods(vm)
+	if err != nil {
+		return
+	}
+	step.MarkCompleted()
+	return
+}
+
+// Integrity of the source disk.
+// Returns nil when the source checksum is neither reported by
+// a populator nor can be computed from the DataVolume source.
+func (r *Integrity) source(vm *planapi.VMStatus, pvc *core.PersistentVolumeClaim, dv *cdi.DataVolume) (integrity *planapi.DiskIntegrity, err error) {
+	if dv != nil {
+		integrity = r.dataVolumeSource(vm, pvc, dv)
+		return
+	}
+	integrity, err = r.populatorSource(pvc)
+	return
+}
+
+// Integrity of the source disk copied by CDI.
+// The source checksum is computed by the source pod.
+func (r *Integrity) dataVolumeSource(vm *planapi.VMStatus, pvc *core.PersistentVolumeClaim, dv *cdi.DataVolume) (integrity *planapi.DiskIntegrity) {
+	source := dv.Spec.Source
+	if source == nil {
+		return
+	}
+	switch {
+	case source.VDDK != nil:
+		if source.VDDK.InitImageURL == "" || vm.Ref.ID == "" {
+			return
+		}
+		integrity = &planapi.DiskIntegrity{
+			PVC:       pvc.Name,
+			Algorithm: "sha256",
+		}
+	case source.Imageio != nil:
+		// The ImageIO checksum is computed over the
+		// current content of the disk, not a snapshot.
+		if Settings.Migration.OvirtPopulatorImage == "" || len(dv.Spec.Checkpoints) > 0 {
+			return
+		}
+		integrity = &planapi.DiskIntegrity{
+			PVC:       pvc.Name,
+			Algorithm: blockHashAlgorithm,
+		}
+	}
+	return
+}
+
+// Integrity of the source disk recorded on the populator CR.
+// Returns nil when the PVC was not populated by a populator
+// reporting the checksum.
+func (r *Integrity) populatorSource(pvc *core.PersistentVolumeClaim) (integrity *planapi.DiskIntegrity, err error) {
+	ref := pvc.Spec.DataSourceRef
+	if ref == nil || ref.APIGroup == nil || *ref.APIGroup != api.SchemeGroupVersion.Group {
+		return
+	}
+	key := client.ObjectKey{Namespace: pvc.Namespace, Name: ref.Name}
+	if ref.Namespace != nil {
+		key.Namespace = *ref.Namespace
+	}
+	var checksum string
+	var size int64
+	switch ref.Kind {
+	case api.OvirtVolumePopulatorKind:
+		cr := &api.OvirtVolumePopulator{}
+		err = r.Destination.Client.Get(context.TODO(), key, cr)
+		checksum, size = cr.Status.Checksum, cr.Status.ChecksumSize
+	case api.OpenstackVolumePopulatorKind:
+		cr := &api.OpenstackVolumePopulator{}
+		err = r.Destination.Client.Get(context.TODO(), key, cr)
+		checksum, size = cr.Status.Checksum, cr.Status.ChecksumSize
+	case api.HttpVolumePopulatorKind:
+		cr := &api.HttpVolumePopulator{}
+		err = r.Destination.Client.Get(context.TODO(), key, cr)
+		checksum, size = cr.Status.Checksum, cr.Status.ChecksumSize
+	default:
+		return
+	}
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	if !found || !integrityAlgorithms[algorithm] || size <= 0 {
+		return
+	}
+	integrity = &planapi.DiskIntegrity{
+		PVC:       pvc.Name,
+		Algorithm: algorithm,
+		Size:      size,
+		Source:    strings.ToLower(digest),
+	}
+	return
+}
+
+// Find the DataVolume of the PVC.
+func findDataVolume(dvs []ExtendedDataVolume, pvc *core.PersistentVolumeClaim) *cdi.DataVolume {
+	for _, dv := range dvs {
+		if dv.Name == pvc.Name && dv.Namespace == pvc.Namespace {
+			return dv.DataVolume
+		}
+	}
+	return nil
+}
+
+// Find the (source or target) integrity pod of the PVC.
+func (r *Integrity) findPod(pods []core.Pod, pvc *core.PersistentVolumeClaim, checksum string) *core.Pod {
+	for i := range pods {
+		if pods[i].Labels[kPVC] == string(pvc.UID) && pods[i].Labels[kChecksum] == checksum {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+// Create the pod computing the checksum of the source of the
+// DataVolume. The checksum is reported as the termination message
+// ("<algorithm>:<digest>:<size>").
+func (r *Integrity) createSourcePod(vm *planapi.VMStatus, pvc *core.PersistentVolumeClaim, dv *cdi.DataVolume, integrity *planapi.DiskIntegrity) (err error) {
+	container := r.container()
+	spec := core.PodSpec{
+		RestartPolicy: core.RestartPolicyNever,
+		SecurityContext: &core.PodSecurityContext{
+			SeccompProfile: &core.SeccompProfile{
+				Type: core.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+	source := dv.Spec.Source
+	switch integrity.Algorithm {
+	case blockHashAlgorithm:
+		engineURL, pErr := liburl.Parse(source.Imageio.URL)
+		if pErr != nil {
+			err = liberr.Wrap(pErr)
+			return
+		}
+		engineURL.Path = ""
+		insecure := "false"
+		if r.Source.Secret != nil {
+			if value, found := r.Source.Secret.Data["insecureSkipVerify"]; found {
+				insecure = string(value)
+			}
+		}
+		container.Image = Settings.Migration.OvirtPopulatorImage
+		container.Args = []string{
+			"--checksum-only",
+			"--engine-url=" + engineURL.String(),
+			"--disk-id=" + source.Imageio.DiskID,
+		}
+		container.Env = []core.EnvVar{
+			secretEnv("user", source.Imageio.SecretRef, "accessKeyId"),
+			secretEnv("password", source.Imageio.SecretRef, "secretKey"),
+			{
+				Name: "cacert",
+				ValueFrom: &core.EnvVarSource{
+					ConfigMapKeyRef: &core.ConfigMapKeySelector{
+						LocalObjectReference: core.LocalObjectReference{Name: source.Imageio.CertConfigMap},
+						Key:                  "ca.pem",
+						Optional:             ptr.To(true),
+					},
+				},
+			},
+			{
+				Name:  "insecureSkipVerify",
+				Value: insecure,
+			},
+		}
+	default:
+		vddkURL, pErr := liburl.Parse(source.VDDK.URL)
+		if pErr != nil {
+			err = liberr.Wrap(pErr)
+			return
+		}
+		var snapshot string
+		if n := len(dv.Spec.Checkpoints); n > 0 {
+			snapshot = dv.Spec.Checkpoints[n-1].Current
+		}
+		container.Env = []core.EnvVar{
+			{Name: "SERVER", Value: vddkURL.Hostname()},
+			{Name: "THUMBPRINT", Value: source.VDDK.Thumbprint},
+			{Name: "MOREF", Value: vm.Ref.ID},
+			{Name: "SNAPSHOT", Value: snapshot},
+			{Name: "FILE", Value: source.VDDK.BackingFile},
+		}
+		// The disk is read through nbdkit as CDI does.
+		container.Command = []string{
+			"/bin/bash",
+			"-c",
+			strings.Join(
+				[]string{
+					"set -o errexit -o pipefail",
+					"nbdkit --readonly -U - vddk libdir=/opt/vmware-vix-disklib-distrib" +
+						" server=\"$SERVER\" user=\"$(cat /etc/secret/accessKeyId)\" password=+/etc/secret/secretKey" +
+						" thumbprint=\"$THUMBPRINT\" vm=moref=\"$MOREF\" ${SNAPSHOT:+snapshot=$SNAPSHOT} file=\"$FILE\"" +
+						" --run 'set -o pipefail; nbdinfo --size \"$uri\" > /tmp/size && nbdcopy \"$uri\" - | sha256sum > /tmp/digest'",
+					"echo \"sha256:$(cut -d' ' -f1 /tmp/digest):$(cat /tmp/size)\" > /dev/termination-log",
+				},
+				"\n"),
+		}
+		container.VolumeMounts = []core.VolumeMount{
+			{
+				Name:      VddkVolumeName,
+				MountPath: "/opt",
+			},
+			{
+				Name:      "secret",
+				MountPath: "/etc/secret",
+				ReadOnly:  true,
+			},
+		}
+		vddk := r.container()
+		vddk.Name = "vddk-side-car"
+		vddk.Image = source.VDDK.InitImageURL
+		vddk.VolumeMounts = []core.VolumeMount{
+			{
+				Name:      VddkVolumeName,
+				MountPath: "/opt",
+			},
+		}
+		spec.InitContainers = []core.Container{vddk}
+		spec.Volumes = []core.Volume{
+			{
+				Name: VddkVolumeName,
+				VolumeSource: core.VolumeSource{
+					EmptyDir: &core.EmptyDirVolumeSource{},
+				},
+			},
+			{
+				Name: "secret",
+				VolumeSource: core.VolumeSource{
+					Secret: &core.SecretVolumeSource{
+						SecretName: source.VDDK.SecretRef,
+					},
+				},
+			},
+		}
+	}
+	spec.Containers = []core.Container{container}
+	err = r.createIntegrityPod(vm, pvc, sourceChecksum, spec)
+	return
+}
+
+// Create the pod computing the checksum of the PVC.
+// The checksum is reported as the termination message.
+func (r *Integrity) createPod(vm *planapi.VMStatus, pvc *core.PersistentVolumeClaim, integrity *planapi.DiskIntegrity) (err error) {
+	container := r.container()
+	var path string
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == core.PersistentVolumeBlock {
+		path = "/dev/block"
+		container.VolumeDevices = []core.VolumeDevice{
+			{
+				Name:       "disk",
+				DevicePath: path,
+			},
+		}
+	} else {
+		path = "/mnt/disk.img"
+		container.VolumeMounts = []core.VolumeMount{
+			{
+				Name:      "disk",
+				MountPath: "/mnt",
+				ReadOnly:  true,
+			},
+		}
+	}
+	if integrity.Algorithm == blockHashAlgorithm {
+		// The oVirt populator reports the block hash.
+		container.Image = Settings.Migration.OvirtPopulatorImage
+		container.Args = []string{
+			"--volume-path=" + path,
+			fmt.Sprintf("--digest-size=%d", integrity.Size),
+		}
+	} else {
+		container.Command = []string{
+			"/bin/bash",
+			"-c",
+			fmt.Sprintf(
+				"set -o pipefail; head -c %d %s | %ssum | cut -d' ' -f1 > /dev/termination-log",
+				integrity.Size,
+				path,
+				integrity.Algorithm),
+		}
+	}
+	spec := core.PodSpec{
+		RestartPolicy: core.RestartPolicyNever,
+		Containers:    []core.Container{container},
+		Volumes: []core.Volume{
+			{
+				Name: "disk",
+				VolumeSource: core.VolumeSource{
+					PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		SecurityContext: &core.PodSecurityContext{
+			FSGroup: ptr.To(qemuGroup),
+			SeccompProfile: &core.SeccompProfile{
+				Type: core.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+	err = r.createIntegrityPod(vm, pvc, targetChecksum, spec)
+	return
+}
+
+// Unprivileged integrity pod container.
+func (r *Integrity) container() core.Container {
+	return core.Container{
+		Name:  "main",
+		Image: Settings.Migration.VirtV2vImageCold,
+		SecurityContext: &core.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			RunAsNonRoot:             ptr.To(true),
+			RunAsUser:                ptr.To(qemuUser),
+			Capabilities: &core.Capabilities{
+				Drop: []core.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// Create an integrity pod of the PVC.
+func (r *Integrity) createIntegrityPod(vm *planapi.VMStatus, pvc *core.PersistentVolumeClaim, checksum string, spec core.PodSpec) (err error) {
+	labels := r.kubevirt.integrityLabels(vm.Ref)
+	labels[kPVC] = string(pvc.UID)
+	labels[kChecksum] = checksum
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    pvc.Namespace,
+			Labels:       labels,
+			GenerateName: r.kubevirt.getGeneratedName(vm) + "integrity-",
+		},
+		Spec: spec,
+	}
+	err = r.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created integrity pod.",
+		"pod",
+		pod.Namespace+"/"+pod.Name,
+		"pvc",
+		pvc.Name,
+		"checksum",
+		checksum,
+		"vm",
+		vm.String())
+	return
+}
+
+// Environment variable set from a secret key.
+func secretEnv(name, secret, key string) core.EnvVar {
+	return core.EnvVar{
+		Name: name,
+		ValueFrom: &core.EnvVarSource{
+			SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// Checksum reported in the termination message of the integrity pod.
+func podChecksum(pod *core.Pod) string {
+	return terminatedMessage(pod)
+}
+
+// Termination message of the (first) terminated container.
+func terminatedMessage(pod *core.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return strings.TrimSpace(status.State.Terminated.Message)
+		}
+	}
+	return ""
+}
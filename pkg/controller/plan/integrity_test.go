@@ -0,0 +1,268 @@
+package plan
+
+import (
+	"context"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Integrity", func() {
+	var vm *planapi.VMStatus
+	var step *planapi.Step
+	var integrity *Integrity
+	var dClient client.Client
+
+	build := func(objects ...runtime.Object) {
+		scheme := runtime.NewScheme()
+		_ = core.AddToScheme(scheme)
+		_ = api.SchemeBuilder.AddToScheme(scheme)
+		_ = cdi.AddToScheme(scheme)
+		dClient = fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(objects...).
+			Build()
+		plan := &api.Plan{}
+		plan.Spec.TargetNamespace = "test"
+		ctx := &plancontext.Context{
+			Plan:      plan,
+			Migration: createMigration(),
+			Client:    dClient,
+			Log:       logging.WithName("test"),
+		}
+		ctx.Destination.Client = dClient
+		integrity = &Integrity{Context: ctx, kubevirt: &KubeVirt{Context: ctx}}
+		vm = &planapi.VMStatus{}
+		vm.Ref = ref.Ref{ID: "vm1"}
+		vm.Name = "vm1"
+		step = &planapi.Step{Task: planapi.Task{Name: DiskIntegrity}}
+	}
+	pvc := func(name string, populated bool) *core.PersistentVolumeClaim {
+		object := &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+				UID:       types.UID("uid-" + name),
+				Labels: map[string]string{
+					kMigration: "test",
+					kVM:        "vm1",
+				},
+			},
+		}
+		if populated {
+			object.Spec.DataSourceRef = &core.TypedObjectReference{
+				APIGroup: ptr.To(api.SchemeGroupVersion.Group),
+				Kind:     api.OvirtVolumePopulatorKind,
+				Name:     name,
+			}
+		}
+		return object
+	}
+	populator := func(name, checksum string) *api.OvirtVolumePopulator {
+		object := &api.OvirtVolumePopulator{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: name},
+		}
+		object.Status.Checksum = checksum
+		object.Status.ChecksumSize = 1024
+		return object
+	}
+	dataVolume := func(name string, source *cdi.DataVolumeSource) *cdi.DataVolume {
+		return &cdi.DataVolume{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+				Labels: map[string]string{
+					kMigration: "test",
+					kPlan:      "",
+					kVM:        "vm1",
+				},
+			},
+			Spec: cdi.DataVolumeSpec{Source: source},
+		}
+	}
+	pods := func() []core.Pod {
+		list := &core.PodList{}
+		err := dClient.List(context.TODO(), list, client.InNamespace("test"))
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		return list.Items
+	}
+	terminate := func(message string) {
+		for _, pod := range pods() {
+			if pod.Status.Phase == core.PodSucceeded {
+				continue
+			}
+			pod.Status.Phase = core.PodSucceeded
+			pod.Status.ContainerStatuses = []core.ContainerStatus{
+				{
+					Name: "main",
+					State: core.ContainerState{
+						Terminated: &core.ContainerStateTerminated{Message: message + "\n"},
+					},
+				},
+			}
+			gomega.Expect(dClient.Status().Update(context.TODO(), &pod)).To(gomega.Succeed())
+		}
+	}
+
+	ginkgo.It("should compute the checksum of the populated PVC", func() {
+		build(pvc("disk1", true), populator("disk1", "sha256:ABC"))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		gomega.Expect(vm.Integrity).To(gomega.HaveLen(1))
+		gomega.Expect(vm.Integrity[0].Algorithm).To(gomega.Equal("sha256"))
+		gomega.Expect(vm.Integrity[0].Source).To(gomega.Equal("abc"))
+		gomega.Expect(vm.Integrity[0].Size).To(gomega.Equal(int64(1024)))
+		created := pods()
+		gomega.Expect(created).To(gomega.HaveLen(1))
+		gomega.Expect(created[0].Labels[kPVC]).To(gomega.Equal("uid-disk1"))
+		gomega.Expect(created[0].Spec.Containers[0].Command[2]).To(gomega.ContainSubstring("head -c 1024 /mnt/disk.img | sha256sum"))
+	})
+
+	ginkgo.It("should complete when the checksums match", func() {
+		build(pvc("disk1", true), populator("disk1", "sha256:abc"))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		terminate("abc")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(vm.Integrity[0].Target).To(gomega.Equal("abc"))
+		gomega.Expect(vm.Integrity[0].Verified).To(gomega.BeTrue())
+		gomega.Expect(pods()).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should fail the step when the checksums do not match", func() {
+		build(pvc("disk1", true), populator("disk1", "sha256:abc"))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		terminate("def")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+		gomega.Expect(vm.Integrity[0].Verified).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should flag the disks without a source checksum", func() {
+		build(pvc("disk1", false), pvc("disk2", true), populator("disk2", ""))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(vm.Integrity).To(gomega.BeEmpty())
+		gomega.Expect(pods()).To(gomega.BeEmpty())
+		condition := vm.FindCondition(VMIntegrityNotVerified)
+		gomega.Expect(condition).ToNot(gomega.BeNil())
+		gomega.Expect(condition.Items).To(gomega.ConsistOf("disk1", "disk2"))
+	})
+
+	ginkgo.It("should flag the disks cloned by the storage array", func() {
+		cloned := pvc("disk1", false)
+		cloned.Annotations = map[string]string{base.AnnOffload: "fake"}
+		build(cloned)
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(pods()).To(gomega.BeEmpty())
+		gomega.Expect(vm.FindCondition(VMIntegrityNotVerified)).To(gomega.BeNil())
+		condition := vm.FindCondition(VMOffloadNotVerified)
+		gomega.Expect(condition).ToNot(gomega.BeNil())
+		gomega.Expect(condition.Items).To(gomega.ConsistOf("disk1"))
+	})
+
+	ginkgo.It("should read the VMDK of the DataVolume through VDDK", func() {
+		build(
+			pvc("disk1", false),
+			dataVolume("disk1", &cdi.DataVolumeSource{
+				VDDK: &cdi.DataVolumeSourceVDDK{
+					URL:          "https://vcenter.example.com/sdk",
+					BackingFile:  "[ds1] vm1/vm1.vmdk",
+					Thumbprint:   "AA:BB",
+					SecretRef:    "vddk",
+					InitImageURL: "vddk:latest",
+				},
+			}))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(vm.Integrity).To(gomega.HaveLen(1))
+		gomega.Expect(vm.Integrity[0].Source).To(gomega.BeEmpty())
+		created := pods()
+		gomega.Expect(created).To(gomega.HaveLen(1))
+		gomega.Expect(created[0].Labels[kChecksum]).To(gomega.Equal(sourceChecksum))
+		gomega.Expect(created[0].Spec.InitContainers[0].Image).To(gomega.Equal("vddk:latest"))
+		gomega.Expect(created[0].Spec.Containers[0].Env).To(gomega.ContainElements(
+			core.EnvVar{Name: "SERVER", Value: "vcenter.example.com"},
+			core.EnvVar{Name: "MOREF", Value: "vm1"},
+			core.EnvVar{Name: "FILE", Value: "[ds1] vm1/vm1.vmdk"}))
+		terminate("sha256:ABC:2048")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(vm.Integrity[0].Source).To(gomega.Equal("abc"))
+		gomega.Expect(vm.Integrity[0].Size).To(gomega.Equal(int64(2048)))
+		created = pods()
+		gomega.Expect(created).To(gomega.HaveLen(2))
+		target := integrity.findPod(created, pvc("disk1", false), targetChecksum)
+		gomega.Expect(target).ToNot(gomega.BeNil())
+		gomega.Expect(target.Spec.Containers[0].Command[2]).To(gomega.ContainSubstring("head -c 2048 /mnt/disk.img | sha256sum"))
+		terminate("abc")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(vm.Integrity[0].Verified).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should compare the ImageIO checksum of the DataVolume", func() {
+		Settings.Migration.OvirtPopulatorImage = "ovirt-populator:latest"
+		defer func() {
+			Settings.Migration.OvirtPopulatorImage = ""
+		}()
+		build(
+			pvc("disk1", false),
+			dataVolume("disk1", &cdi.DataVolumeSource{
+				Imageio: &cdi.DataVolumeSourceImageIO{
+					URL:           "https://engine.example.com/ovirt-engine/api",
+					DiskID:        "disk-id",
+					SecretRef:     "imageio",
+					CertConfigMap: "imageio",
+				},
+			}))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		created := pods()
+		gomega.Expect(created).To(gomega.HaveLen(1))
+		gomega.Expect(created[0].Spec.Containers[0].Image).To(gomega.Equal("ovirt-populator:latest"))
+		gomega.Expect(created[0].Spec.Containers[0].Args).To(gomega.ConsistOf(
+			"--checksum-only",
+			"--engine-url=https://engine.example.com",
+			"--disk-id=disk-id"))
+		terminate("blkhash:abc:2048")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		target := integrity.findPod(pods(), pvc("disk1", false), targetChecksum)
+		gomega.Expect(target).ToNot(gomega.BeNil())
+		gomega.Expect(target.Spec.Containers[0].Args).To(gomega.ConsistOf(
+			"--volume-path=/mnt/disk.img",
+			"--digest-size=2048"))
+	})
+
+	ginkgo.It("should fail the step when the source checksum is not computed", func() {
+		build(
+			pvc("disk1", false),
+			dataVolume("disk1", &cdi.DataVolumeSource{
+				VDDK: &cdi.DataVolumeSourceVDDK{
+					URL:          "https://vcenter.example.com/sdk",
+					InitImageURL: "vddk:latest",
+				},
+			}))
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		terminate("")
+		gomega.Expect(integrity.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+	})
+})
@@ -82,6 +82,8 @@ const (
 	kApp = "forklift.app"
 	// LUKS
 	kLUKS = "isLUKS"
+	// Test failover (rehearsal) label (value=true)
+	kRehearsal = "rehearsal"
 )
 
 // User
@@ -1023,6 +1025,36 @@ func (r *KubeVirt) DeleteGuestConversionPod(vm *plan.VMStatus) (err error) {
 	return
 }
 
+// Delete the integrity pods of the VM.
+func (r *KubeVirt) DeleteIntegrityPods(vm *plan.VMStatus) (err error) {
+	list, err := r.GetPodsWithLabels(r.integrityLabels(vm.Ref))
+	if err != nil {
+		return
+	}
+	for _, object := range list.Items {
+		err = r.DeleteObject(&object, vm, "Deleted integrity pod.", "pod")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Delete the verification pods of the VM.
+func (r *KubeVirt) DeleteVerifyPods(vm *plan.VMStatus) (err error) {
+	list, err := r.GetPodsWithLabels(r.verifyLabels(vm.Ref))
+	if err != nil {
+		return
+	}
+	for _, object := range list.Items {
+		err = r.DeleteObject(&object, vm, "Deleted verification pod.", "pod")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
 // Gets pods associated with the VM.
 func (r *KubeVirt) GetPods(vm *plan.VMStatus) (pods *core.PodList, err error) {
 	return r.GetPodsWithLabels(r.vmAllButMigrationLabels(vm.Ref))
@@ -1093,6 +1125,111 @@ func (r *KubeVirt) DeleteHookJobs(vm *plan.VMStatus) (err error) {
 	return
 }
 
+// Delete the objects created by the test failover.
+// Selected by the rehearsal label only so that objects
+// created by migrations are never deleted.
+func (r *KubeVirt) DeleteRehearsal() (err error) {
+	selector := client.MatchingLabels(r.rehearsalLabels())
+	destination := []client.Object{
+		&cnv.VirtualMachine{},
+		&cdi.DataVolume{},
+		&core.PersistentVolumeClaim{},
+		&core.Pod{},
+		&core.Secret{},
+		&core.ConfigMap{},
+		&batch.Job{},
+	}
+	for _, kind := range destination {
+		err = r.Destination.Client.DeleteAllOf(
+			context.TODO(),
+			kind,
+			client.InNamespace(r.Plan.Spec.TargetNamespace),
+			selector,
+			client.PropagationPolicy(meta.DeletePropagationBackground))
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	// hook jobs run in the plan namespace.
+	for _, kind := range []client.Object{&batch.Job{}, &core.ConfigMap{}} {
+		err = r.Client.DeleteAllOf(
+			context.TODO(),
+			kind,
+			client.InNamespace(r.Plan.Namespace),
+			selector,
+			client.PropagationPolicy(meta.DeletePropagationBackground))
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = nil
+	r.Log.Info(
+		"Deleted test failover objects.",
+		"namespace",
+		r.Plan.Spec.TargetNamespace)
+	return
+}
+
+// Delete the plan-level hook jobs and configMaps.
+// The hook resources are created in the plan namespace.
+func (r *KubeVirt) DeletePlanHookJobs() (err error) {
+	hookLabels := r.planLabels()
+	delete(hookLabels, kMigration)
+	hookLabels[kVM] = PlanHookID
+	options := &client.ListOptions{
+		LabelSelector: k8slabels.SelectorFromSet(hookLabels),
+		Namespace:     r.Plan.Namespace,
+	}
+	jobs := &batch.JobList{}
+	err = r.Client.List(context.TODO(), jobs, options)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		err = r.Client.Delete(
+			context.TODO(),
+			job,
+			client.PropagationPolicy(meta.DeletePropagationBackground))
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		r.Log.Info(
+			"Deleted (hook) job.",
+			"job",
+			path.Join(
+				job.Namespace,
+				job.Name))
+	}
+	maps := &core.ConfigMapList{}
+	err = r.Client.List(context.TODO(), maps, options)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range maps.Items {
+		mp := &maps.Items[i]
+		err = r.Client.Delete(context.TODO(), mp)
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		r.Log.Info(
+			"Deleted (hook) configMap.",
+			"map",
+			path.Join(
+				mp.Namespace,
+				mp.Name))
+	}
+	return
+}
+
 // Set the Populator Pod Ownership.
 func (r *KubeVirt) SetPopulatorPodOwnership(vm *plan.VMStatus) (err error) {
 	pvcs, err := r.getPVCs(vm.Ref)
@@ -1210,7 +1347,7 @@ func (r *KubeVirt) dataVolumes(vm *plan.VMStatus, secret *core.Secret, configMap
 		annotations[AnnDefaultNetwork] = path.Join(
 			r.Plan.Spec.TransferNetwork.Namespace, r.Plan.Spec.TransferNetwork.Name)
 	}
-	if r.Plan.Spec.Warm || !r.Destination.Provider.IsHost() || r.Plan.IsSourceProviderOCP() {
+	if r.Plan.SnapshotCopy() || !r.Destination.Provider.IsHost() || r.Plan.IsSourceProviderOCP() {
 		// Set annotation for WFFC storage classes. Note that we create data volumes while
 		// running a cold migration to the local cluster only when the source is either OpenShift
 		// or vSphere, and in the latter case the conversion pod acts as the first-consumer
@@ -1721,6 +1858,12 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 				Value: vm.RootDisk,
 			})
 	}
+	// virt-v2v copies the disks through nbdkit, limited by the rate filter.
+	if el9 {
+		if limit := r.Context.RateLimit(len(vmVolumes)); limit != nil {
+			environment = append(environment, rateLimitEnv(limit)...)
+		}
+	}
 	// pod annotations
 	annotations := map[string]string{}
 	if r.Plan.Spec.TransferNetwork != nil {
@@ -1789,6 +1932,24 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 	return
 }
 
+// Environment of the virt-v2v transfer rate limit.
+func rateLimitEnv(limit *plan.RateLimit) []core.EnvVar {
+	windows := make([]string, 0, len(limit.Schedule))
+	for i := range limit.Schedule {
+		windows = append(windows, limit.Schedule[i].String())
+	}
+	return []core.EnvVar{
+		{
+			Name:  "V2V_rateLimit",
+			Value: strconv.FormatInt(limit.Rate, 10),
+		},
+		{
+			Name:  "V2V_rateWindows",
+			Value: strings.Join(windows, " "),
+		},
+	}
+}
+
 func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs []*core.PersistentVolumeClaim, vm *plan.VMStatus) (volumes []core.Volume, mounts []core.VolumeMount, devices []core.VolumeDevice, err error) {
 	pvcsByName := make(map[string]*core.PersistentVolumeClaim)
 	for _, pvc := range pvcs {
@@ -2207,11 +2368,25 @@ func (r *KubeVirt) secret(vmRef ref.Ref, setSecretData func(*core.Secret) error,
 }
 
 // Labels for plan and migration.
-func (r *KubeVirt) planLabels() map[string]string {
-	return map[string]string{
+// Objects created by a test failover are labeled
+// as rehearsal objects.
+func (r *KubeVirt) planLabels() (labels map[string]string) {
+	labels = map[string]string{
 		kMigration: string(r.Migration.UID),
 		kPlan:      string(r.Plan.GetUID()),
 	}
+	if r.Plan.Rehearsing() {
+		labels[kRehearsal] = "true"
+	}
+	return
+}
+
+// Labels for objects created by a test failover.
+func (r *KubeVirt) rehearsalLabels() map[string]string {
+	return map[string]string{
+		kPlan:      string(r.Plan.GetUID()),
+		kRehearsal: "true",
+	}
 }
 
 // Label for a PVC consumer pod.
@@ -2236,6 +2411,20 @@ func (r *KubeVirt) conversionLabels(vmRef ref.Ref, filterOutMigrationLabel bool)
 	return
 }
 
+// Labels for an integrity pod.
+func (r *KubeVirt) integrityLabels(vmRef ref.Ref) (labels map[string]string) {
+	labels = r.vmLabels(vmRef)
+	labels[kApp] = integrityApp
+	return
+}
+
+// Labels for a verification pod.
+func (r *KubeVirt) verifyLabels(vmRef ref.Ref) (labels map[string]string) {
+	labels = r.vmLabels(vmRef)
+	labels[kApp] = verifyApp
+	return
+}
+
 // Labels for a VM on a plan.
 func (r *KubeVirt) vmLabels(vmRef ref.Ref) (labels map[string]string) {
 	labels = r.planLabels()
@@ -2,15 +2,21 @@
 package plan
 
 import (
+	"context"
+
 	v1beta1 "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
 	ginkgo "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -37,11 +43,118 @@ var _ = ginkgo.Describe("kubevirt tests", func() {
 		})
 	})
 
+	ginkgo.Describe("rateLimitEnv", func() {
+		ginkgo.It("should pass the rate and the windows to virt-v2v", func() {
+			limit := &planapi.RateLimit{
+				Rate: 1000,
+				Schedule: []planapi.RateWindow{
+					{
+						TimeWindow: planapi.TimeWindow{Start: "09:00", End: "17:00", Days: []planapi.Weekday{"Mon", "Tue"}},
+						Rate:       100,
+					},
+					{
+						TimeWindow: planapi.TimeWindow{Start: "22:00", End: "06:00"},
+					},
+				},
+			}
+			Expect(rateLimitEnv(limit)).To(Equal([]v1.EnvVar{
+				{Name: "V2V_rateLimit", Value: "1000"},
+				{Name: "V2V_rateWindows", Value: "Mon,Tue/09:00-17:00=100 /22:00-06:00=0"},
+			}))
+		})
+	})
+
+	ginkgo.Describe("DeletePlanHookJobs", func() {
+		hookLabels := map[string]string{
+			"plan": "plan",
+			"vmID": PlanHookID,
+			"step": PreHook,
+		}
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-plan-prehook",
+				Namespace: "test",
+				Labels:    hookLabels,
+			},
+		}
+		mp := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-plan-prehook",
+				Namespace: "test",
+				Labels:    hookLabels,
+			},
+		}
+		vmJob := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-vm-prehook",
+				Namespace: "test",
+				Labels: map[string]string{
+					"plan": "plan",
+					"vmID": "vm-1",
+					"step": PreHook,
+				},
+			},
+		}
+
+		ginkgo.It("should delete only the plan-level hook resources", func() {
+			kubevirt := createKubeVirt(job, mp, vmJob)
+			kubevirt.Plan = &v1beta1.Plan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+					UID:       "plan",
+				},
+			}
+			Expect(kubevirt.DeletePlanHookJobs()).To(Succeed())
+			jobs := &batchv1.JobList{}
+			Expect(kubevirt.Client.List(context.TODO(), jobs)).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Name).To(Equal("test-vm-prehook"))
+			maps := &v1.ConfigMapList{}
+			Expect(kubevirt.Client.List(context.TODO(), maps)).To(Succeed())
+			Expect(maps.Items).To(BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("DeleteRehearsal", func() {
+		secret := func(name string, labels map[string]string) *v1.Secret {
+			return &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "rehearsal",
+					Labels:    labels,
+				},
+			}
+		}
+
+		ginkgo.It("should delete only the rehearsal objects", func() {
+			kubevirt := createKubeVirt(
+				secret("test", map[string]string{"plan": "plan", "rehearsal": "true"}),
+				secret("production", map[string]string{"plan": "plan"}))
+			kubevirt.Plan = &v1beta1.Plan{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+					UID:       "plan",
+				},
+			}
+			kubevirt.Plan.Spec.TargetNamespace = "rehearsal"
+			Expect(kubevirt.DeleteRehearsal()).To(Succeed())
+			secrets := &v1.SecretList{}
+			Expect(kubevirt.Client.List(context.TODO(), secrets)).To(Succeed())
+			Expect(secrets.Items).To(HaveLen(1))
+			Expect(secrets.Items[0].Name).To(Equal("production"))
+		})
+	})
+
 })
 
 func createKubeVirt(objs ...runtime.Object) *KubeVirt {
 	scheme := runtime.NewScheme()
 	_ = v1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
 	v1beta1.SchemeBuilder.AddToScheme(scheme)
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -48,6 +48,12 @@ var (
 	CDIDiskCopy             libitr.Flag = 0x08
 	VirtV2vDiskCopy         libitr.Flag = 0x10
 	OpenstackImageMigration libitr.Flag = 0x20
+	HasPostPowerOffHook     libitr.Flag = 0x40
+	HasPreCutoverHook       libitr.Flag = 0x80
+	HasPostConversionHook   libitr.Flag = 0x100
+	HasFailureHook          libitr.Flag = 0x200
+	RequiresVerification    libitr.Flag = 0x400
+	RequiresIntegrityCheck  libitr.Flag = 0x800
 )
 
 // Phases.
@@ -77,6 +83,12 @@ const (
 	WaitForInitialSnapshot   = "WaitForInitialSnapshot"
 	WaitForFinalSnapshot     = "WaitForFinalSnapshot"
 	ConvertOpenstackSnapshot = "ConvertOpenstackSnapshot"
+	PostPowerOffHook         = "PostPowerOffHook"
+	PreCutoverHook           = "PreCutoverHook"
+	PostConversionHook       = "PostConversionHook"
+	FailureHook              = "FailureHook"
+	VerifyVM                 = "VerifyVM"
+	VerifyDiskIntegrity      = "VerifyDiskIntegrity"
 )
 
 // Steps.
@@ -88,6 +100,8 @@ const (
 	ImageConversion = "ImageConversion"
 	DiskTransferV2v = "DiskTransferV2v"
 	VMCreation      = "VirtualMachineCreation"
+	Verification    = "Verification"
+	DiskIntegrity   = "DiskIntegrity"
 	Unknown         = "Unknown"
 )
 
@@ -105,14 +119,18 @@ var (
 			{Name: StorePowerState},
 			{Name: PowerOffSource},
 			{Name: WaitForPowerOff},
+			{Name: PostPowerOffHook, All: HasPostPowerOffHook},
 			{Name: CreateDataVolumes},
 			{Name: CopyDisks, All: CDIDiskCopy},
+			{Name: VerifyDiskIntegrity, All: CDIDiskCopy | RequiresIntegrityCheck},
 			{Name: AllocateDisks, All: VirtV2vDiskCopy},
 			{Name: CreateGuestConversionPod, All: RequiresConversion},
 			{Name: ConvertGuest, All: RequiresConversion},
 			{Name: CopyDisksVirtV2V, All: RequiresConversion},
 			{Name: ConvertOpenstackSnapshot, All: OpenstackImageMigration},
+			{Name: PostConversionHook, All: HasPostConversionHook},
 			{Name: CreateVM},
+			{Name: VerifyVM, All: RequiresVerification},
 			{Name: PostHook, All: HasPostHook},
 			{Name: Completed},
 		},
@@ -130,22 +148,58 @@ var (
 			{Name: CreateSnapshot},
 			{Name: WaitForSnapshot},
 			{Name: AddCheckpoint},
+			{Name: PreCutoverHook, All: HasPreCutoverHook},
 			{Name: StorePowerState},
 			{Name: PowerOffSource},
 			{Name: WaitForPowerOff},
+			{Name: PostPowerOffHook, All: HasPostPowerOffHook},
 			{Name: CreateFinalSnapshot},
 			{Name: WaitForFinalSnapshot},
 			{Name: AddFinalCheckpoint},
 			{Name: Finalize},
 			{Name: CreateGuestConversionPod, All: RequiresConversion},
 			{Name: ConvertGuest, All: RequiresConversion},
+			{Name: PostConversionHook, All: HasPostConversionHook},
 			{Name: CreateVM},
+			{Name: VerifyVM, All: RequiresVerification},
+			{Name: PostHook, All: HasPostHook},
+			{Name: Completed},
+		},
+	}
+	testItinerary = libitr.Itinerary{
+		Name: "Test",
+		Pipeline: libitr.Pipeline{
+			{Name: Started},
+			{Name: PreHook, All: HasPreHook},
+			{Name: StorePowerState},
+			{Name: CreateInitialSnapshot},
+			{Name: WaitForInitialSnapshot},
+			{Name: CreateDataVolumes},
+			{Name: CopyDisks},
+			{Name: VerifyDiskIntegrity, All: RequiresIntegrityCheck},
+			{Name: Finalize},
+			{Name: CreateGuestConversionPod, All: RequiresConversion},
+			{Name: ConvertGuest, All: RequiresConversion},
+			{Name: PostConversionHook, All: HasPostConversionHook},
+			{Name: CreateVM},
+			{Name: VerifyVM, All: RequiresVerification},
 			{Name: PostHook, All: HasPostHook},
 			{Name: Completed},
 		},
 	}
 )
 
+// Hook steps.
+// The hook run by each step is found by the step name.
+var HookSteps = map[string]libitr.Flag{
+	PreHook:            HasPreHook,
+	PostPowerOffHook:   HasPostPowerOffHook,
+	PreCutoverHook:     HasPreCutoverHook,
+	PostConversionHook: HasPostConversionHook,
+	PostHook:           HasPostHook,
+	FailureHook:        HasFailureHook,
+}
+
 // Migration.
 type Migration struct {
 	*plancontext.Context
@@ -191,6 +245,14 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 
 	r.resolveCanceledRefs()
 
+	running, failed, err := r.runPlanHook(PreHook)
+	if err != nil || running {
+		return
+	}
+	if failed {
+		r.failVMs("The plan pre-migration hook failed.")
+	}
+
 	for _, vm := range r.runningVMs() {
 		err = r.execute(vm)
 		if err != nil {
@@ -209,6 +271,9 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 		}
 	}
 
+	r.seedTransfers()
+	r.Plan.Status.Migration.ReflectTransfers(time.Now())
+
 	completed, err := r.end()
 	if completed {
 		reQ = NoReQ
@@ -256,6 +321,8 @@ func (r *Migration) begin() (err error) {
 	}
 	r.Plan.Status.Migration.MarkReset()
 	r.Plan.Status.Migration.MarkStarted()
+	r.Plan.Status.Migration.Hooks = r.planHooks()
+	r.Plan.Status.Migration.HookOutputs = nil
 	snapshot.SetCondition(
 		libcnd.Condition{
 			Type:     Executing,
@@ -300,7 +367,7 @@ func (r *Migration) begin() (err error) {
 		step, _ := r.itinerary().First()
 		if current, found := r.Plan.Status.Migration.FindVM(vm.Ref); !found {
 			status = &plan.VMStatus{VM: vm}
-			if r.Plan.Spec.Warm {
+			if r.Plan.SnapshotCopy() {
 				status.Warm = &plan.Warm{}
 			}
 		} else {
@@ -317,7 +384,7 @@ func (r *Migration) begin() (err error) {
 			status.Pipeline = pipeline
 			status.Phase = step.Name
 			status.Error = nil
-			if r.Plan.Spec.Warm {
+			if r.Plan.SnapshotCopy() {
 				status.Warm = &plan.Warm{}
 			}
 			log.Info(
@@ -371,6 +438,30 @@ func (r *Migration) Archive() {
 		}
 		_ = r.cleanup(vm, dontFailOnError)
 	}
+
+	if err := r.kubevirt.DeletePlanHookJobs(); err != nil {
+		r.Log.Error(err, "Failed to clean up plan hook job(s).")
+	}
+}
+
+// Clean up the test failover.
+// Best effort to remove the test VMs, their disks and
+// the source snapshots. Returns false when the cleanup
+// has not completed.
+func (r *Migration) CleanupRehearsal() bool {
+	if err := r.init(); err != nil {
+		r.Log.Error(err, "Rehearsal cleanup initialization failed.")
+		return false
+	}
+	if err := r.kubevirt.DeleteRehearsal(); err != nil {
+		r.Log.Error(err, "Couldn't clean up the test failover.")
+		return false
+	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		r.removeWarmSnapshots(vm)
+	}
+
+	return true
 }
 
 func (r *Migration) SetPopulatorDataSourceLabels() {
@@ -417,7 +508,8 @@ func (r *Migration) Cancel() error {
 				return false
 			}
 			_ = r.cleanup(vm, dontFailOnError)
-			if vm.RestorePowerState == plan.VMPowerStateOn {
+			// the source VM is not powered off by a test failover.
+			if vm.RestorePowerState == plan.VMPowerStateOn && r.Plan.Spec.Test == nil {
 				if err := r.provider.PowerOn(vm.Ref); err != nil {
 					r.Log.Error(err,
 						"Couldn't restore the power state of the source VM.",
@@ -467,6 +559,12 @@ func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error
 	if err := r.kubevirt.DeleteGuestConversionPod(vm); failOnErr(err) {
 		return err
 	}
+	if err := r.kubevirt.DeleteIntegrityPods(vm); failOnErr(err) {
+		return err
+	}
+	if err := r.kubevirt.DeleteVerifyPods(vm); failOnErr(err) {
+		return err
+	}
 	if err := r.kubevirt.DeleteSecret(vm); failOnErr(err) {
 		return err
 	}
@@ -627,9 +725,12 @@ func (r *Migration) next(phase string) (next string) {
 
 // Get the itinerary for the migration type.
 func (r *Migration) itinerary() *libitr.Itinerary {
-	if r.Plan.Spec.Warm {
+	switch {
+	case r.Plan.Spec.Test != nil:
+		return &testItinerary
+	case r.Plan.Spec.Warm:
 		return &warmItinerary
-	} else {
+	default:
 		return &coldItinerary
 	}
 }
@@ -641,17 +742,21 @@ func (r *Migration) step(vm *plan.VMStatus) (step string) {
 		step = Initialize
 	case AllocateDisks:
 		step = DiskAllocation
-	case CopyDisks, CopyingPaused, CreateSnapshot, WaitForSnapshot, AddCheckpoint, ConvertOpenstackSnapshot:
+	case CopyDisks, CopyingPaused, CreateSnapshot, WaitForSnapshot, AddCheckpoint:
 		step = DiskTransfer
 	case CreateFinalSnapshot, WaitForFinalSnapshot, AddFinalCheckpoint, Finalize:
 		step = Cutover
-	case CreateGuestConversionPod, ConvertGuest:
+	case CreateGuestConversionPod, ConvertGuest, ConvertOpenstackSnapshot:
 		step = ImageConversion
 	case CopyDisksVirtV2V:
 		step = DiskTransferV2v
 	case CreateVM:
 		step = VMCreation
-	case PreHook, PostHook:
+	case VerifyVM:
+		step = Verification
+	case VerifyDiskIntegrity:
+		step = DiskIntegrity
+	case PreHook, PostHook, PostPowerOffHook, PreCutoverHook, PostConversionHook, FailureHook:
 		step = vm.Phase
 	case StorePowerState, PowerOffSource, WaitForPowerOff:
 		if r.Plan.Spec.Warm {
@@ -719,7 +824,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			break
 		}
 		vm.Phase = r.next(vm.Phase)
-	case PreHook, PostHook:
+	case PreHook, PostHook, PostPowerOffHook, PreCutoverHook, PostConversionHook, FailureHook:
 		runner := HookRunner{Context: r.Context}
 		err = runner.Run(vm)
 		if err != nil {
@@ -727,9 +832,14 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		}
 		if step, found := vm.FindStep(r.step(vm)); found {
 			step.Phase = Running
+			r.hookFailed(vm, step)
 			if step.MarkedCompleted() && step.Error == nil {
 				step.Phase = Completed
-				vm.Phase = r.next(vm.Phase)
+				if vm.Phase == FailureHook {
+					vm.Phase = Completed
+				} else {
+					vm.Phase = r.next(vm.Phase)
+				}
 			}
 		} else {
 			vm.Phase = Completed
@@ -796,7 +906,9 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				}
 			}
 			if vm.Warm != nil {
-				err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dataVolumes, false, r.kubevirt.loadHosts)
+				// a test failover copies the single (initial) snapshot.
+				final := r.Plan.Spec.Test != nil
+				err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dataVolumes, final, r.kubevirt.loadHosts)
 				if err != nil {
 					step.AddError(err.Error())
 					err = nil
@@ -871,6 +983,46 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		step.MarkCompleted()
 		step.Phase = Completed
 		vm.Phase = r.next(vm.Phase)
+	case VerifyVM:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		step.Phase = Running
+		verifier := Verifier{
+			Context:  r.Context,
+			builder:  r.builder,
+			kubevirt: &r.kubevirt,
+		}
+		err = verifier.Run(vm, step)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if step.MarkedCompleted() && step.Error == nil {
+			step.Phase = Completed
+			vm.Phase = r.next(vm.Phase)
+		}
+	case VerifyDiskIntegrity:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		step.Phase = Running
+		integrity := Integrity{Context: r.Context, kubevirt: &r.kubevirt}
+		err = integrity.Run(vm, step)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if step.MarkedCompleted() && step.Error == nil {
+			step.Phase = Completed
+			vm.Phase = r.next(vm.Phase)
+		}
 	case AllocateDisks, CopyDisks:
 		step, found := vm.FindStep(r.step(vm))
 		if !found {
@@ -891,6 +1043,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			err = nil
 			break
 		}
+		r.reflectTransfer(vm, step)
 		if step.MarkedCompleted() && !step.HasError() {
 			if r.Plan.Spec.Warm {
 				now := meta.Now()
@@ -939,6 +1092,10 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			return pvc.Annotations[base.AnnSourceFormat]
 		}
 
+		r.converter.ProgressFn = func(pvc *core.PersistentVolumeClaim, percent float64) {
+			r.updateImageConversionProgress(step, pvc, percent)
+		}
+
 		ready, err := r.converter.ConvertPVCs(pvcs, srcFormatFn, "raw")
 		if err != nil {
 			step.AddError(err.Error())
@@ -947,17 +1104,25 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		}
 
 		if !ready {
+			step.ReflectTasks()
 			r.Log.Info("Conversion isn't ready yet")
 			return nil
 		}
 
+		// Disks not requiring conversion have no progress.
+		for _, task := range step.Tasks {
+			task.Progress.Completed = task.Progress.Total
+			task.MarkCompleted()
+		}
+		step.ReflectTasks()
+
 		if step.MarkedCompleted() && !step.HasError() {
 			step.Phase = Completed
 			vm.Phase = r.next(vm.Phase)
 		}
 	case CopyingPaused:
 		if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
-			vm.Phase = StorePowerState
+			vm.Phase = r.next(AddCheckpoint)
 		} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
 			vm.Phase = CreateSnapshot
 		}
@@ -1083,6 +1248,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		if err != nil {
 			return
 		}
+		r.reflectTransfer(vm, step)
 		if step.MarkedCompleted() {
 			err = r.provider.RemoveSnapshots(vm.Ref, vm.Warm.Precopies, r.kubevirt.loadHosts)
 			if err != nil {
@@ -1129,6 +1295,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		if err != nil {
 			return
 		}
+		r.reflectTransfer(vm, step)
 
 		switch r.Source.Provider.Type() {
 		case v1beta1.Ova, v1beta1.VSphere:
@@ -1193,6 +1360,137 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			})
 
 	} else if vm.Error != nil {
+		if r.failureHook(vm) {
+			return
+		}
+		vm.Phase = Completed
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     Failed,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The VM migration has FAILED.",
+				Durable:  true,
+			})
+	}
+
+	return
+}
+
+// Build the plan-level hook steps.
+func (r *Migration) planHooks() (steps []*plan.Step) {
+	descriptions := map[string]string{
+		PreHook:  "Run plan pre-migration hook.",
+		PostHook: "Run plan post-migration hook.",
+	}
+	for _, name := range []string{PreHook, PostHook} {
+		if _, found := r.Plan.Spec.FindHook(name); !found {
+			continue
+		}
+		steps = append(
+			steps,
+			&plan.Step{
+				Task: plan.Task{
+					Name:        name,
+					Description: descriptions[name],
+					Progress:    libitr.Progress{Total: 1},
+					Phase:       Pending,
+				},
+			})
+	}
+
+	return
+}
+
+// Run a plan-level hook.
+// Returns running=true until the hook has completed and
+// failed=true when the hook failed and the failure policy
+// is to fail the plan.
+func (r *Migration) runPlanHook(name string) (running, failed bool, err error) {
+	step, found := r.Plan.Status.Migration.FindHook(name)
+	if !found {
+		return
+	}
+	ref, found := r.Plan.Spec.FindHook(name)
+	if !found {
+		return
+	}
+	kind := PlanPreHook
+	if name == PostHook {
+		kind = PlanPostHook
+	}
+	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+	if step.MarkedCompleted() {
+		failed = step.Error != nil && !ref.ContinueOnFailure()
+		return
+	}
+	runner := HookRunner{Context: r.Context}
+	err = runner.RunPlan(step, &ref)
+	if err != nil {
+		return
+	}
+	if !step.MarkedCompleted() {
+		step.Phase = Running
+		snapshot.SetCondition(
+			libcnd.Condition{
+				Type:     kind,
+				Status:   True,
+				Reason:   Running,
+				Category: Advisory,
+				Message:  "The plan hook is RUNNING.",
+				Durable:  true,
+			})
+		running = true
+		return
+	}
+	step.Phase = Completed
+	if step.Error == nil {
+		snapshot.SetCondition(
+			libcnd.Condition{
+				Type:     kind,
+				Status:   True,
+				Reason:   Succeeded,
+				Category: Advisory,
+				Message:  "The plan hook has SUCCEEDED.",
+				Durable:  true,
+			})
+		r.Log.Info("Plan hook [SUCCEEDED]", "step", name)
+		return
+	}
+	message := "The plan hook has FAILED."
+	if ref.ContinueOnFailure() {
+		message = "The plan hook has FAILED; the migration continued."
+	} else {
+		failed = true
+	}
+	snapshot.SetCondition(
+		libcnd.Condition{
+			Type:     kind,
+			Status:   True,
+			Reason:   Failed,
+			Category: Warn,
+			Message:  message,
+			Items:    step.Error.Reasons,
+			Durable:  true,
+		})
+	r.Log.Info(
+		"Plan hook [FAILED]",
+		"step",
+		name,
+		"reasons",
+		step.Error.Reasons)
+
+	return
+}
+
+// Fail the VMs that have not completed.
+func (r *Migration) failVMs(reason string) {
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedCompleted() {
+			continue
+		}
+		vm.AddError(reason)
+		vm.MarkCompleted()
 		vm.Phase = Completed
 		vm.SetCondition(
 			libcnd.Condition{
@@ -1203,7 +1501,67 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				Durable:  true,
 			})
 	}
+}
+
+// Handle a failed hook step.
+// When the hook failure policy is to continue, the error is
+// replaced by a (warning) condition on the VM.
+func (r *Migration) hookFailed(vm *plan.VMStatus, step *plan.Step) {
+	if step.Error == nil {
+		return
+	}
+	ref, found := vm.FindHook(step.Name)
+	if !found || !ref.ContinueOnFailure() || vm.Phase == FailureHook {
+		return
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     HookFailed,
+			Status:   True,
+			Category: Warn,
+			Reason:   step.Name,
+			Message:  "The hook failed; the migration continued.",
+			Items:    step.Error.Reasons,
+			Durable:  true,
+		})
+	r.Log.Info(
+		"Hook failed; continuing.",
+		"vm",
+		vm.String(),
+		"step",
+		step.Name,
+		"reasons",
+		step.Error.Reasons)
+	step.Error = nil
+	step.MarkCompleted()
+}
 
+// Run the failure hook (once) for a failed VM.
+// The step is added to the pipeline as needed.
+// Returns true while the hook has not completed.
+func (r *Migration) failureHook(vm *plan.VMStatus) (running bool) {
+	predicate := &Predicate{vm: &vm.VM, context: r.Context}
+	allowed, err := predicate.Evaluate(HasFailureHook)
+	if err != nil || !allowed {
+		return
+	}
+	step, found := vm.FindStep(FailureHook)
+	if !found {
+		step = &plan.Step{
+			Task: plan.Task{
+				Name:        FailureHook,
+				Description: "Run failure hook.",
+				Progress:    libitr.Progress{Total: 1},
+				Phase:       Pending,
+			},
+		}
+		vm.Pipeline = append(vm.Pipeline, step)
+	}
+	if step.MarkedCompleted() {
+		return
+	}
+	vm.Phase = FailureHook
+	running = true
 	return
 }
 
@@ -1266,8 +1624,8 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 				task_name = DiskTransferV2v
 				task_description = "Copy disks."
 			case ConvertOpenstackSnapshot:
-				task_name = ConvertOpenstackSnapshot
-				task_description = "Convert OpenStack snapshot."
+				task_name = ImageConversion
+				task_description = "Convert disk images."
 			default:
 				err = liberr.New(fmt.Sprintf("Unknown step '%s'. Not implemented.", step.Name))
 				return
@@ -1335,6 +1693,39 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						Phase:       Pending,
 					},
 				})
+		case PostPowerOffHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PostPowerOffHook,
+						Description: "Run hook after the source VM is powered off.",
+						Progress:    libitr.Progress{Total: 1},
+						Phase:       Pending,
+					},
+				})
+		case PreCutoverHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PreCutoverHook,
+						Description: "Run pre-cutover hook.",
+						Progress:    libitr.Progress{Total: 1},
+						Phase:       Pending,
+					},
+				})
+		case PostConversionHook:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        PostConversionHook,
+						Description: "Run hook before the VM is first booted.",
+						Progress:    libitr.Progress{Total: 1},
+						Phase:       Pending,
+					},
+				})
 		case CreateVM:
 			pipeline = append(
 				pipeline,
@@ -1346,6 +1737,28 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case VerifyDiskIntegrity:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        DiskIntegrity,
+						Description: "Verify the disks match the source.",
+						Phase:       Pending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		case VerifyVM:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        Verification,
+						Description: "Verify the VM boots and passes health checks.",
+						Phase:       Pending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		}
 		next, done, _ := r.itinerary().Next(step.Name)
 		if !done {
@@ -1378,6 +1791,13 @@ func (r *Migration) end() (completed bool, err error) {
 			succeeded++
 		}
 	}
+	running, hookFailed, err := r.runPlanHook(PostHook)
+	if err != nil || running {
+		return
+	}
+	if hookFailed {
+		failed++
+	}
 	go r.provider.Finalize(r.Plan.Status.Migration.VMs, r.Migration.Name)
 	r.Plan.Status.Migration.MarkCompleted()
 	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
@@ -1462,13 +1882,114 @@ func (r *Migration) ensureGuestConversionPod(vm *plan.VMStatus) (ready bool, err
 	return
 }
 
+// Reflect the progress of the disk transfer step in the
+// transfer progress (bytes, rate and ETA) of the step and
+// the VM. The CDI and populator transfers update their tasks
+// with the bytes they report, virt-v2v only reports percents.
+func (r *Migration) reflectTransfer(vm *plan.VMStatus, step *plan.Step) {
+	now := time.Now()
+	switch step.Name {
+	case DiskTransferV2v:
+		for _, task := range step.Tasks {
+			task.ReflectTransfer(now)
+		}
+		fallthrough
+	case DiskTransfer, Cutover:
+		step.ReflectTransfers(now)
+		vm.Transfer = step.Transfer.DeepCopy()
+	}
+}
+
+// Seed the transfer progress of the VMs not transferring yet
+// with the size of their disks found in the inventory when the
+// tasks were built, the plan progress counts the queued VMs.
+func (r *Migration) seedTransfers() {
+	now := time.Now()
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.Transfer != nil {
+			continue
+		}
+		for _, name := range []string{DiskTransfer, DiskTransferV2v} {
+			if step, found := vm.FindStep(name); found {
+				step.ReflectTransfers(now)
+				vm.Transfer = step.Transfer.DeepCopy()
+				break
+			}
+		}
+	}
+}
+
 func (r *Migration) setTaskCompleted(task *plan.Task) {
 	task.Phase = Completed
 	task.Reason = TransferCompleted
 	task.Progress.Completed = task.Progress.Total
+	task.CompleteTransfer(time.Now())
 	task.MarkCompleted()
 }
 
+// Size (bytes) of the DataVolume the CDI progress is a percent of.
+func dataVolumeSize(dv *cdi.DataVolume) (size int64) {
+	var requests core.ResourceList
+	switch {
+	case dv.Spec.Storage != nil:
+		requests = dv.Spec.Storage.Resources.Requests
+	case dv.Spec.PVC != nil:
+		requests = dv.Spec.PVC.Resources.Requests
+	}
+	if quantity, found := requests[core.ResourceStorage]; found {
+		size = quantity.Value()
+	}
+	return
+}
+
+// Update the tasks of the disks cloned by the storage array.
+// The clones are polled, and the PVs and PVCs created for the
+// completed clones, until the PVCs of all the clones exist.
+// The task of a disk completes when its PVC is bound.
+// Returns the number of disks still being cloned.
+func (r *Migration) updateOffloadProgress(vm *plan.VMStatus, step *plan.Step, pvcs []*core.PersistentVolumeClaim) (cloning int, err error) {
+	if vm.Offload == nil {
+		return
+	}
+	claims := map[string]*core.PersistentVolumeClaim{}
+	for _, pvc := range pvcs {
+		if _, ok := pvc.Annotations[base.AnnOffload]; ok {
+			claims[r.builder.ResolvePersistentVolumeClaimIdentifier(pvc)] = pvc
+		}
+	}
+	for _, disk := range vm.Offload.Disks {
+		if _, found := claims[disk.File]; !found && disk.Phase != plan.CloneFailed {
+			err = r.kubevirt.createLunDisks(vm.Ref)
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+	now := time.Now()
+	for _, disk := range vm.Offload.Disks {
+		task, found := step.FindTask(disk.File)
+		if !found || task.MarkedCompleted() {
+			continue
+		}
+		pvc, found := claims[disk.File]
+		switch {
+		case disk.Phase == plan.CloneFailed:
+			task.AddError(fmt.Sprintf("The storage array failed to clone the disk: %s", disk.Error))
+			task.MarkCompleted()
+		case found && pvc.Status.Phase == core.ClaimBound:
+			r.setTaskCompleted(task)
+		default:
+			cloning++
+			task.Phase = Running
+			task.MarkStarted()
+			task.Progress.Completed = disk.Completed / 0x100000
+			task.UpdateTransfer(disk.Completed, disk.Capacity, now)
+		}
+	}
+	return
+}
+
 // Update the progress of the appropriate disk copy step. (DiskTransfer, Cutover)
 func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
 	var pendingReason string
@@ -1480,16 +2001,29 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 	if err != nil {
 		return
 	}
+	claims, err := r.kubevirt.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	cloning, err := r.updateOffloadProgress(vm, step, claims)
+	if err != nil {
+		return
+	}
+	running += cloning
 	if len(dvs) == 0 {
-		pvcs, err = r.kubevirt.getPVCs(vm.Ref)
-		if err != nil {
-			return
-		}
+		pvcs = claims
 		for _, pvc := range pvcs {
 			if _, ok := pvc.Annotations["lun"]; ok {
 				// skip LUNs
 				continue
 			}
+			if _, ok := pvc.Annotations[base.AnnOffload]; ok {
+				// cloned by the storage array
+				if pvc.Status.Phase == core.ClaimBound {
+					completed++
+				}
+				continue
+			}
 			var task *plan.Task
 			name := r.builder.ResolvePersistentVolumeClaimIdentifier(pvc)
 			found := false
@@ -1543,6 +2077,9 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 				pct := dv.PercentComplete()
 				transferred := pct * float64(task.Progress.Total)
 				task.Progress.Completed = int64(transferred)
+				if size := dataVolumeSize(dv.DataVolume); size > 0 {
+					task.UpdateTransfer(int64(pct*float64(size)), size, time.Now())
+				}
 
 				// The importer pod is recreated by CDI if it is removed for some
 				// reason while the import is in progress, so we can assume that if
@@ -1662,6 +2199,27 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 	return nil
 }
 
+// Update the task of the disk converted by the image-converter job.
+func (r *Migration) updateImageConversionProgress(step *plan.Step, pvc *core.PersistentVolumeClaim, percent float64) {
+	taskName, err := r.builder.GetPopulatorTaskName(pvc)
+	if err != nil {
+		r.Log.Error(err, "Couldn't find the task of the PVC.", "pvc", pvc.Name)
+		return
+	}
+	task, found := step.FindTask(taskName)
+	if !found {
+		r.Log.Info("Ignoring progress update", "task", taskName, "step", step.Name)
+		return
+	}
+	task.MarkStarted()
+	task.Phase = Running
+	task.Progress.Completed = int64(float64(task.Progress.Total) * percent / 100)
+	if percent >= 100 {
+		task.Phase = Completed
+		task.MarkCompleted()
+	}
+}
+
 func (r *Migration) updateConversionProgressEl9(pod *core.Pod, step *plan.Step) (err error) {
 	var diskRegex = regexp.MustCompile(`v2v_disk_transfers\{disk_id="(\d+)"\} (\d{1,3}\.?\d*)`)
 	url := fmt.Sprintf("http://%s:2112/metrics", pod.Status.PodIP)
@@ -1745,11 +2303,19 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 		return
 	}
 
+	_, err = r.updateOffloadProgress(vm, step, pvcs)
+	if err != nil {
+		return
+	}
 	for _, pvc := range pvcs {
 		if _, ok := pvc.Annotations["lun"]; ok {
 			// skip LUNs
 			continue
 		}
+		if _, ok := pvc.Annotations[base.AnnOffload]; ok {
+			// cloned by the storage array
+			continue
+		}
 		var task *plan.Task
 		var taskName string
 		taskName, err = r.builder.GetPopulatorTaskName(pvc)
@@ -1763,10 +2329,7 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 		}
 
 		if pvc.Status.Phase == core.ClaimBound {
-			task.Phase = Completed
-			task.Reason = TransferCompleted
-			task.Progress.Completed = task.Progress.Total
-			task.MarkCompleted()
+			r.setTaskCompleted(task)
 			continue
 		}
 
@@ -1786,6 +2349,10 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 			}
 		}
 		task.Progress.Completed = newProgress
+		pvcSize := pvc.Spec.Resources.Requests[core.ResourceStorage]
+		if size := pvcSize.Value(); size > 0 {
+			task.UpdateTransfer(transferredBytes, size, time.Now())
+		}
 	}
 
 	step.ReflectTasks()
@@ -1853,6 +2420,18 @@ func (r *Predicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		_, allowed = r.vm.FindHook(PreHook)
 	case HasPostHook:
 		_, allowed = r.vm.FindHook(PostHook)
+	case HasPostPowerOffHook:
+		_, allowed = r.vm.FindHook(PostPowerOffHook)
+	case HasPreCutoverHook:
+		_, allowed = r.vm.FindHook(PreCutoverHook)
+	case HasPostConversionHook:
+		_, allowed = r.vm.FindHook(PostConversionHook)
+	case HasFailureHook:
+		_, allowed = r.vm.FindHook(FailureHook)
+	case RequiresVerification:
+		allowed = r.context.Plan.Spec.Verify != nil
+	case RequiresIntegrityCheck:
+		allowed = r.context.Plan.Spec.VerifyIntegrity
 	case RequiresConversion:
 		allowed = r.context.Source.Provider.RequiresConversion()
 	case CDIDiskCopy:
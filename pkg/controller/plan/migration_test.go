@@ -0,0 +1,158 @@
+package plan
+
+import (
+	v1beta1 "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("Migration transfer progress", func() {
+	transferStep := func(name string) *planapi.Step {
+		return &planapi.Step{
+			Task: planapi.Task{Name: name},
+			Tasks: []*planapi.Task{
+				{Name: "disk1", Progress: libitr.Progress{Total: 1000}},
+				{Name: "disk2", Progress: libitr.Progress{Total: 1000}},
+			},
+		}
+	}
+
+	ginkgo.It("should seed the VMs not transferring yet", func() {
+		queued := &planapi.VMStatus{Pipeline: []*planapi.Step{transferStep(DiskTransfer)}}
+		other := &planapi.VMStatus{Pipeline: []*planapi.Step{{Task: planapi.Task{Name: Initialize}}}}
+		plan := &v1beta1.Plan{}
+		plan.Status.Migration.VMs = []*planapi.VMStatus{queued, other}
+		migration := &Migration{Context: &plancontext.Context{Plan: plan}}
+
+		migration.seedTransfers()
+		gomega.Expect(queued.Transfer.BytesTotal).To(gomega.Equal(int64(2000 * planapi.MB)))
+		gomega.Expect(queued.Transfer.BytesCompleted).To(gomega.BeZero())
+		gomega.Expect(other.Transfer).To(gomega.BeNil())
+	})
+
+	ginkgo.It("should reflect the virt-v2v progress", func() {
+		step := transferStep(DiskTransferV2v)
+		step.Tasks[0].Progress.Completed = 500
+		vm := &planapi.VMStatus{}
+		migration := &Migration{}
+		migration.reflectTransfer(vm, step)
+		gomega.Expect(vm.Transfer.BytesCompleted).To(gomega.Equal(int64(500 * planapi.MB)))
+		gomega.Expect(vm.Transfer.BytesTotal).To(gomega.Equal(int64(2000 * planapi.MB)))
+	})
+
+	ginkgo.It("should not reflect other steps", func() {
+		vm := &planapi.VMStatus{}
+		migration := &Migration{}
+		migration.reflectTransfer(vm, transferStep(DiskAllocation))
+		gomega.Expect(vm.Transfer).To(gomega.BeNil())
+	})
+})
+
+var _ = ginkgo.Describe("Migration offload progress", func() {
+	var migration *Migration
+	var builder *offloadBuilder
+	var vm *planapi.VMStatus
+	var step *planapi.Step
+
+	ginkgo.BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		_ = core.AddToScheme(scheme)
+		client := fakeClient.NewClientBuilder().WithScheme(scheme).Build()
+		plan := &v1beta1.Plan{}
+		plan.Spec.TargetNamespace = "test"
+		ctx := &plancontext.Context{
+			Plan:      plan,
+			Migration: createMigration(),
+			Client:    client,
+		}
+		ctx.Destination.Client = client
+		builder = &offloadBuilder{}
+		migration = &Migration{
+			Context:  ctx,
+			builder:  builder,
+			kubevirt: KubeVirt{Context: ctx, Builder: builder},
+		}
+		vm = &planapi.VMStatus{}
+		vm.Ref = ref.Ref{ID: "vm1"}
+		vm.Offload = &planapi.Offload{
+			Driver: "fake",
+			Disks: []planapi.OffloadedDisk{
+				{File: "disk1", Capacity: 1000 * planapi.MB, Completed: 500 * planapi.MB, Phase: planapi.CloneRunning},
+				{File: "disk2", Capacity: 1000 * planapi.MB, Completed: 1000 * planapi.MB, Phase: planapi.CloneCompleted},
+				{File: "disk3", Capacity: 1000 * planapi.MB, Phase: planapi.CloneFailed, Error: "extent offline"},
+			},
+		}
+		step = &planapi.Step{Task: planapi.Task{Name: DiskTransfer}}
+		for _, disk := range vm.Offload.Disks {
+			step.Tasks = append(step.Tasks, &planapi.Task{Name: disk.File, Progress: libitr.Progress{Total: 1000}})
+		}
+	})
+
+	claim := func(file string, phase core.PersistentVolumeClaimPhase) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        file,
+				Annotations: map[string]string{base.AnnOffload: "fake", base.AnnDiskSource: file},
+			},
+			Status: core.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	ginkgo.It("should report the progress of the clones", func() {
+		pvcs := []*core.PersistentVolumeClaim{claim("disk2", core.ClaimBound)}
+		cloning, err := migration.updateOffloadProgress(vm, step, pvcs)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(cloning).To(gomega.Equal(1))
+		gomega.Expect(builder.polls).To(gomega.Equal(1))
+		running, completed, failed := step.Tasks[0], step.Tasks[1], step.Tasks[2]
+		gomega.Expect(running.MarkedCompleted()).To(gomega.BeFalse())
+		gomega.Expect(running.Progress.Completed).To(gomega.Equal(int64(500)))
+		gomega.Expect(running.Transfer.BytesCompleted).To(gomega.Equal(int64(500 * planapi.MB)))
+		gomega.Expect(completed.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(completed.Error).To(gomega.BeNil())
+		gomega.Expect(failed.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(failed.Error.Reasons[0]).To(gomega.ContainSubstring("extent offline"))
+	})
+
+	ginkgo.It("should wait for the PVCs of the clones to be bound", func() {
+		vm.Offload.Disks[0].Phase = planapi.CloneCompleted
+		pvcs := []*core.PersistentVolumeClaim{
+			claim("disk1", core.ClaimPending),
+			claim("disk2", core.ClaimBound),
+		}
+		cloning, err := migration.updateOffloadProgress(vm, step, pvcs)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		gomega.Expect(cloning).To(gomega.Equal(1))
+		gomega.Expect(builder.polls).To(gomega.BeZero())
+		gomega.Expect(step.Tasks[0].MarkedCompleted()).To(gomega.BeFalse())
+	})
+})
+
+// Builder of the disks cloned by the storage array.
+type offloadBuilder struct {
+	base.Builder
+	// Number of times the clones were polled.
+	polls int
+}
+
+func (r *offloadBuilder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return pvc.Annotations[base.AnnDiskSource]
+}
+
+func (r *offloadBuilder) LunPersistentVolumeClaims(ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	r.polls++
+	return
+}
+
+func (r *offloadBuilder) LunPersistentVolumes(ref.Ref) (pvs []core.PersistentVolume, err error) {
+	return
+}
@@ -6,14 +6,21 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/url"
 	"path"
 	"strconv"
+	"strings"
+	"unicode"
 
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/vsphere/offload"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	ocp "github.com/konveyor/forklift-controller/pkg/lib/client/openshift"
@@ -25,6 +32,7 @@ import (
 	core "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
@@ -38,6 +46,11 @@ const (
 	WarmMigrationNotReady        = "WarmMigrationNotReady"
 	NamespaceNotValid            = "NamespaceNotValid"
 	TransferNetNotValid          = "TransferNetworkNotValid"
+	TransferRelayNotValid        = "TransferRelayNotValid"
+	TransferRelayNotSupported    = "TransferRelayNotSupported"
+	BandwidthLimitNotValid       = "BandwidthLimitNotValid"
+	BandwidthLimitNotSupported   = "BandwidthLimitNotSupported"
+	StorageOffloadNotSupported   = "StorageOffloadNotSupported"
 	NetRefNotValid               = "NetworkMapRefNotValid"
 	NetMapNotReady               = "NetworkMapNotReady"
 	DsMapNotReady                = "StorageMapNotReady"
@@ -48,14 +61,29 @@ const (
 	VMNetworksNotMapped          = "VMNetworksNotMapped"
 	VMStorageNotMapped           = "VMStorageNotMapped"
 	VMStorageNotSupported        = "VMStorageNotSupported"
+	VMStorageNotOffloaded        = "VMStorageNotOffloaded"
 	VMMultiplePodNetworkMappings = "VMMultiplePodNetworkMappings"
 	VMMissingGuestIPs            = "VMMissingGuestIPs"
+	VMOutOfScope                 = "VMOutOfScope"
+	VMCriticalConcerns           = "VMCriticalConcerns"
+	VMDestinationNotValidated    = "VMDestinationNotValidated"
 	HostNotReady                 = "HostNotReady"
 	DuplicateVM                  = "DuplicateVM"
 	NameNotValid                 = "TargetNameNotValid"
 	HookNotValid                 = "HookNotValid"
 	HookNotReady                 = "HookNotReady"
 	HookStepNotValid             = "HookStepNotValid"
+	HookFailed                   = "HookFailed"
+	PlanPreHook                  = "PlanPreHook"
+	PlanPostHook                 = "PlanPostHook"
+	VMVerificationFailed         = "VMVerificationFailed"
+	VMIntegrityNotVerified       = "VMIntegrityNotVerified"
+	VMOffloadNotVerified         = "VMOffloadNotVerified"
+	IntegrityNotSupported        = "IntegrityNotSupported"
+	VerifyNotValid               = "VerifyNotValid"
+	TestNotValid                 = "TestNotValid"
+	Rehearsed                    = "Rehearsed"
+	RehearsalCleanedUp           = "RehearsalCleanedUp"
 	Executing                    = "Executing"
 	Succeeded                    = "Succeeded"
 	Failed                       = "Failed"
@@ -93,6 +121,7 @@ const (
 	UserRequested     = "UserRequested"
 	InMaintenanceMode = "InMaintenanceMode"
 	MissingGuestInfo  = "MissingGuestInformation"
+	NotAcknowledged   = "NotAcknowledged"
 )
 
 // Statuses
@@ -146,10 +175,29 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err := r.validateTransferRelay(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateStorageOffload(plan); err != nil {
+		return err
+	}
+	if err := r.validateBandwidthLimit(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateHooks(plan); err != nil {
 		return err
 	}
 
+	if err := r.validateVerify(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateTest(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateVddkImage(plan); err != nil {
 		return err
 	}
@@ -211,6 +259,199 @@ func (r *Reconciler) ensureSecretForProvider(plan *api.Plan) error {
 	return nil
 }
 
+// Validate the post-migration verification.
+func (r *Reconciler) validateVerify(plan *api.Plan) (err error) {
+	if plan.Spec.VerifyIntegrity && plan.Spec.Warm {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     IntegrityNotSupported,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Warn,
+			Message:  "The disk integrity is not verified by warm migrations.",
+		})
+	} else if plan.Spec.VerifyIntegrity {
+		// virt-v2v converts the disks while copying them.
+		el9, el9Err := plan.VSphereUsesEl9VirtV2v()
+		if el9Err == nil && el9 {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     IntegrityNotSupported,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Critical,
+				Message:  "The disk integrity cannot be verified when the disks are copied and converted by virt-v2v.",
+			})
+		}
+	}
+	verify := plan.Spec.Verify
+	if verify == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     VerifyNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The verification is not valid.",
+		Items:    []string{},
+	}
+	if verify.Deadline < 0 {
+		notValid.Items = append(notValid.Items, "deadline: must not be negative.")
+	}
+	if verify.Probe != nil && (verify.Probe.Port < 1 || verify.Probe.Port > 65535) {
+		notValid.Items = append(notValid.Items, "probe: port must be 1-65535.")
+	}
+	if verify.Probe != nil && verify.Probe.Path != "" && !validProbePath(verify.Probe.Path) {
+		notValid.Items = append(notValid.Items, "probe: path must be absolute without control or quote characters.")
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+// The probe (HTTP) path is valid.
+// Absolute without control or quote characters.
+func validProbePath(probePath string) bool {
+	if !strings.HasPrefix(probePath, "/") {
+		return false
+	}
+	for _, r := range probePath {
+		if unicode.IsControl(r) || strings.ContainsRune("'\"`\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate the test failover.
+func (r *Reconciler) validateTest(plan *api.Plan) (err error) {
+	test := plan.Spec.Test
+	if test == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     TestNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The test failover is not valid.",
+		Items:    []string{},
+	}
+	defer func() {
+		if len(notValid.Items) > 0 {
+			plan.Status.SetCondition(notValid)
+		}
+	}()
+	if plan.Spec.Warm {
+		notValid.Items = append(notValid.Items, "warm: not supported by test failover.")
+	}
+	switch {
+	case test.Namespace == "":
+		notValid.Items = append(notValid.Items, "namespace: not set.")
+	case len(k8svalidation.IsDNS1123Label(test.Namespace)) > 0:
+		notValid.Items = append(notValid.Items, "namespace: not a valid DNS label.")
+	case test.Namespace == plan.Spec.TargetNamespace:
+		notValid.Items = append(notValid.Items, "namespace: must not be the target namespace.")
+	}
+	if plan.Status.Migration.ActiveSnapshot().HasCondition(Executing) {
+		notValid.Items = append(notValid.Items, "A migration is executing.")
+	}
+	if provider := plan.Referenced.Provider.Source; provider != nil {
+		switch provider.Type() {
+		case api.VSphere, api.OVirt:
+			pAdapter, aErr := adapter.New(provider)
+			if aErr != nil {
+				err = aErr
+				return
+			}
+			validator, vErr := pAdapter.Validator(plan)
+			if vErr != nil {
+				err = vErr
+				return
+			}
+			if !validator.WarmMigration() {
+				notValid.Items = append(notValid.Items, "Snapshot copy from the source provider is not supported.")
+			}
+		default:
+			notValid.Items = append(notValid.Items, "Test failover from the source provider is not supported.")
+		}
+	}
+	if test.Network == nil {
+		notValid.Items = append(notValid.Items, "network: not set.")
+		return
+	}
+	ref := *test.Network
+	if !libref.RefSet(&ref) {
+		notValid.Items = append(notValid.Items, "network: not set.")
+		return
+	}
+	mp := &api.NetworkMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		mp)
+	if k8serr.IsNotFound(err) {
+		err = nil
+		notValid.Items = append(notValid.Items, "network: not found.")
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if !mp.Status.HasCondition(libcnd.Ready) {
+		notValid.Items = append(notValid.Items, "network: does not have Ready condition.")
+		return
+	}
+	notIsolated := r.notIsolated(plan, mp)
+	if len(notIsolated) > 0 {
+		notValid.Items = append(
+			notValid.Items,
+			"network: not isolated: "+strings.Join(notIsolated, ", "))
+		return
+	}
+
+	plan.Referenced.Map.TestNetwork = mp
+
+	return
+}
+
+// Network types.
+const (
+	Pod    = "pod"
+	Multus = "multus"
+)
+
+// Test network map destinations that are not isolated.
+// The pod network and the networks the VMs are migrated
+// to are shared with the production VMs.
+func (r *Reconciler) notIsolated(plan *api.Plan, mp *api.NetworkMap) (notIsolated []string) {
+	production := make(map[string]bool)
+	if plan.Referenced.Map.Network != nil {
+		for _, pair := range plan.Referenced.Map.Network.Spec.Map {
+			if pair.Destination.Type == Multus {
+				production[path.Join(pair.Destination.Namespace, pair.Destination.Name)] = true
+			}
+		}
+	}
+	for _, pair := range mp.Spec.Map {
+		switch pair.Destination.Type {
+		case Multus:
+			name := path.Join(pair.Destination.Namespace, pair.Destination.Name)
+			if production[name] {
+				notIsolated = append(notIsolated, name)
+			}
+		default:
+			notIsolated = append(notIsolated, pair.Destination.Type)
+		}
+	}
+	return
+}
+
 // Validate that warm migration is supported from the source provider.
 func (r *Reconciler) validateWarmMigration(plan *api.Plan) (err error) {
 	if !plan.Spec.Warm {
@@ -417,6 +658,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has unsupported storage. Migration of Direct LUN/FC from oVirt is supported as from version 4.5.2.1",
 		Items:    []string{},
 	}
+	notOffloaded := libcnd.Condition{
+		Type:     VMStorageNotOffloaded,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM has disks on datastores not backed by devices the storage array may clone, the disks are copied.",
+		Items:    []string{},
+	}
 	maintenanceMode := libcnd.Condition{
 		Type:     HostNotReady,
 		Status:   True,
@@ -441,7 +690,42 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Guest information on vNICs is missing, cannot preserve static IPs. Make sure VMware tools are installed and the VM is running.",
 		Items:    []string{},
 	}
+	outOfScope := libcnd.Condition{
+		Type:     VMOutOfScope,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "VM is not within the source provider scope.",
+		Items:    []string{},
+	}
+	criticalConcerns := libcnd.Condition{
+		Type:     VMCriticalConcerns,
+		Status:   True,
+		Reason:   NotAcknowledged,
+		Category: Critical,
+		Message:  "VM has critical concerns that have not been acknowledged.",
+		Items:    []string{},
+	}
 
+	notValidated := libcnd.Condition{
+		Type:     VMDestinationNotValidated,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Warn,
+		Message:  "VMs could not be validated against the destination cluster.",
+		Items:    []string{},
+	}
+
+	destination, err := r.destinationValidation(plan)
+	if err != nil {
+		return err
+	}
+	if destination != nil && destination.Failed {
+		plan.Status.SetCondition(notValidated)
+		destination = nil
+	}
+
+	plan.Status.WaivedConcerns = nil
 	setOf := map[string]bool{}
 	//
 	// Referenced VMs.
@@ -494,6 +778,32 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		if err != nil {
 			return err
 		}
+		ok, err := validator.InScope(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			outOfScope.Items = append(outOfScope.Items, ref.String())
+			continue
+		}
+		concerns, err := validator.Concerns(*ref)
+		if err != nil {
+			return err
+		}
+		if destination != nil {
+			if destination.NotValidated[ref.String()] {
+				notValidated.Items = append(notValidated.Items, ref.String())
+			} else {
+				concerns = mergeConcerns(concerns, destination.Concerns[ref.String()])
+			}
+		}
+		blocking, waived := r.evaluateConcerns(&plan.Spec.VMs[i], concerns)
+		for _, id := range blocking {
+			criticalConcerns.Items = append(
+				criticalConcerns.Items,
+				ref.String()+"concern:"+id)
+		}
+		plan.Status.WaivedConcerns = append(plan.Status.WaivedConcerns, waived...)
 		if plan.Referenced.Map.Network != nil {
 			ok, err := validator.NetworksMapped(*ref)
 			if err != nil {
@@ -525,8 +835,15 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			if !ok {
 				unsupportedStorage.Items = append(unsupportedStorage.Items, ref.String())
 			}
+			ok, err = validator.StorageOffload(*ref)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				notOffloaded.Items = append(notOffloaded.Items, ref.String())
+			}
 		}
-		ok, err := validator.MaintenanceMode(*ref)
+		ok, err = validator.MaintenanceMode(*ref)
 		if err != nil {
 			return err
 		}
@@ -591,6 +908,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(unsupportedStorage.Items) > 0 {
 		plan.Status.SetCondition(unsupportedStorage)
 	}
+	if len(notOffloaded.Items) > 0 {
+		plan.Status.SetCondition(notOffloaded)
+	}
 	if len(maintenanceMode.Items) > 0 {
 		plan.Status.SetCondition(maintenanceMode)
 	}
@@ -600,10 +920,77 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(missingStaticIPs.Items) > 0 {
 		plan.Status.SetCondition(missingStaticIPs)
 	}
+	if len(criticalConcerns.Items) > 0 {
+		plan.Status.SetCondition(criticalConcerns)
+	}
+	if len(notValidated.Items) > 0 {
+		plan.Status.SetCondition(notValidated)
+	}
+	if len(outOfScope.Items) > 0 {
+		plan.Status.SetCondition(outOfScope)
+	}
 
 	return nil
 }
 
+// Evaluate the concerns reported for a VM.
+// Returns the IDs of the critical concerns that have not been
+// acknowledged and the acknowledged (waived) concerns.
+func (r *Reconciler) evaluateConcerns(
+	vm *planapi.VM,
+	concerns []planbase.Concern) (blocking []string, waived []planapi.WaivedConcern) {
+	//
+	for _, concern := range concerns {
+		if vm.Acknowledged(concern.ID) {
+			waived = append(
+				waived,
+				planapi.WaivedConcern{
+					VM:       vm.Ref,
+					ID:       concern.ID,
+					Label:    concern.Label,
+					Category: concern.Category,
+				})
+			continue
+		}
+		if concern.Category == model.ConcernCritical {
+			blocking = append(blocking, concern.ID)
+		}
+	}
+
+	return
+}
+
+// Validate the storage offload of the source provider.
+// The disks are copied when the feature is disabled or
+// the driver is not registered.
+func (r *Reconciler) validateStorageOffload(plan *api.Plan) (err error) {
+	provider := plan.Referenced.Provider.Source
+	if provider == nil || provider.Type() != api.VSphere || !provider.UsesStorageOffload() {
+		return
+	}
+	driver := provider.Spec.Settings[api.StorageOffload]
+	switch {
+	case !settings.Settings.Features.VsphereStorageOffload:
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     StorageOffloadNotSupported,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Warn,
+			Message:  "The storage offload feature is not enabled, the disks are copied.",
+		})
+	case !offload.Registered(driver):
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     StorageOffloadNotSupported,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Warn,
+			Message:  "The storage offload driver is not registered, the disks are copied.",
+			Items:    []string{driver},
+		})
+	}
+	return
+}
+
 // Validate transfer network selection.
 func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	if plan.Spec.TransferNetwork == nil {
@@ -634,6 +1021,154 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the transfer relay.
+// The secret is looked up when the destination is the host cluster.
+// Plans with disks not transferred by the oVirt or OpenStack
+// populators are warned that those disks bypass the relay.
+func (r *Reconciler) validateTransferRelay(plan *api.Plan) (err error) {
+	relay := plan.Spec.TransferRelay
+	if relay == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     TransferRelayNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Transfer relay is not valid.",
+		Items:    []string{},
+	}
+	defer func() {
+		if len(notValid.Items) > 0 {
+			plan.Status.SetCondition(notValid)
+		}
+	}()
+	if u, pErr := url.Parse(relay.URL); pErr != nil || u.Scheme != "https" || u.Host == "" {
+		notValid.Items = append(notValid.Items, "url: must be an https URL.")
+	}
+	if relay.Secret == "" {
+		notValid.Items = append(notValid.Items, "secret: not set.")
+	} else if destination := plan.Referenced.Provider.Destination; destination != nil && destination.IsHost() {
+		secret := &core.Secret{}
+		key := client.ObjectKey{Namespace: plan.Spec.TargetNamespace, Name: relay.Secret}
+		err = r.Get(context.TODO(), key, secret)
+		if k8serr.IsNotFound(err) {
+			err = nil
+			notValid.Items = append(notValid.Items, "secret: not found in the target namespace.")
+		} else if err != nil {
+			err = liberr.Wrap(err)
+			return
+		} else {
+			for _, name := range []string{"tls.crt", "tls.key", "ca.crt"} {
+				if _, found := secret.Data[name]; !found {
+					notValid.Items = append(notValid.Items, fmt.Sprintf("secret: %s not found.", name))
+				}
+			}
+		}
+	}
+	if provider := plan.Referenced.Provider.Source; provider != nil {
+		destination := plan.Referenced.Provider.Destination
+		host := destination != nil && destination.IsHost()
+		relayed := false
+		switch provider.Type() {
+		case api.OpenStack:
+			relayed = true
+		case api.OVirt:
+			relayed = !plan.SnapshotCopy() && host
+		}
+		// CDI importers, the vSphere populator and virt-v2v
+		// read the source directly, bypassing the relay.
+		if !relayed {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     TransferRelayNotSupported,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Warn,
+				Message:  "The transfer relay is used only by the oVirt and OpenStack volume populators; the disks of this plan are transferred directly.",
+			})
+		}
+	}
+
+	return
+}
+
+// Validate the bandwidth limit.
+// The volume populators and virt-v2v limit the transfer rate,
+// plans with disks copied by CDI are rejected.
+func (r *Reconciler) validateBandwidthLimit(plan *api.Plan) (err error) {
+	limit := plan.Spec.BandwidthLimit
+	if limit == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     BandwidthLimitNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The bandwidth limit is not valid.",
+		Items:    []string{},
+	}
+	negative := func(field string, quantity *resource.Quantity) {
+		if quantity != nil && quantity.Sign() < 0 {
+			notValid.Items = append(notValid.Items, field+": must not be negative.")
+		}
+	}
+	negative("total", limit.Total)
+	negative("perVM", limit.PerVM)
+	for i := range limit.Schedule {
+		window := &limit.Schedule[i]
+		negative(fmt.Sprintf("schedule[%d].total", i), window.Total)
+		negative(fmt.Sprintf("schedule[%d].perVM", i), window.PerVM)
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	destination := plan.Referenced.Provider.Destination
+	host := destination != nil && destination.IsHost()
+	limited := false
+	switch provider.Type() {
+	case api.OpenStack:
+		limited = true
+	case api.OVirt:
+		limited = !plan.SnapshotCopy() && host
+	case api.VSphere, api.Ova:
+		el9, _ := plan.VSphereUsesEl9VirtV2v()
+		limited = el9 || provider.UsesVolumePopulator() && !plan.SnapshotCopy() && host
+	}
+	// CDI importers have no rate limit.
+	if !limited {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     BandwidthLimitNotSupported,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "The bandwidth limit is applied only by the volume populators and virt-v2v; the disks of this plan would be copied by CDI without a limit.",
+		})
+	}
+
+	return
+}
+
+// Determine whether the hook step is valid for the plan.
+// The pre-cutover step is valid only for warm migrations.
+func (r *Reconciler) validHookStep(plan *api.Plan, step string) (valid bool) {
+	_, valid = HookSteps[step]
+	if step == PreCutoverHook {
+		valid = plan.Spec.Warm
+	}
+	return
+}
+
+// Determine whether the plan-level hook step is valid.
+// Plan hooks run before the first VM and after the last VM.
+func (r *Reconciler) validPlanHookStep(step string) bool {
+	return step == PreHook || step == PostHook
+}
+
 // Validate referenced hooks.
 func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 	notSet := libcnd.Condition{
@@ -668,66 +1203,107 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 		Message:  "Hook step not valid.",
 		Items:    []string{},
 	}
+	type owned struct {
+		// Description of the owner.
+		owner string
+		// Plan-level hook.
+		planScoped bool
+		// Reference.
+		ref planapi.HookRef
+	}
+	refs := []owned{}
+	for _, ref := range plan.Spec.Hooks {
+		refs = append(refs, owned{owner: "Plan", planScoped: true, ref: ref})
+	}
 	for _, vm := range plan.Spec.VMs {
 		for _, ref := range vm.Hooks {
-			// Step not valid.
-			if _, found := map[string]int{PreHook: 1, PostHook: 1}[ref.Step]; !found {
+			refs = append(refs, owned{owner: "VM: " + vm.String(), ref: ref})
+		}
+	}
+	steps := make(map[string]bool)
+	for _, owned := range refs {
+		ref := owned.ref
+		// Step not valid.
+		if owned.planScoped && !r.validPlanHookStep(ref.Step) ||
+			!owned.planScoped && !r.validHookStep(plan, ref.Step) {
+			description := fmt.Sprintf(
+				"%s step: %s",
+				owned.owner,
+				ref.Step)
+			stepNotValid.Items = append(
+				stepNotValid.Items,
+				description)
+		}
+		// Step not unique.
+		// Only one hook runs for each step.
+		key := owned.owner + "/" + ref.Step
+		if steps[key] {
+			description := fmt.Sprintf(
+				"%s step: %s (duplicate)",
+				owned.owner,
+				ref.Step)
+			stepNotValid.Items = append(
+				stepNotValid.Items,
+				description)
+		}
+		steps[key] = true
+		// Not Set.
+		if !libref.RefSet(&ref.Hook) {
+			notSet.Items = append(
+				notSet.Items,
+				owned.owner)
+			continue
+		}
+		// Not Found.
+		hook := &api.Hook{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: ref.Hook.Namespace,
+				Name:      ref.Hook.Name,
+			},
+			hook)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
 				description := fmt.Sprintf(
-					"VM: %s step: %s",
-					vm.String(),
-					ref.Step)
-				stepNotValid.Items = append(
-					stepNotValid.Items,
-					description)
-			}
-			// Not Set.
-			if !libref.RefSet(&ref.Hook) {
-				description := fmt.Sprintf("VM: %s", vm.String())
-				notSet.Items = append(
-					notSet.Items,
+					"%s hook: %s",
+					owned.owner,
+					ref.Hook.String())
+				notFound.Items = append(
+					notFound.Items,
 					description)
+				err = nil
 				continue
-			}
-			// Not Found.
-			hook := &api.Hook{}
-			err = r.Get(
-				context.TODO(),
-				client.ObjectKey{
-					Namespace: ref.Hook.Namespace,
-					Name:      ref.Hook.Name,
-				},
-				hook)
-			if err != nil {
-				if k8serr.IsNotFound(err) {
-					description := fmt.Sprintf(
-						"VM: %s hook: %s",
-						vm.String(),
-						ref.Hook.String())
-					notFound.Items = append(
-						notFound.Items,
-						description)
-					continue
-				} else {
-					return
-				}
 			} else {
-				plan.Referenced.Hooks = append(
-					plan.Referenced.Hooks,
-					hook)
-			}
-			// Not Ready.
-			if !hook.Status.HasCondition(libcnd.Ready) {
-				description := fmt.Sprintf(
-					"VM: %s hook: %s",
-					vm.String(),
-					ref.Hook.String())
-				notReady.Items = append(
-					notReady.Items,
-					description)
+				return
 			}
+		} else {
+			plan.Referenced.Hooks = append(
+				plan.Referenced.Hooks,
+				hook)
+		}
+		// In-guest hooks require the migrated VM.
+		if hook.Spec.Guest != nil && (owned.planScoped || ref.Step != PostHook) {
+			description := fmt.Sprintf(
+				"%s step: %s (in-guest hook)",
+				owned.owner,
+				ref.Step)
+			stepNotValid.Items = append(
+				stepNotValid.Items,
+				description)
+		}
+		// Not Ready.
+		if !hook.Status.HasCondition(libcnd.Ready) {
+			description := fmt.Sprintf(
+				"%s hook: %s",
+				owned.owner,
+				ref.Hook.String())
+			notReady.Items = append(
+				notReady.Items,
+				description)
 		}
 	}
-	for _, cnd := range []libcnd.Condition{} {
+	for _, cnd := range []libcnd.Condition{notSet, notFound, notReady, stepNotValid} {
 		if len(cnd.Items) > 0 {
 			plan.Status.SetCondition(cnd)
 		}
@@ -779,7 +1355,12 @@ func (r *Reconciler) validateVddkImage(plan *api.Plan) (err error) {
 	}
 
 	image, found := source.Spec.Settings[api.VDDK]
+	// the volume populator streams the disks using VDDK.
+	populator := source.UsesVolumePopulator() && !plan.SnapshotCopy() && destination.IsHost()
 	switch {
+	case populator && !found:
+		vddkNotConfigured.Message = "VDDK image is necessary for the vSphere volume populator"
+		plan.Status.SetCondition(vddkNotConfigured)
 	case !el9 && !found:
 		// VDDK image is required when EL8 virt-v2v image is in use
 		plan.Status.SetCondition(vddkNotConfigured)
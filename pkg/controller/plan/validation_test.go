@@ -2,23 +2,32 @@ package plan
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
 	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/vsphere/offload"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
 	"github.com/konveyor/forklift-controller/pkg/lib/condition"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
 	ginkgo "github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	core "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	discovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -176,3 +185,524 @@ func createPlan(name, namespace string, source, destination *api.Provider) *v1be
 		},
 	}
 }
+
+var _ = ginkgo.Describe("Plan concerns", func() {
+	reconciler := &Reconciler{}
+	concerns := []planbase.Concern{
+		{ID: "shareable-disk", Label: "Shareable disk", Category: model.ConcernCritical},
+		{ID: "changed-block-tracking", Label: "Changed Block Tracking", Category: model.ConcernWarning},
+		{ID: "rdm-disk", Label: "RDM disk", Category: model.ConcernCritical},
+	}
+
+	ginkgo.It("should block on critical concerns", func() {
+		vm := &planapi.VM{}
+		blocking, waived := reconciler.evaluateConcerns(vm, concerns)
+		gomega.Expect(blocking).To(gomega.ConsistOf("shareable-disk", "rdm-disk"))
+		gomega.Expect(waived).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should waive acknowledged concerns", func() {
+		vm := &planapi.VM{
+			AcknowledgedConcerns: []string{
+				"shareable-disk",
+				"changed-block-tracking",
+				"unknown",
+			},
+		}
+		vm.ID = "vm-1"
+		blocking, waived := reconciler.evaluateConcerns(vm, concerns)
+		gomega.Expect(blocking).To(gomega.ConsistOf("rdm-disk"))
+		gomega.Expect(waived).To(gomega.HaveLen(2))
+		gomega.Expect(waived[0].VM.ID).To(gomega.Equal("vm-1"))
+		gomega.Expect(waived[0].ID).To(gomega.Equal("shareable-disk"))
+		gomega.Expect(waived[0].Category).To(gomega.Equal(model.ConcernCritical))
+	})
+
+	ginkgo.It("should assign stable concern IDs", func() {
+		concern := model.Concern{Label: "Invalid VM Name"}
+		concern.AssignID()
+		gomega.Expect(concern.ID).To(gomega.Equal("invalid-vm-name"))
+		concern = model.Concern{Label: "Too many NICs!", Policy: "ns/nics"}
+		concern.AssignID()
+		gomega.Expect(concern.ID).To(gomega.Equal("ns/nics/too-many-nics"))
+		concern = model.Concern{ID: "custom", Label: "Custom"}
+		concern.AssignID()
+		gomega.Expect(concern.ID).To(gomega.Equal("custom"))
+	})
+
+	ginkgo.It("should prefer concerns reported for the plan", func() {
+		planned := []planbase.Concern{
+			{ID: "rdm-disk", Label: "RDM disk", Category: model.ConcernWarning},
+			{ID: "insufficient-node-cpu-capacity", Category: model.ConcernCritical},
+		}
+		merged := mergeConcerns(concerns, planned)
+		gomega.Expect(merged).To(gomega.HaveLen(4))
+		for _, c := range merged {
+			if c.ID == "rdm-disk" {
+				gomega.Expect(c.Category).To(gomega.Equal(model.ConcernWarning))
+			}
+		}
+	})
+
+	ginkgo.It("should detect the CNI type", func() {
+		gomega.Expect(cniType(`{"cniVersion":"0.3.1","type":"sriov"}`)).To(gomega.Equal("sriov"))
+		gomega.Expect(cniType(`{"plugins":[{"type":"bridge"},{"type":"tuning"}]}`)).To(gomega.Equal("bridge"))
+		gomega.Expect(cniType("")).To(gomega.BeEmpty())
+	})
+})
+
+var _ = ginkgo.Describe("Plan hook validation", func() {
+	ginkgo.It("should accept only pre and post plan-level hooks", func() {
+		hook := &v1beta1.Hook{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "wave"},
+		}
+		reconciler := createFakeReconciler(hook)
+		plan := &api.Plan{}
+		plan.Spec.Hooks = []planapi.HookRef{
+			{Step: PreHook, Hook: core.ObjectReference{Namespace: "test", Name: "wave"}},
+			{Step: PostPowerOffHook, Hook: core.ObjectReference{Namespace: "test", Name: "wave"}},
+		}
+		gomega.Expect(reconciler.validateHooks(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(HookStepNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("Plan step: PostPowerOffHook"))
+		gomega.Expect(plan.Referenced.Hooks).To(gomega.HaveLen(2))
+	})
+
+	ginkgo.It("should reject more than one plan-level hook for a step", func() {
+		hook := &v1beta1.Hook{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "wave"},
+		}
+		reconciler := createFakeReconciler(hook)
+		plan := &api.Plan{}
+		plan.Spec.Hooks = []planapi.HookRef{
+			{Step: PreHook, Hook: core.ObjectReference{Namespace: "test", Name: "wave"}},
+			{Step: PreHook, Hook: core.ObjectReference{Namespace: "test", Name: "wave"}},
+		}
+		gomega.Expect(reconciler.validateHooks(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(HookStepNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("Plan step: PreHook (duplicate)"))
+	})
+})
+
+var _ = ginkgo.Describe("Plan test failover", func() {
+	source := createProvider("source", "test", "https://source", v1beta1.VSphere, &core.ObjectReference{})
+	testPlan := func() *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.TargetNamespace = "production"
+		plan.Spec.Test = &planapi.TestFailover{
+			Namespace: "rehearsal",
+			Network:   &core.ObjectReference{Namespace: "test", Name: "isolated"},
+		}
+		plan.Referenced.Provider.Source = source
+		return plan
+	}
+
+	ginkgo.It("should resolve the test network map", func() {
+		mp := &v1beta1.NetworkMap{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "isolated"},
+		}
+		mp.Status.SetCondition(condition.Condition{Type: condition.Ready, Status: condition.True})
+		reconciler := createFakeReconciler(mp)
+		plan := testPlan()
+		gomega.Expect(reconciler.validateTest(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(TestNotValid)).To(gomega.BeFalse())
+		gomega.Expect(plan.Referenced.Map.TestNetwork).ToNot(gomega.BeNil())
+	})
+
+	ginkgo.It("should reject an invalid test failover", func() {
+		reconciler := createFakeReconciler()
+		plan := testPlan()
+		plan.Spec.Warm = true
+		plan.Spec.Test.Namespace = "Not_Valid"
+		plan.Referenced.Provider.Source = createProvider("source", "test", "", v1beta1.OpenStack, &core.ObjectReference{})
+		gomega.Expect(reconciler.validateTest(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(TestNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.HaveLen(4))
+	})
+
+	ginkgo.It("should require a separate namespace and network map", func() {
+		reconciler := createFakeReconciler()
+		plan := testPlan()
+		plan.Spec.Test = &planapi.TestFailover{}
+		gomega.Expect(reconciler.validateTest(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(TestNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("namespace: not set.", "network: not set."))
+		plan = testPlan()
+		plan.Spec.Test.Namespace = plan.Spec.TargetNamespace
+		gomega.Expect(reconciler.validateTest(plan)).To(gomega.Succeed())
+		cnd = plan.Status.FindCondition(TestNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ContainElement("namespace: must not be the target namespace."))
+	})
+
+	ginkgo.It("should reject a test network map that is not isolated", func() {
+		mp := &v1beta1.NetworkMap{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "isolated"},
+		}
+		mp.Spec.Map = []v1beta1.NetworkPair{
+			{Destination: v1beta1.DestinationNetwork{Type: Pod}},
+			{Destination: v1beta1.DestinationNetwork{Type: Multus, Namespace: "test", Name: "prod"}},
+			{Destination: v1beta1.DestinationNetwork{Type: Multus, Namespace: "test", Name: "lab"}},
+		}
+		mp.Status.SetCondition(condition.Condition{Type: condition.Ready, Status: condition.True})
+		reconciler := createFakeReconciler(mp)
+		plan := testPlan()
+		plan.Referenced.Map.Network = &v1beta1.NetworkMap{}
+		plan.Referenced.Map.Network.Spec.Map = []v1beta1.NetworkPair{
+			{Destination: v1beta1.DestinationNetwork{Type: Multus, Namespace: "test", Name: "prod"}},
+		}
+		gomega.Expect(reconciler.validateTest(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(TestNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("network: not isolated: pod, test/prod"))
+		gomega.Expect(plan.Referenced.Map.TestNetwork).To(gomega.BeNil())
+	})
+
+	ginkgo.It("should keep the rehearsal separate", func() {
+		production := &v1beta1.NetworkMap{}
+		isolated := &v1beta1.NetworkMap{}
+		plan := testPlan()
+		plan.Referenced.Map.Network = production
+		plan.Referenced.Map.TestNetwork = isolated
+		plan.Status.Migration.VMs = []*planapi.VMStatus{{Phase: Completed}}
+		restore := plan.Rehearse()
+		gomega.Expect(plan.Rehearsing()).To(gomega.BeTrue())
+		gomega.Expect(plan.Spec.TargetNamespace).To(gomega.Equal("rehearsal"))
+		gomega.Expect(plan.Referenced.Map.Network).To(gomega.BeIdenticalTo(isolated))
+		gomega.Expect(plan.Status.Migration.VMs).To(gomega.BeEmpty())
+		plan.Status.Migration.VMs = []*planapi.VMStatus{{Phase: Started}}
+		restore()
+		gomega.Expect(plan.Rehearsing()).To(gomega.BeFalse())
+		gomega.Expect(plan.Spec.TargetNamespace).To(gomega.Equal("production"))
+		gomega.Expect(plan.Referenced.Map.Network).To(gomega.BeIdenticalTo(production))
+		gomega.Expect(plan.Status.Migration.VMs[0].Phase).To(gomega.Equal(Completed))
+		gomega.Expect(plan.Status.Rehearsal.VMs[0].Phase).To(gomega.Equal(Started))
+	})
+
+	ginkgo.It("should not power off the source", func() {
+		plan := testPlan()
+		migration := Migration{Context: &plancontext.Context{Plan: plan}}
+		itinerary := migration.itinerary()
+		gomega.Expect(itinerary.Name).To(gomega.Equal("Test"))
+		for _, step := range itinerary.Pipeline {
+			gomega.Expect(step.Name).ToNot(gomega.BeElementOf(PowerOffSource, WaitForPowerOff, CopyingPaused))
+		}
+		gomega.Expect(plan.SnapshotCopy()).To(gomega.BeTrue())
+	})
+})
+
+var _ = ginkgo.Describe("Plan storage offload", func() {
+	offloadPlan := func(driver string) *api.Plan {
+		plan := &api.Plan{}
+		source := createProvider("source", "test", "https://source", v1beta1.VSphere, &core.ObjectReference{})
+		source.Spec.Settings = map[string]string{v1beta1.StorageOffload: driver}
+		plan.Referenced.Provider.Source = source
+		return plan
+	}
+
+	ginkgo.BeforeEach(func() {
+		offload.Register("fake", (&offload.Fake{}).Factory)
+		settings.Settings.Features.VsphereStorageOffload = true
+	})
+
+	ginkgo.AfterEach(func() {
+		settings.Settings.Features.VsphereStorageOffload = false
+	})
+
+	ginkgo.It("should accept a registered driver", func() {
+		plan := offloadPlan("fake")
+		gomega.Expect(createFakeReconciler().validateStorageOffload(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(StorageOffloadNotSupported)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should warn about a driver not registered", func() {
+		plan := offloadPlan("unknown")
+		gomega.Expect(createFakeReconciler().validateStorageOffload(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(StorageOffloadNotSupported)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Category).To(gomega.Equal(Warn))
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("unknown"))
+	})
+
+	ginkgo.It("should warn when the feature is not enabled", func() {
+		settings.Settings.Features.VsphereStorageOffload = false
+		plan := offloadPlan("fake")
+		gomega.Expect(createFakeReconciler().validateStorageOffload(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(StorageOffloadNotSupported)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Reason).To(gomega.Equal(NotSupported))
+	})
+})
+
+var _ = ginkgo.Describe("Plan transfer relay", func() {
+	relayPlan := func() *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.TargetNamespace = "test"
+		plan.Spec.TransferRelay = &planapi.TransferRelay{
+			URL:    "https://relay.example.com:8443",
+			Secret: "relay",
+		}
+		plan.Referenced.Provider.Source = createProvider("source", "test", "https://source", v1beta1.OVirt, &core.ObjectReference{})
+		plan.Referenced.Provider.Destination = createProvider("host", "test", "", v1beta1.OpenShift, &core.ObjectReference{})
+		return plan
+	}
+
+	ginkgo.It("should accept a valid relay", func() {
+		secret := &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "relay"},
+			Data: map[string][]byte{
+				"tls.crt": []byte("cert"),
+				"tls.key": []byte("key"),
+				"ca.crt":  []byte("ca"),
+			},
+		}
+		reconciler := createFakeReconciler(secret)
+		plan := relayPlan()
+		gomega.Expect(reconciler.validateTransferRelay(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(TransferRelayNotValid)).To(gomega.BeFalse())
+		gomega.Expect(plan.Status.HasCondition(TransferRelayNotSupported)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should reject an invalid relay", func() {
+		secret := &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "relay"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert")},
+		}
+		reconciler := createFakeReconciler(secret)
+		plan := relayPlan()
+		plan.Spec.TransferRelay.URL = "http://relay.example.com"
+		plan.Referenced.Provider.Source = createProvider("source", "test", "https://source", v1beta1.VSphere, &core.ObjectReference{})
+		gomega.Expect(reconciler.validateTransferRelay(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(TransferRelayNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.HaveLen(3))
+		gomega.Expect(plan.Status.FindCondition(TransferRelayNotSupported).Category).To(gomega.Equal(Warn))
+	})
+
+	ginkgo.It("should warn about plans with disks copied by CDI", func() {
+		secret := &core.Secret{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "relay"},
+			Data: map[string][]byte{
+				"tls.crt": []byte("cert"),
+				"tls.key": []byte("key"),
+				"ca.crt":  []byte("ca"),
+			},
+		}
+		reconciler := createFakeReconciler(secret)
+		plan := relayPlan()
+		plan.Spec.Warm = true
+		gomega.Expect(reconciler.validateTransferRelay(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(TransferRelayNotValid)).To(gomega.BeFalse())
+		cnd := plan.Status.FindCondition(TransferRelayNotSupported)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Category).To(gomega.Equal(Warn))
+	})
+})
+
+var _ = ginkgo.Describe("Plan integrity", func() {
+	integrityPlan := func(providerType v1beta1.ProviderType) *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.VerifyIntegrity = true
+		plan.Referenced.Provider.Source = createProvider("source", "test", "https://source", providerType, &core.ObjectReference{})
+		plan.Referenced.Provider.Destination = createProvider("host", "test", "", v1beta1.OpenShift, &core.ObjectReference{})
+		return plan
+	}
+
+	ginkgo.It("should accept the disks copied by CDI or populators", func() {
+		reconciler := createFakeReconciler()
+		plan := integrityPlan(v1beta1.OVirt)
+		gomega.Expect(reconciler.validateVerify(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(IntegrityNotSupported)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should reject the disks copied by virt-v2v", func() {
+		reconciler := createFakeReconciler()
+		for _, providerType := range []v1beta1.ProviderType{v1beta1.VSphere, v1beta1.Ova} {
+			plan := integrityPlan(providerType)
+			gomega.Expect(reconciler.validateVerify(plan)).To(gomega.Succeed())
+			cnd := plan.Status.FindCondition(IntegrityNotSupported)
+			gomega.Expect(cnd).ToNot(gomega.BeNil())
+			gomega.Expect(cnd.Category).To(gomega.Equal(Critical))
+		}
+	})
+
+	ginkgo.It("should warn for warm migrations", func() {
+		reconciler := createFakeReconciler()
+		plan := integrityPlan(v1beta1.VSphere)
+		plan.Spec.Warm = true
+		gomega.Expect(reconciler.validateVerify(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(IntegrityNotSupported)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Category).To(gomega.Equal(Warn))
+	})
+})
+
+var _ = ginkgo.Describe("Plan verification", func() {
+	probePlan := func(path string) *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.Verify = &planapi.Verify{
+			Probe: &planapi.VerifyProbe{Port: 80, Path: path},
+		}
+		return plan
+	}
+
+	ginkgo.It("should accept an absolute probe path", func() {
+		reconciler := createFakeReconciler()
+		for _, path := range []string{"", "/", "/health?ready=1"} {
+			plan := probePlan(path)
+			gomega.Expect(reconciler.validateVerify(plan)).To(gomega.Succeed())
+			gomega.Expect(plan.Status.HasCondition(VerifyNotValid)).To(gomega.BeFalse(), path)
+		}
+	})
+
+	ginkgo.It("should reject a probe path not valid", func() {
+		reconciler := createFakeReconciler()
+		for _, path := range []string{
+			"health",
+			"/health'; rm -rf / '",
+			"/health\"",
+			"/health`id`",
+			"/health\nHost: x",
+		} {
+			plan := probePlan(path)
+			gomega.Expect(reconciler.validateVerify(plan)).To(gomega.Succeed())
+			gomega.Expect(plan.Status.HasCondition(VerifyNotValid)).To(gomega.BeTrue(), path)
+		}
+	})
+})
+
+var _ = ginkgo.Describe("Plan bandwidth limit", func() {
+	limitPlan := func(providerType v1beta1.ProviderType) *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.BandwidthLimit = &planapi.BandwidthLimit{
+			Total: ptr.To(resource.MustParse("100Mi")),
+		}
+		plan.Referenced.Provider.Source = createProvider("source", "test", "https://source", providerType, &core.ObjectReference{})
+		plan.Referenced.Provider.Destination = createProvider("host", "test", "", v1beta1.OpenShift, &core.ObjectReference{})
+		return plan
+	}
+
+	ginkgo.It("should accept a limit applied by the populators", func() {
+		reconciler := createFakeReconciler()
+		plan := limitPlan(v1beta1.OVirt)
+		gomega.Expect(reconciler.validateBandwidthLimit(plan)).To(gomega.Succeed())
+		gomega.Expect(plan.Status.HasCondition(BandwidthLimitNotValid)).To(gomega.BeFalse())
+		gomega.Expect(plan.Status.HasCondition(BandwidthLimitNotSupported)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should reject negative limits", func() {
+		reconciler := createFakeReconciler()
+		plan := limitPlan(v1beta1.OpenStack)
+		plan.Spec.BandwidthLimit.Schedule = []planapi.BandwidthWindow{
+			{
+				TimeWindow: planapi.TimeWindow{Start: "09:00", End: "17:00"},
+				PerVM:      ptr.To(resource.MustParse("-1Mi")),
+			},
+		}
+		gomega.Expect(reconciler.validateBandwidthLimit(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(BandwidthLimitNotValid)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Items).To(gomega.ConsistOf("schedule[0].perVM: must not be negative."))
+	})
+
+	ginkgo.It("should accept a limit applied by virt-v2v", func() {
+		reconciler := createFakeReconciler()
+		for _, providerType := range []v1beta1.ProviderType{v1beta1.VSphere, v1beta1.Ova} {
+			plan := limitPlan(providerType)
+			gomega.Expect(reconciler.validateBandwidthLimit(plan)).To(gomega.Succeed())
+			gomega.Expect(plan.Status.HasCondition(BandwidthLimitNotSupported)).To(gomega.BeFalse())
+		}
+	})
+
+	ginkgo.It("should reject a limit when the disks are copied by CDI", func() {
+		reconciler := createFakeReconciler()
+		plan := limitPlan(v1beta1.VSphere)
+		plan.Spec.Warm = true
+		gomega.Expect(reconciler.validateBandwidthLimit(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(BandwidthLimitNotSupported)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Category).To(gomega.Equal(Critical))
+	})
+
+	ginkgo.It("should split the plan limit between the VMs and disks", func() {
+		limit := &planapi.BandwidthLimit{
+			Total: ptr.To(resource.MustParse("100Mi")),
+			PerVM: ptr.To(resource.MustParse("40Mi")),
+			Schedule: []planapi.BandwidthWindow{
+				{
+					TimeWindow: planapi.TimeWindow{Start: "22:00", End: "06:00"},
+				},
+			},
+		}
+		rateLimit := limit.RateLimit(4, 2)
+		gomega.Expect(rateLimit.Rate).To(gomega.Equal(int64(25 << 20 / 2)))
+		gomega.Expect(rateLimit.Schedule).To(gomega.HaveLen(1))
+		gomega.Expect(rateLimit.Schedule[0].Rate).To(gomega.BeZero())
+		gomega.Expect(limit.RateLimit(1, 4).Rate).To(gomega.Equal(int64(10 << 20)))
+	})
+})
+
+var _ = ginkgo.Describe("Plan destination validation cache", func() {
+	newPlan := func() *api.Plan {
+		return &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:  "test",
+				Name:       "test",
+				UID:        "plan-1",
+				Generation: 2,
+			},
+		}
+	}
+	ginkgo.It("should be current for the plan generation and policy version", func() {
+		plan := newPlan()
+		validation := &DestinationValidation{
+			UID:        plan.UID,
+			Generation: plan.Generation,
+			Version:    7,
+			Expiration: time.Now().Add(DestinationTTL),
+		}
+		gomega.Expect(validation.Current(plan, 7)).To(gomega.BeTrue())
+		gomega.Expect(validation.Current(plan, 8)).To(gomega.BeFalse())
+		plan.Generation++
+		gomega.Expect(validation.Current(plan, 7)).To(gomega.BeFalse())
+		recreated := newPlan()
+		recreated.UID = "plan-2"
+		gomega.Expect(validation.Current(recreated, 7)).To(gomega.BeFalse())
+		validation.Expiration = time.Now()
+		gomega.Expect(validation.Current(newPlan(), 7)).To(gomega.BeFalse())
+	})
+	ginkgo.It("should cache by plan", func() {
+		cache := DestinationCache{}
+		plan := newPlan()
+		_, found := cache.Get(plan)
+		gomega.Expect(found).To(gomega.BeFalse())
+		validation := &DestinationValidation{UID: plan.UID}
+		cache.Put(plan, validation)
+		cached, found := cache.Get(plan)
+		gomega.Expect(found).To(gomega.BeTrue())
+		gomega.Expect(cached).To(gomega.BeIdenticalTo(validation))
+		cache.Delete(client.ObjectKeyFromObject(plan))
+		_, found = cache.Get(plan)
+		gomega.Expect(found).To(gomega.BeFalse())
+	})
+})
+
+var _ = ginkgo.Describe("Plan VDDK validation", func() {
+	ginkgo.It("should require a VDDK image for the volume populator", func() {
+		source := createProvider("source", "test", "https://source", v1beta1.VSphere, &core.ObjectReference{})
+		source.Spec.Settings = map[string]string{v1beta1.VolumePopulator: "true"}
+		destination := createProvider("destination", "test", "", v1beta1.OpenShift, &core.ObjectReference{})
+		reconciler := createFakeReconciler()
+		plan := &api.Plan{}
+		plan.Referenced.Provider.Source = source
+		plan.Referenced.Provider.Destination = destination
+		gomega.Expect(reconciler.validateVddkImage(plan)).To(gomega.Succeed())
+		cnd := plan.Status.FindCondition(VDDKNotConfigured)
+		gomega.Expect(cnd).ToNot(gomega.BeNil())
+		gomega.Expect(cnd.Message).To(gomega.ContainSubstring("volume populator"))
+	})
+})
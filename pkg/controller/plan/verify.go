@@ -0,0 +1,413 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Verification step annotations.
+const (
+	// The VM was started by the verification.
+	AnnVerifyStarted = "startedForVerification"
+	// Hostname reported by the guest agent.
+	AnnVerifyHostname = "hostname"
+	// IP addresses reported by the guest agent.
+	AnnVerifyIPs = "ipAddresses"
+)
+
+// Probe timeout.
+const VerifyProbeTimeout = time.Second * 5
+
+// App label value of the verification pod.
+const verifyApp = "verify"
+
+// Verifier.
+// Verifies the migrated VM boots and passes the checks
+// enabled on the plan.
+type Verifier struct {
+	*plancontext.Context
+	// Provider builder.
+	builder planbase.Builder
+	// KubeVirt.
+	kubevirt *KubeVirt
+	// Guest agent endpoint.
+	endpoint GuestAgentEndpoint
+	// Probe.
+	// Returns the reason the probe has not passed.
+	probe func(vm *planapi.VMStatus, address string) (reason string, err error)
+}
+
+// Run the verification.
+// The step is marked completed when the checks have passed
+// or the deadline has been exceeded. The step reason describes
+// the check that has not (yet) passed.
+func (r *Verifier) Run(vm *planapi.VMStatus, step *planapi.Step) (err error) {
+	spec := r.Plan.Spec.Verify
+	step.MarkStarted()
+	if step.Annotations == nil {
+		step.Annotations = make(map[string]string)
+	}
+	object := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Name:      vm.Name,
+		},
+		object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.ensureRunning(object, step)
+	if err != nil {
+		return
+	}
+	reason, err := r.check(vm, step)
+	if err != nil {
+		return
+	}
+	step.Reason = reason
+	if reason == "" {
+		err = r.restore(object, step)
+		if err != nil {
+			return
+		}
+		step.Progress.Completed = 1
+		step.MarkCompleted()
+		return
+	}
+	deadline := time.Duration(spec.DeadlineSeconds()) * time.Second
+	if time.Since(step.Started.Time) < deadline {
+		return
+	}
+	err = r.restore(object, step)
+	if err != nil {
+		return
+	}
+	if spec.ContinueOnFailure() {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VMVerificationFailed,
+				Status:   True,
+				Category: Warn,
+				Reason:   NotValid,
+				Message:  "The VM did not pass verification; the migration continued.",
+				Items:    []string{reason},
+				Durable:  true,
+			})
+	} else {
+		step.AddError("Verification failed: " + reason)
+	}
+	step.MarkCompleted()
+	return
+}
+
+// Ensure the VM is running.
+// A VM that is not running is started and stopped
+// when the verification has completed.
+func (r *Verifier) ensureRunning(object *cnv.VirtualMachine, step *planapi.Step) (err error) {
+	strategy, err := object.RunStrategy()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if strategy != cnv.RunStrategyHalted {
+		return
+	}
+	if _, started := step.Annotations[AnnVerifyStarted]; started {
+		return
+	}
+	r.setRunning(object, true)
+	err = r.Destination.Client.Update(context.TODO(), object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	step.Annotations[AnnVerifyStarted] = "true"
+	r.Log.Info(
+		"VM started for verification.",
+		"vm",
+		object.Namespace+"/"+object.Name)
+	return
+}
+
+// Stop the VM when started by the verification.
+func (r *Verifier) restore(object *cnv.VirtualMachine, step *planapi.Step) (err error) {
+	if _, started := step.Annotations[AnnVerifyStarted]; !started {
+		return
+	}
+	r.setRunning(object, false)
+	err = r.Destination.Client.Update(context.TODO(), object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	delete(step.Annotations, AnnVerifyStarted)
+	return
+}
+
+// Set the VM run strategy.
+func (r *Verifier) setRunning(object *cnv.VirtualMachine, running bool) {
+	if object.Spec.RunStrategy != nil {
+		strategy := cnv.RunStrategyHalted
+		if running {
+			strategy = cnv.RunStrategyAlways
+		}
+		object.Spec.RunStrategy = &strategy
+		return
+	}
+	object.Spec.Running = &running
+}
+
+// Run the checks.
+// Returns the reason the first failed check did not pass.
+func (r *Verifier) check(vm *planapi.VMStatus, step *planapi.Step) (reason string, err error) {
+	spec := r.Plan.Spec.Verify
+	vmi := &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Name:      vm.Name,
+		},
+		vmi)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			reason = "The VMI has not been created."
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if vmi.Status.Phase != cnv.Running {
+		reason = fmt.Sprintf("The VMI phase is: %s.", vmi.Status.Phase)
+		return
+	}
+	addresses := []string{}
+	for _, nic := range vmi.Status.Interfaces {
+		if len(nic.IPs) > 0 {
+			addresses = append(addresses, nic.IPs...)
+		} else if nic.IP != "" {
+			addresses = append(addresses, nic.IP)
+		}
+	}
+	if spec.GuestAgent {
+		reason, err = r.checkGuest(vm, step, addresses)
+		if err != nil || reason != "" {
+			return
+		}
+	}
+	if spec.Probe != nil {
+		if len(addresses) == 0 {
+			reason = "The VMI has not reported an IP address."
+			return
+		}
+		probe := r.probe
+		if probe == nil {
+			probe = r.probePod
+		}
+		reason, err = probe(vm, addresses[0])
+		if err != nil || reason != "" {
+			return
+		}
+	}
+
+	return
+}
+
+// Check the guest agent has connected and reported
+// the (expected) hostname and IP addresses.
+func (r *Verifier) checkGuest(vm *planapi.VMStatus, step *planapi.Step, addresses []string) (reason string, err error) {
+	agent := GuestAgent{Endpoint: r.endpoint}
+	if agent.Endpoint == nil {
+		agent.Endpoint = NewLauncherEndpoint(r.Context, vm)
+	}
+	connected, err := agent.Endpoint.Connected()
+	if err != nil {
+		return
+	}
+	if !connected {
+		reason = "The guest agent is not connected."
+		return
+	}
+	hostname, hErr := agent.HostName()
+	if hErr != nil || hostname == "" {
+		reason = "The guest agent has not reported the hostname."
+		return
+	}
+	step.Annotations[AnnVerifyHostname] = hostname
+	if len(addresses) == 0 {
+		reason = "The guest agent has not reported an IP address."
+		return
+	}
+	step.Annotations[AnnVerifyIPs] = strings.Join(addresses, ",")
+	expected, err := r.builder.GuestIdentity(vm.Ref)
+	if err != nil {
+		return
+	}
+	if expected.HostName != "" && !sameHost(hostname, expected.HostName) {
+		reason = fmt.Sprintf(
+			"Expected hostname: %s, reported: %s.",
+			expected.HostName,
+			hostname)
+		return
+	}
+	reported := make(map[string]bool)
+	for _, ip := range addresses {
+		reported[ip] = true
+	}
+	missing := []string{}
+	for _, ip := range expected.IPs {
+		if !reported[ip] {
+			missing = append(missing, ip)
+		}
+	}
+	if len(missing) > 0 {
+		reason = fmt.Sprintf(
+			"Expected IP addresses not reported: %s.",
+			strings.Join(missing, ","))
+	}
+
+	return
+}
+
+// Probe the address from a pod in the target namespace.
+// The controller may not be able to reach the VM network so
+// the probe runs next to the VM. A failed probe pod is deleted
+// and the probe is retried.
+func (r *Verifier) probePod(vm *planapi.VMStatus, address string) (reason string, err error) {
+	list, err := r.kubevirt.GetPodsWithLabels(r.kubevirt.verifyLabels(vm.Ref))
+	if err != nil {
+		return
+	}
+	if len(list.Items) == 0 {
+		err = r.createProbePod(vm, address)
+		if err != nil {
+			return
+		}
+		reason = "The probe is pending."
+		return
+	}
+	pod := &list.Items[0]
+	switch pod.Status.Phase {
+	case core.PodSucceeded:
+		err = r.kubevirt.DeleteObject(pod, vm, "Deleted verification pod.", "pod")
+	case core.PodFailed:
+		reason = fmt.Sprintf("Probe failed: %s", terminatedMessage(pod))
+		err = r.kubevirt.DeleteObject(pod, vm, "Deleted verification pod.", "pod")
+	default:
+		reason = "The probe is running."
+	}
+	return
+}
+
+// Create the probe pod.
+// The probe error is reported as the termination message.
+func (r *Verifier) createProbePod(vm *planapi.VMStatus, address string) (err error) {
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Labels:       r.kubevirt.verifyLabels(vm.Ref),
+			GenerateName: r.kubevirt.getGeneratedName(vm) + "verify-",
+		},
+		Spec: core.PodSpec{
+			RestartPolicy: core.RestartPolicyNever,
+			Containers: []core.Container{
+				{
+					Name:    "main",
+					Image:   Settings.Migration.VirtV2vImageCold,
+					Command: ProbeCommand(address, r.Plan.Spec.Verify.Probe),
+					// The probe errors (stderr) are reported.
+					TerminationMessagePolicy: core.TerminationMessageFallbackToLogsOnError,
+					SecurityContext: &core.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						RunAsNonRoot:             ptr.To(true),
+						RunAsUser:                ptr.To(qemuUser),
+						Capabilities: &core.Capabilities{
+							Drop: []core.Capability{"ALL"},
+						},
+					},
+				},
+			},
+			SecurityContext: &core.PodSecurityContext{
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+		},
+	}
+	err = r.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created verification pod.",
+		"pod",
+		pod.Namespace+"/"+pod.Name,
+		"vm",
+		vm.String())
+	return
+}
+
+// Probe command.
+// HTTP GET when the path is specified; otherwise TCP connect.
+// The command is not interpreted by a shell. The TCP connect
+// is done by bash with the address and port passed as (positional)
+// arguments. The errors (stderr) are reported by the pod using the
+// log as the termination message.
+func ProbeCommand(address string, probe *planapi.VerifyProbe) []string {
+	timeout := strconv.Itoa(int(VerifyProbeTimeout.Seconds()))
+	port := strconv.Itoa(int(probe.Port))
+	if probe.Path == "" {
+		return []string{
+			"timeout",
+			timeout,
+			"/bin/bash",
+			"-c",
+			`</dev/tcp/"$0"/"$1"`,
+			address,
+			port,
+		}
+	}
+	return []string{
+		"curl",
+		"-sSf",
+		"-o",
+		"/dev/null",
+		"--max-time",
+		timeout,
+		"http://" + net.JoinHostPort(address, port) + probe.Path,
+	}
+}
+
+// The hostnames are the same.
+// A short name matches the first label of a FQDN.
+func sameHost(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	short := func(name string) string {
+		s, _, _ := strings.Cut(name, ".")
+		return s
+	}
+	return strings.EqualFold(short(a), short(b))
+}
@@ -0,0 +1,209 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Fake builder.
+// Reports the expected guest identity.
+type fakeBuilder struct {
+	planbase.Builder
+	identity planbase.GuestIdentity
+}
+
+func (r *fakeBuilder) GuestIdentity(ref.Ref) (planbase.GuestIdentity, error) {
+	return r.identity, nil
+}
+
+var _ = ginkgo.Describe("Verification", func() {
+	var vm *planapi.VMStatus
+	var step *planapi.Step
+	var verifier *Verifier
+	var builder *fakeBuilder
+	var dClient client.Client
+	var agent *fakeGuestAgent
+
+	build := func(verify *planapi.Verify, objects ...runtime.Object) {
+		scheme := runtime.NewScheme()
+		_ = cnv.AddToScheme(scheme)
+		_ = core.AddToScheme(scheme)
+		dClient = fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(objects...).
+			Build()
+		plan := &api.Plan{}
+		plan.UID = "plan"
+		plan.Spec.TargetNamespace = "test"
+		plan.Spec.Verify = verify
+		agent = &fakeGuestAgent{connected: true}
+		builder = &fakeBuilder{}
+		ctx := &plancontext.Context{
+			Plan:      plan,
+			Migration: createMigration(),
+			Log:       logging.WithName("test"),
+		}
+		ctx.Destination.Client = dClient
+		verifier = &Verifier{
+			Context:  ctx,
+			builder:  builder,
+			kubevirt: &KubeVirt{Context: ctx},
+			endpoint: agent,
+		}
+		vm = &planapi.VMStatus{}
+		vm.Name = "vm1"
+		step = &planapi.Step{Task: planapi.Task{Name: Verification}}
+	}
+	halted := func() *cnv.VirtualMachine {
+		running := false
+		return &cnv.VirtualMachine{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm1"},
+			Spec:       cnv.VirtualMachineSpec{Running: &running},
+		}
+	}
+	vmi := func() *cnv.VirtualMachineInstance {
+		return &cnv.VirtualMachineInstance{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm1"},
+			Status: cnv.VirtualMachineInstanceStatus{
+				Phase: cnv.Running,
+				Interfaces: []cnv.VirtualMachineInstanceNetworkInterface{
+					{IP: "10.0.0.5", IPs: []string{"10.0.0.5"}},
+				},
+			},
+		}
+	}
+	running := func() bool {
+		object := &cnv.VirtualMachine{}
+		err := dClient.Get(context.TODO(), client.ObjectKey{Namespace: "test", Name: "vm1"}, object)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		return *object.Spec.Running
+	}
+
+	ginkgo.It("should start the VM and wait for the VMI", func() {
+		build(&planapi.Verify{}, halted())
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(running()).To(gomega.BeTrue())
+		gomega.Expect(step.Annotations).To(gomega.HaveKey(AnnVerifyStarted))
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		gomega.Expect(step.Reason).ToNot(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should pass the checks and restore the power state", func() {
+		probed := ""
+		build(
+			&planapi.Verify{
+				GuestAgent: true,
+				Probe:      &planapi.VerifyProbe{Port: 22},
+			},
+			halted(),
+			vmi())
+		verifier.probe = func(_ *planapi.VMStatus, address string) (string, error) {
+			probed = address
+			return "", nil
+		}
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(step.Annotations[AnnVerifyHostname]).To(gomega.Equal("vm1"))
+		gomega.Expect(probed).To(gomega.Equal("10.0.0.5"))
+		gomega.Expect(running()).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should fail the step when the deadline is exceeded", func() {
+		build(&planapi.Verify{GuestAgent: true, Deadline: 60}, halted(), vmi())
+		agent.connected = false
+		started := meta.NewTime(time.Now().Add(-time.Hour))
+		step.Started = &started
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).ToNot(gomega.BeNil())
+	})
+
+	ginkgo.It("should flag the VM when failures are allowed", func() {
+		build(
+			&planapi.Verify{
+				Deadline:  60,
+				Probe:     &planapi.VerifyProbe{Port: 80, Path: "/health"},
+				OnFailure: planapi.HookContinue,
+			},
+			halted(),
+			vmi())
+		verifier.probe = func(*planapi.VMStatus, string) (string, error) {
+			return "Probe failed: connection refused", nil
+		}
+		started := meta.NewTime(time.Now().Add(-time.Hour))
+		step.Started = &started
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+		gomega.Expect(step.Error).To(gomega.BeNil())
+		gomega.Expect(vm.HasCondition(VMVerificationFailed)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should compare the hostname with the source", func() {
+		build(&planapi.Verify{GuestAgent: true}, halted(), vmi())
+		builder.identity.HostName = "vm2.example.com"
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+		gomega.Expect(step.Reason).To(gomega.ContainSubstring("vm2.example.com"))
+		builder.identity.HostName = "VM1.example.com"
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should probe from a pod in the target namespace", func() {
+		build(
+			&planapi.Verify{Probe: &planapi.VerifyProbe{Port: 80, Path: "/health"}},
+			halted(),
+			vmi())
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.Reason).To(gomega.Equal("The probe is pending."))
+		pods := &core.PodList{}
+		gomega.Expect(dClient.List(context.TODO(), pods)).To(gomega.Succeed())
+		gomega.Expect(pods.Items).To(gomega.HaveLen(1))
+		pod := &pods.Items[0]
+		gomega.Expect(pod.Namespace).To(gomega.Equal("test"))
+		container := pod.Spec.Containers[0]
+		gomega.Expect(container.Command).To(gomega.Equal([]string{
+			"curl", "-sSf", "-o", "/dev/null", "--max-time", "5", "http://10.0.0.5:80/health",
+		}))
+		gomega.Expect(container.TerminationMessagePolicy).To(
+			gomega.Equal(core.TerminationMessageFallbackToLogsOnError))
+		pod.Status.Phase = core.PodFailed
+		pod.Status.ContainerStatuses = []core.ContainerStatus{
+			{
+				State: core.ContainerState{
+					Terminated: &core.ContainerStateTerminated{Message: "connection refused"},
+				},
+			},
+		}
+		gomega.Expect(dClient.Status().Update(context.TODO(), pod)).To(gomega.Succeed())
+		gomega.Expect(verifier.Run(vm, step)).To(gomega.Succeed())
+		gomega.Expect(step.Reason).To(gomega.Equal("Probe failed: connection refused"))
+		gomega.Expect(dClient.List(context.TODO(), pods)).To(gomega.Succeed())
+		gomega.Expect(pods.Items).To(gomega.BeEmpty())
+	})
+})
+
+var _ = ginkgo.Describe("Verification probe", func() {
+	ginkgo.It("should pass the address and port as arguments", func() {
+		command := ProbeCommand("10.0.0.5", &planapi.VerifyProbe{Port: 22})
+		gomega.Expect(command).To(gomega.Equal([]string{
+			"timeout", "5", "/bin/bash", "-c", `</dev/tcp/"$0"/"$1"`, "10.0.0.5", "22",
+		}))
+	})
+})
@@ -123,6 +123,7 @@ func (r *Collector) Start() error {
 	ctx := Context{
 		client: r.client,
 		db:     r.db,
+		scope:  r.provider.Scope(),
 		log:    r.log,
 	}
 	ctx.ctx, r.cancel = context.WithCancel(context.Background())
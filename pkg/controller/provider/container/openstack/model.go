@@ -7,6 +7,7 @@ import (
 
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
 	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/openstack"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
@@ -42,6 +43,8 @@ type Context struct {
 	db libmodel.DB
 	// OpenStack client.
 	client *Client
+	// Collection scope (project names).
+	scope []string
 	// Log.
 	log logging.LevelLogger
 }
@@ -57,6 +60,26 @@ func (r *Context) canceled() (done bool) {
 	return
 }
 
+// Validate that the (client) project is in scope.
+// Only the project of the client is collected, so
+// the provider is scoped by its credentials.
+func (r *Context) inScope(project *libclient.Project) (err error) {
+	if len(r.scope) == 0 {
+		return
+	}
+	for _, name := range r.scope {
+		if name == project.Name || name == project.ID {
+			return
+		}
+	}
+	err = liberr.New(
+		"project not in scope.",
+		"project",
+		project.Name)
+
+	return
+}
+
 // Model adapter.
 // Provides integration between the REST resource
 // model and the inventory model.
@@ -176,6 +199,10 @@ func (r *ProjectAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	if err != nil {
 		return
 	}
+	err = ctx.inScope(clientProject)
+	if err != nil {
+		return
+	}
 	projectList = append(projectList, *clientProject)
 	list := fb.NewList()
 	for _, project := range projectList {
@@ -198,6 +225,10 @@ func (r *ProjectAdapter) GetUpdates(ctx *Context) (updates []Updater, err error)
 	if err != nil {
 		return
 	}
+	err = ctx.inScope(clientProject)
+	if err != nil {
+		return
+	}
 	projectList = append(projectList, *clientProject)
 	for i := range projectList {
 		project := &Project{projectList[i]}
@@ -267,7 +267,8 @@ func (r *VMEventHandler) validate(VM *model.VM) (err error) {
 		Ref: refapi.Ref{
 			ID: VM.ID,
 		},
-		Workload: r.workload,
+		Namespace: r.Provider.Namespace,
+		Workload:  r.workload,
 	}
 	r.log.V(4).Info(
 		"Validate VM.",
@@ -248,6 +248,7 @@ func (r *VMEventHandler) validate(VM *model.VM) (err error) {
 		Ref: refapi.Ref{
 			ID: VM.ID,
 		},
+		Namespace: r.Provider.Namespace,
 	}
 	r.log.V(4).Info(
 		"Validate VM.",
@@ -169,7 +169,10 @@ func parseVersion(fullVersion string) (major, minor, build, revision string) {
 func (r *Collector) Start() error {
 	ctx := Context{
 		client: r.client,
-		log:    r.log,
+		scope: &Scope{
+			Names: r.provider.Scope(),
+		},
+		log: r.log,
 	}
 	ctx.ctx, r.cancel = context.WithCancel(context.Background())
 	start := func() {
@@ -125,6 +125,8 @@ type Context struct {
 	ctx context.Context
 	// oVirt client.
 	client *Client
+	// Collection scope.
+	scope *Scope
 	// Log.
 	log logging.LevelLogger
 }
@@ -293,6 +295,10 @@ func (r *NetworkAdapter) Event() []int {
 
 // List the collection.
 func (r *NetworkAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	networks, err := ctx.scopedNetworks()
+	if err != nil {
+		return
+	}
 	networkList := NetworkList{}
 	err = ctx.client.list("networks", &networkList, r.follow())
 	if err != nil {
@@ -300,6 +306,9 @@ func (r *NetworkAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	}
 	list := fb.NewList()
 	for _, object := range networkList.Items {
+		if networks != nil && !networks[object.ID] {
+			continue
+		}
 		m := &model.Network{
 			Base: model.Base{ID: object.ID},
 		}
@@ -529,8 +538,15 @@ func (r *StorageDomainAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	if err != nil {
 		return
 	}
+	err = ctx.admitClusters()
+	if err != nil {
+		return
+	}
 	list := fb.NewList()
 	for _, object := range sdList.Items {
+		if !object.inScope(ctx.scope) {
+			continue
+		}
 		m := &model.StorageDomain{
 			Base: model.Base{ID: object.ID},
 		}
@@ -603,13 +619,21 @@ func (r *ClusterAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 		return
 	}
 	list := fb.NewList()
-	for _, object := range clusterList.Items {
+	for i := range clusterList.Items {
+		object := &clusterList.Items[i]
+		if !ctx.scope.Admit(object) {
+			continue
+		}
 		m := &model.Cluster{
 			Base: model.Base{ID: object.ID},
 		}
 		object.ApplyTo(m)
 		list.Append(m)
 	}
+	err = ctx.scope.Validate()
+	if err != nil {
+		return
+	}
 
 	itr = list.Iter()
 
@@ -638,6 +662,9 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 			break
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Admit(object) {
+				return
+			}
 			m := &model.Cluster{
 				Base: model.Base{ID: object.ID},
 			}
@@ -652,6 +679,9 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 			break
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(object.ID) {
+				return
+			}
 			m := &model.Cluster{
 				Base: model.Base{ID: object.ID},
 			}
@@ -665,6 +695,9 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 		}
 	case USER_REMOVE_CLUSTER:
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(event.Cluster.ID) {
+				return
+			}
 			err = tx.Delete(
 				&model.Cluster{
 					Base: model.Base{ID: event.Cluster.ID},
@@ -756,6 +789,9 @@ func (r *HostAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	}
 	list := fb.NewList()
 	for _, object := range hostList.Items {
+		if !ctx.scope.Contains(object.Cluster.ID) {
+			continue
+		}
 		m := &model.Host{
 			Base: model.Base{ID: object.ID},
 		}
@@ -790,6 +826,9 @@ func (r *HostAdapter) Apply(ctx *Context, event *Event) (updater Updater, err er
 			break
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(object.Cluster.ID) {
+				return
+			}
 			m := &model.Host{
 				Base: model.Base{ID: object.ID},
 			}
@@ -808,6 +847,9 @@ func (r *HostAdapter) Apply(ctx *Context, event *Event) (updater Updater, err er
 			break
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(object.Cluster.ID) {
+				return
+			}
 			m := &model.Host{
 				Base: model.Base{ID: object.ID},
 			}
@@ -825,6 +867,9 @@ func (r *HostAdapter) Apply(ctx *Context, event *Event) (updater Updater, err er
 				&model.Host{
 					Base: model.Base{ID: event.Host.ID},
 				})
+			if errors.Is(err, libmodel.NotFound) && ctx.scope.Restricted() {
+				err = nil
+			}
 			return
 		}
 	default:
@@ -895,6 +940,9 @@ func (r *VMAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	}
 	list := fb.NewList()
 	for _, object := range vmList.Items {
+		if !ctx.scope.Contains(object.Cluster.ID) {
+			continue
+		}
 		m := &model.VM{
 			Base: model.Base{ID: object.ID},
 		}
@@ -930,6 +978,9 @@ func (r *VMAdapter) Apply(ctx *Context, event *Event) (updater Updater, err erro
 			return
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(object.Cluster.ID) {
+				return
+			}
 			m := &model.VM{
 				Base: model.Base{ID: object.ID},
 			}
@@ -964,6 +1015,9 @@ func (r *VMAdapter) Apply(ctx *Context, event *Event) (updater Updater, err erro
 			break
 		}
 		updater = func(tx *libmodel.Tx) (err error) {
+			if !ctx.scope.Contains(object.Cluster.ID) {
+				return
+			}
 			m := &model.VM{
 				Base: model.Base{ID: object.ID},
 			}
@@ -984,6 +1038,9 @@ func (r *VMAdapter) Apply(ctx *Context, event *Event) (updater Updater, err erro
 				&model.VM{
 					Base: model.Base{ID: event.VM.ID},
 				})
+			if errors.Is(err, libmodel.NotFound) && ctx.scope.Restricted() {
+				err = nil
+			}
 			return
 		}
 	case USER_FINISHED_REMOVE_DISK_ATTACHED_TO_VMS,
@@ -1057,6 +1114,10 @@ func (r *DiskAdapter) Event() []int {
 
 // List the collection.
 func (r *DiskAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	disks, err := ctx.scopedDisks()
+	if err != nil {
+		return
+	}
 	diskList := DiskList{}
 	err = ctx.client.list("disks", &diskList)
 	if err != nil {
@@ -1064,6 +1125,9 @@ func (r *DiskAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	}
 	list := fb.NewList()
 	for _, object := range diskList.Items {
+		if disks != nil && !disks[object.ID] {
+			continue
+		}
 		if object.StorageType == "lun" {
 			err = ctx.client.list(fmt.Sprintf("disks/%s", object.ID), &object)
 			if err != nil {
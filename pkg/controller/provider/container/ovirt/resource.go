@@ -578,6 +578,20 @@ func (r *StorageDomain) ApplyTo(m *model.StorageDomain) {
 	r.setDataCenter(m)
 }
 
+// The storage domain is attached to
+// the data center of a cluster in scope.
+func (r *StorageDomain) inScope(scope *Scope) bool {
+	if !scope.Restricted() {
+		return true
+	}
+	for _, ref := range r.DataCenter.List {
+		if scope.ContainsDataCenter(ref.ID) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *StorageDomain) setDataCenter(m *model.StorageDomain) {
 	for _, ref := range r.DataCenter.List {
 		m.DataCenter = ref.ID
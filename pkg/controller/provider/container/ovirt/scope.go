@@ -0,0 +1,151 @@
+package ovirt
+
+import (
+	"fmt"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Collection scope.
+// Restricts collection to the listed clusters (by name or ID).
+// Hosts and VMs in clusters outside the scope are not collected,
+// nor are the networks not attached to a cluster in scope, the
+// storage domains not attached to the data center of a cluster
+// in scope and the disks not attached to a VM in scope.
+type Scope struct {
+	// Cluster names.
+	Names []string
+	// Admitted cluster IDs.
+	clusters map[string]bool
+	// Data centers of the admitted clusters.
+	dataCenters map[string]bool
+	// Found names.
+	found map[string]bool
+}
+
+// The scope is restricted.
+func (r *Scope) Restricted() bool {
+	return len(r.Names) > 0
+}
+
+// Admit a cluster.
+// Returns true when the cluster is in scope.
+func (r *Scope) Admit(cluster *Cluster) (admitted bool) {
+	if !r.Restricted() {
+		admitted = true
+		return
+	}
+	if r.clusters == nil {
+		r.clusters = make(map[string]bool)
+		r.dataCenters = make(map[string]bool)
+		r.found = make(map[string]bool)
+	}
+	for _, name := range r.Names {
+		if name == cluster.Name || name == cluster.ID {
+			r.clusters[cluster.ID] = true
+			r.dataCenters[cluster.DataCenter.ID] = true
+			r.found[name] = true
+			admitted = true
+			break
+		}
+	}
+
+	return
+}
+
+// The cluster (ID) is in scope.
+func (r *Scope) Contains(cluster string) bool {
+	return !r.Restricted() || r.clusters[cluster]
+}
+
+// The data center (ID) of a cluster in scope.
+func (r *Scope) ContainsDataCenter(dataCenter string) bool {
+	return !r.Restricted() || r.dataCenters[dataCenter]
+}
+
+// Validate that each listed cluster has been admitted.
+func (r *Scope) Validate() (err error) {
+	for _, name := range r.Names {
+		if !r.found[name] {
+			err = liberr.New(
+				"scope cluster not found.",
+				"cluster",
+				name)
+			return
+		}
+	}
+
+	return
+}
+
+// Admit the clusters in scope.
+// The collections listed before the clusters
+// are scoped by the admitted clusters.
+func (r *Context) admitClusters() (err error) {
+	if !r.scope.Restricted() {
+		return
+	}
+	clusterList := ClusterList{}
+	err = r.client.list("clusters", &clusterList)
+	if err != nil {
+		return
+	}
+	for i := range clusterList.Items {
+		r.scope.Admit(&clusterList.Items[i])
+	}
+
+	return
+}
+
+// Networks (IDs) attached to the clusters in scope.
+// Returns nil when the scope is not restricted.
+func (r *Context) scopedNetworks() (networks map[string]bool, err error) {
+	if !r.scope.Restricted() {
+		return
+	}
+	err = r.admitClusters()
+	if err != nil {
+		return
+	}
+	networks = make(map[string]bool)
+	for cluster := range r.scope.clusters {
+		networkList := NetworkList{}
+		err = r.client.list(fmt.Sprintf("clusters/%s/networks", cluster), &networkList)
+		if err != nil {
+			return
+		}
+		for _, network := range networkList.Items {
+			networks[network.ID] = true
+		}
+	}
+
+	return
+}
+
+// Disks (IDs) attached to the VMs in scope.
+// Returns nil when the scope is not restricted.
+func (r *Context) scopedDisks() (disks map[string]bool, err error) {
+	if !r.scope.Restricted() {
+		return
+	}
+	err = r.admitClusters()
+	if err != nil {
+		return
+	}
+	vmList := VMList{}
+	err = r.client.list("vms", &vmList, (&BaseAdapter{}).follow("disk_attachments"))
+	if err != nil {
+		return
+	}
+	disks = make(map[string]bool)
+	for _, vm := range vmList.Items {
+		if !r.scope.Contains(vm.Cluster.ID) {
+			continue
+		}
+		for _, attachment := range vm.Disks.Attachment {
+			disks[attachment.Disk.ID] = true
+		}
+	}
+
+	return
+}
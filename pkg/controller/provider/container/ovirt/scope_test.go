@@ -0,0 +1,168 @@
+package ovirt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("ovirt collection scope", func() {
+	cluster := func(id, name string) *Cluster {
+		c := &Cluster{}
+		c.ID = id
+		c.Name = name
+		return c
+	}
+	ginkgo.It("should admit all clusters when not restricted", func() {
+		scope := &Scope{}
+		Expect(scope.Admit(cluster("c1", "east"))).To(BeTrue())
+		Expect(scope.Contains("c2")).To(BeTrue())
+		Expect(scope.Validate()).To(Succeed())
+	})
+	ginkgo.It("should admit listed clusters", func() {
+		scope := &Scope{Names: []string{"east", "c3"}}
+		Expect(scope.Admit(cluster("c1", "east"))).To(BeTrue())
+		Expect(scope.Admit(cluster("c2", "west"))).To(BeFalse())
+		Expect(scope.Validate()).ToNot(Succeed())
+		Expect(scope.Admit(cluster("c3", "north"))).To(BeTrue())
+		Expect(scope.Validate()).To(Succeed())
+		Expect(scope.Contains("c1")).To(BeTrue())
+		Expect(scope.Contains("c2")).To(BeFalse())
+		Expect(scope.Contains("c3")).To(BeTrue())
+	})
+
+	ginkgo.Describe("collections", func() {
+		var server *httptest.Server
+		var ctx *Context
+
+		ginkgo.BeforeEach(func() {
+			// c1 (in scope) and c2 in dc1, c3 in dc2.
+			api := map[string]interface{}{
+				"clusters": map[string]interface{}{
+					"cluster": []interface{}{
+						map[string]interface{}{"id": "c1", "name": "east", "data_center": map[string]string{"id": "dc1"}},
+						map[string]interface{}{"id": "c2", "name": "west", "data_center": map[string]string{"id": "dc1"}},
+						map[string]interface{}{"id": "c3", "name": "north", "data_center": map[string]string{"id": "dc2"}},
+					},
+				},
+				"clusters/c1/networks": map[string]interface{}{
+					"network": []interface{}{
+						map[string]string{"id": "n1"},
+					},
+				},
+				"networks": map[string]interface{}{
+					"network": []interface{}{
+						map[string]string{"id": "n1"},
+						map[string]string{"id": "n2"},
+					},
+				},
+				"storagedomains": map[string]interface{}{
+					"storage_domain": []interface{}{
+						map[string]interface{}{"id": "sd1", "data_centers": map[string]interface{}{"data_center": []interface{}{map[string]string{"id": "dc1"}}}},
+						map[string]interface{}{"id": "sd2", "data_centers": map[string]interface{}{"data_center": []interface{}{map[string]string{"id": "dc2"}}}},
+						map[string]interface{}{"id": "sd3"},
+					},
+				},
+				"vms": map[string]interface{}{
+					"vm": []interface{}{
+						map[string]interface{}{
+							"id":      "vm1",
+							"cluster": map[string]string{"id": "c1"},
+							"disk_attachments": map[string]interface{}{
+								"disk_attachment": []interface{}{map[string]interface{}{"disk": map[string]string{"id": "d1"}}},
+							},
+						},
+						map[string]interface{}{
+							"id":      "vm2",
+							"cluster": map[string]string{"id": "c2"},
+							"disk_attachments": map[string]interface{}{
+								"disk_attachment": []interface{}{map[string]interface{}{"disk": map[string]string{"id": "d2"}}},
+							},
+						},
+					},
+				},
+				"disks": map[string]interface{}{
+					"disk": []interface{}{
+						map[string]string{"id": "d1"},
+						map[string]string{"id": "d2"},
+						map[string]string{"id": "d3"},
+					},
+				},
+			}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				content, found := api[strings.TrimPrefix(r.URL.Path, "/ovirt-engine/api/")]
+				if !found {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(content)
+			}))
+			ctx = &Context{
+				client: &Client{
+					url:    server.URL + "/ovirt-engine/api",
+					client: &libweb.Client{Transport: http.DefaultTransport},
+				},
+				scope: &Scope{Names: []string{"east"}},
+				log:   logging.WithName("test"),
+			}
+		})
+
+		ginkgo.AfterEach(func() {
+			server.Close()
+		})
+
+		ids := func(itr fb.Iterator) (ids []string) {
+			for {
+				object, hasNext := itr.Next()
+				if !hasNext {
+					break
+				}
+				switch m := object.(type) {
+				case *model.Network:
+					ids = append(ids, m.ID)
+				case *model.StorageDomain:
+					ids = append(ids, m.ID)
+				case *model.Disk:
+					ids = append(ids, m.ID)
+				}
+			}
+			return
+		}
+
+		ginkgo.It("should collect the networks attached to the clusters in scope", func() {
+			itr, err := (&NetworkAdapter{}).List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(itr)).To(ConsistOf("n1"))
+		})
+
+		ginkgo.It("should collect the storage domains of the data centers in scope", func() {
+			itr, err := (&StorageDomainAdapter{}).List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(itr)).To(ConsistOf("sd1"))
+		})
+
+		ginkgo.It("should collect the disks of the VMs in scope", func() {
+			itr, err := (&DiskAdapter{}).List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(itr)).To(ConsistOf("d1"))
+		})
+
+		ginkgo.It("should collect everything when not restricted", func() {
+			ctx.scope = &Scope{}
+			itr, err := (&DiskAdapter{}).List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(itr)).To(ConsistOf("d1", "d2", "d3"))
+			itr, err = (&NetworkAdapter{}).List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(itr)).To(ConsistOf("n1", "n2"))
+		})
+	})
+})
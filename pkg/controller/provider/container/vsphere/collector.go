@@ -2,7 +2,6 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	liburl "net/url"
 	"path"
@@ -30,7 +29,7 @@ import (
 const (
 	// Connect retry delay.
 	RetryDelay = time.Second * 5
-	// Max object in each update.
+	// Max object in each update (default page size).
 	MaxObjectUpdates = 10000
 )
 
@@ -95,6 +94,7 @@ const (
 	fCapacity    = "summary.capacity"
 	fFreeSpace   = "summary.freeSpace"
 	fDsMaintMode = "summary.maintenanceMode"
+	fDsInfo      = "info"
 	// VM
 	fUUID                = "config.uuid"
 	fFirmware            = "config.firmware"
@@ -121,6 +121,7 @@ const (
 	fSnapshot            = "snapshot"
 	fIsTemplate          = "config.template"
 	fGuestNet            = "guest.net"
+	fHostName            = "guest.hostName"
 )
 
 // Selections
@@ -335,7 +336,9 @@ func (r *Collector) Shutdown() {
 
 // Get object updates.
 //  1. connect.
-//  2. apply updates.
+//  2. build the (scoped) filter.
+//  3. apply the (paged) initial collection.
+//  4. apply updates.
 //
 // Blocks waiting on updates until canceled.
 func (r *Collector) getUpdates(ctx context.Context) error {
@@ -343,52 +346,57 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Test 1")
 	defer r.close()
-	fmt.Println("Test 11")
 	about := r.client.ServiceContent.About
-	fmt.Println(about)
 	err = r.db.Insert(
 		&model.About{
 			APIVersion: about.ApiVersion,
 			Product:    about.LicenseProductName,
 		})
-	fmt.Println("Test 13")
 	if err != nil {
 		return err
 	}
-	fmt.Println("Test 2")
+	objectSet, err := r.objectSet(ctx)
+	if err != nil {
+		return err
+	}
 	pc := property.DefaultCollector(r.client.Client)
 	pc, err = pc.Create(ctx)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
 	defer pc.Destroy(context.Background())
-	filter := r.filter(pc)
-	filter.Options.MaxObjectUpdates = MaxObjectUpdates
+	filter := r.filter(pc, objectSet)
+	filter.Options.MaxObjectUpdates = r.pageSize()
 	err = pc.CreateFilter(ctx, filter.CreateFilter)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
-	fmt.Println("Test 3")
 	mark := time.Now()
 	req := types.WaitForUpdatesEx{
 		This:    pc.Reference(),
 		Options: filter.Options,
 	}
-	fmt.Println("Test 4")
-	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
 	defer func() {
 		r.parity = false
 		for _, w := range watchList {
 			w.End()
 		}
-		if tx != nil {
-			tx.End()
-		}
 	}()
-	fmt.Println("Test 5")
+	err = r.initialize(ctx, &req)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil
+		}
+		return err
+	}
+	r.parity = true
+	r.log.Info(
+		"Initial parity.",
+		"duration",
+		time.Since(mark))
+	watchList = r.watch()
 	for {
 		response, err := methods.WaitForUpdatesEx(ctx, r.client, &req)
 		if err != nil {
@@ -404,51 +412,88 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 			}
 			return liberr.Wrap(err)
 		}
-		fmt.Println("Test 6")
 		updateSet := response.Returnval
 		if updateSet == nil {
 			continue
 		}
 		req.Version = updateSet.Version
-		tx, err = r.db.Begin()
+		err = r.applySet(ctx, updateSet)
 		if err != nil {
-			return err
+			r.log.Error(
+				err,
+				"apply changes failed.")
 		}
-		fmt.Println("Test 7")
-		for _, fs := range updateSet.FilterSet {
-			err = r.apply(ctx, tx, fs.ObjectSet)
-			if err != nil {
-				r.log.Error(
-					err,
-					"apply changes failed.")
-				break
-			}
+	}
+
+	return nil
+}
+
+// Initial collection.
+// The initial update set is paged: each call returns at most
+// one page (page size) of objects which is applied and committed
+// before the next page is requested. At most one page of the
+// inventory is held in memory. The request version is updated
+// so that waiting on the request returns only later changes.
+func (r *Collector) initialize(ctx context.Context, req *types.WaitForUpdatesEx) (err error) {
+	noWait := int32(0)
+	options := *req.Options
+	options.MaxWaitSeconds = &noWait
+	page := types.WaitForUpdatesEx{
+		This:    req.This,
+		Options: &options,
+		Version: req.Version,
+	}
+	for n := 1; ; n++ {
+		response, wErr := methods.WaitForUpdatesEx(ctx, r.client, &page)
+		if wErr != nil {
+			err = liberr.Wrap(wErr)
+			return
 		}
-		fmt.Println("Test 8")
-		if err == nil {
-			err = tx.Commit()
-		} else {
-			err = tx.End()
+		updateSet := response.Returnval
+		if updateSet == nil {
+			break
 		}
+		page.Version = updateSet.Version
+		err = r.applySet(ctx, updateSet)
 		if err != nil {
-			r.log.Error(
-				err,
-				"tx commit failed.")
+			return
 		}
-		fmt.Println("Test 9")
+		objects := 0
+		for _, fs := range updateSet.FilterSet {
+			objects += len(fs.ObjectSet)
+		}
+		r.log.V(3).Info(
+			"Initial page applied.",
+			"page",
+			n,
+			"objects",
+			objects)
 		if updateSet.Truncated == nil || !*updateSet.Truncated {
-			if !r.parity {
-				r.parity = true
-				r.log.Info(
-					"Initial parity.",
-					"duration",
-					time.Since(mark))
-				watchList = r.watch()
-			}
+			break
 		}
 	}
+	req.Version = page.Version
+	return
+}
 
-	return nil
+// Apply an update set.
+// The updates are applied in a single transaction.
+func (r *Collector) applySet(ctx context.Context, updateSet *types.UpdateSet) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	for _, fs := range updateSet.FilterSet {
+		err = r.apply(ctx, tx, fs.ObjectSet)
+		if err != nil {
+			return
+		}
+	}
+	err = tx.Commit()
+	return
 }
 
 // Add model watches.
@@ -583,21 +628,44 @@ func (r *Collector) getInsecureSkipVerifyFlag() bool {
 }
 
 // Build the object Spec filter.
-func (r *Collector) filter(pc *property.Collector) *property.WaitFilter {
+func (r *Collector) filter(pc *property.Collector, objectSet []types.ObjectSpec) *property.WaitFilter {
 	return &property.WaitFilter{
 		CreateFilter: types.CreateFilter{
 			This: pc.Reference(),
 			Spec: types.PropertyFilterSpec{
-				ObjectSet: []types.ObjectSpec{
-					r.objectSpec(),
-				},
-				PropSet: r.propertySpec(),
+				ObjectSet: objectSet,
+				PropSet:   r.propertySpec(),
 			},
 		},
 		Options: &types.WaitOptions{},
 	}
 }
 
+// Build the object Spec set.
+// The entire inventory unless scoped.
+func (r *Collector) objectSet(ctx context.Context) (set []types.ObjectSpec, err error) {
+	scope, err := r.scope(ctx)
+	if err != nil {
+		return
+	}
+	if len(scope.Paths) == 0 {
+		set = []types.ObjectSpec{
+			r.objectSpec(),
+		}
+		return
+	}
+	set, err = scope.ObjectSet()
+	if err != nil {
+		return
+	}
+	r.log.Info(
+		"Collection scoped.",
+		"paths",
+		scope.Paths)
+
+	return
+}
+
 // Build the object Spec.
 func (r *Collector) objectSpec() types.ObjectSpec {
 	return types.ObjectSpec{
@@ -709,6 +777,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fCapacity,
 				fFreeSpace,
 				fDsMaintMode,
+				fDsInfo,
 				fHost,
 			},
 		},
@@ -735,6 +804,7 @@ func (r *Collector) vmPathSet() []string {
 		fMemorySize,
 		fDevices,
 		fGuestNet,
+		fHostName,
 		fExtraConfig,
 		fGuestName,
 		fGuestID,
@@ -1,7 +1,13 @@
 package vsphere
 
 import (
+	"context"
 	liburl "net/url"
+	"strconv"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -10,6 +16,7 @@ import (
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -35,3 +42,66 @@ var _ = Describe("vSphere collector", func() {
 		table.Entry("collect TPM from vSphere > 6.7", "7.0", ContainElements(fTpmPresent)),
 	)
 })
+
+// Property collector returning the initial update set in pages.
+type pagedCollector struct {
+	pages    [][]types.ObjectUpdate
+	versions []string
+}
+
+func (r *pagedCollector) RoundTrip(ctx context.Context, req, res soap.HasFault) (err error) {
+	request := req.(*methods.WaitForUpdatesExBody).Req
+	r.versions = append(r.versions, request.Version)
+	body := res.(*methods.WaitForUpdatesExBody)
+	body.Res = &types.WaitForUpdatesExResponse{}
+	n := len(r.versions) - 1
+	if n >= len(r.pages) {
+		return
+	}
+	truncated := n < len(r.pages)-1
+	body.Res.Returnval = &types.UpdateSet{
+		Version:   strconv.Itoa(n + 1),
+		Truncated: &truncated,
+		FilterSet: []types.PropertyFilterUpdate{
+			{ObjectSet: r.pages[n]},
+		},
+	}
+	return
+}
+
+var _ = Describe("vSphere initial collection", func() {
+	folder := func(id string) types.ObjectUpdate {
+		return types.ObjectUpdate{
+			Kind: Enter,
+			Obj:  types.ManagedObjectReference{Type: Folder, Value: id},
+		}
+	}
+	It("should apply the initial collection page by page", func() {
+		db := libmodel.New("/tmp/test-vsphere-initial.db", model.All()...)
+		Expect(db.Open(true)).To(Succeed())
+		defer func() {
+			_ = db.Close(true)
+		}()
+		pc := &pagedCollector{
+			pages: [][]types.ObjectUpdate{
+				{folder("group-1"), folder("group-2")},
+				{folder("group-3"), folder("group-4")},
+				{folder("group-5")},
+			},
+		}
+		vimClient := &vim25.Client{RoundTripper: pc}
+		collector := New(db, &api.Provider{}, nil)
+		collector.client = &govmomi.Client{Client: vimClient}
+		req := types.WaitForUpdatesEx{
+			Options: &types.WaitOptions{MaxObjectUpdates: 2},
+		}
+		err := collector.initialize(context.TODO(), &req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pc.versions).To(Equal([]string{"", "1", "2"}))
+		Expect(req.Version).To(Equal("3"))
+		Expect(req.Options.MaxWaitSeconds).To(BeNil())
+		list := []model.Folder{}
+		Expect(db.List(&list, libmodel.ListOptions{})).To(Succeed())
+		Expect(list).To(HaveLen(5))
+	})
+})
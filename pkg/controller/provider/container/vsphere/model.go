@@ -478,11 +478,29 @@ func (v *DatastoreAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.MaintenanceMode = s
 				}
+			case fDsInfo:
+				switch info := p.Val.(type) {
+				case types.VmfsDatastoreInfo:
+					v.updateBackingDevices(&info)
+				case *types.VmfsDatastoreInfo:
+					v.updateBackingDevices(info)
+				}
 			}
 		}
 	}
 }
 
+// Update the devices (NAA IDs) backing the VMFS datastore.
+func (v *DatastoreAdapter) updateBackingDevices(info *types.VmfsDatastoreInfo) {
+	devices := []string{}
+	if info.Vmfs != nil {
+		for _, extent := range info.Vmfs.Extent {
+			devices = append(devices, extent.DiskName)
+		}
+	}
+	v.model.BackingDevices = devices
+}
+
 // VM model adapter.
 type VmAdapter struct {
 	Base
@@ -585,6 +603,14 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 						v.model.GuestID = s
 					}
 				}
+			case fHostName:
+				if s, cast := p.Val.(string); cast {
+					// Reported by the guest tools only while the VM is
+					// running; the stored value is kept otherwise.
+					if s != "" {
+						v.model.HostName = s
+					}
+				}
 			case fBalloonedMemory:
 				if n, cast := p.Val.(int32); cast {
 					v.model.BalloonedMemory = n
@@ -0,0 +1,381 @@
+package vsphere
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Host/VM traversal Spec.
+var TsHostVM = &types.TraversalSpec{
+	Type: Host,
+	Path: fVm,
+}
+
+// Cluster/Host/VM traversal Spec.
+var TsClusterHostVM = &types.TraversalSpec{
+	Type: ComputeResource,
+	Path: fHost,
+	SelectSet: []types.BaseSelectionSpec{
+		TsHostVM,
+	},
+}
+
+// Inventory container.
+// A folder, datacenter or cluster.
+type Container struct {
+	// Reference.
+	Ref types.ManagedObjectReference
+	// Name.
+	Name string
+	// Parent.
+	Parent *types.ManagedObjectReference
+}
+
+// Collection scope.
+// Restricts collection to the inventory rooted at the
+// datacenters, clusters and (VM) folders found at the
+// listed inventory paths. Example: /dc1/host/cluster1.
+//   - datacenter: everything in the datacenter.
+//   - cluster: the hosts and the VMs running on them.
+//   - folder: the folders and VMs within the folder.
+//
+// Networks and datastores within a scoped datacenter are
+// always collected so VMs may be mapped. Hosts are collected
+// for folders. Folders and datacenters are collected (without
+// traversal) so that paths may be built for collected objects.
+type Scope struct {
+	// Inventory paths.
+	Paths []string
+	// Containers by reference.
+	containers map[types.ManagedObjectReference]*Container
+}
+
+// Add a container.
+func (r *Scope) Add(container *Container) {
+	if r.containers == nil {
+		r.containers = make(map[types.ManagedObjectReference]*Container)
+	}
+	r.containers[container.Ref] = container
+}
+
+// Inventory path of a container.
+// The root folder is not part of the path.
+func (r *Scope) Path(ref types.ManagedObjectReference) (path string) {
+	parts := []string{}
+	for {
+		container, found := r.containers[ref]
+		if !found || container.Parent == nil {
+			break
+		}
+		parts = append([]string{container.Name}, parts...)
+		ref = *container.Parent
+	}
+	path = "/" + strings.Join(parts, "/")
+	return
+}
+
+// Datacenter containing the container.
+func (r *Scope) Datacenter(ref types.ManagedObjectReference) (dc types.ManagedObjectReference, found bool) {
+	for {
+		container, known := r.containers[ref]
+		if !known {
+			return
+		}
+		if ref.Type == Datacenter {
+			dc = ref
+			found = true
+			return
+		}
+		if container.Parent == nil {
+			return
+		}
+		ref = *container.Parent
+	}
+}
+
+// Resolve the inventory paths.
+func (r *Scope) Roots() (roots []types.ManagedObjectReference, err error) {
+	byPath := map[string]types.ManagedObjectReference{}
+	for ref := range r.containers {
+		switch ref.Type {
+		case Datacenter, Cluster, ComputeResource, Folder:
+			byPath[r.Path(ref)] = ref
+		}
+	}
+	for _, p := range r.Paths {
+		p = "/" + strings.Trim(p, "/")
+		ref, found := byPath[p]
+		if !found {
+			err = liberr.New(
+				"scope path not found.",
+				"path",
+				p)
+			return
+		}
+		roots = append(roots, ref)
+	}
+
+	return
+}
+
+// Build the object Spec set.
+func (r *Scope) ObjectSet() (set []types.ObjectSpec, err error) {
+	roots, err := r.Roots()
+	if err != nil {
+		return
+	}
+	// Datacenter traversal (by datacenter).
+	traversal := map[types.ManagedObjectReference]map[*types.TraversalSpec]bool{}
+	for _, root := range roots {
+		dc, found := r.Datacenter(root)
+		if !found {
+			err = liberr.New(
+				"scope path must be within a datacenter.",
+				"path",
+				r.Path(root))
+			return
+		}
+		if _, found := traversal[dc]; !found {
+			traversal[dc] = map[*types.TraversalSpec]bool{}
+		}
+		switch root.Type {
+		case Datacenter:
+			traversal[dc][TsDatacenterVM] = true
+			traversal[dc][TsDatacenterHost] = true
+		case Cluster, ComputeResource:
+			set = append(
+				set,
+				types.ObjectSpec{
+					Obj: root,
+					SelectSet: []types.BaseSelectionSpec{
+						TsClusterHostVM,
+					},
+				})
+		case Folder:
+			traversal[dc][TsDatacenterHost] = true
+			set = append(
+				set,
+				types.ObjectSpec{
+					Obj: root,
+					SelectSet: []types.BaseSelectionSpec{
+						TsRootFolder,
+					},
+				})
+		}
+		traversal[dc][TsDatacenterNet] = true
+		traversal[dc][TsDatacenterDatastore] = true
+	}
+	for dc, specs := range traversal {
+		selectSet := []types.BaseSelectionSpec{
+			TsRootFolder,
+		}
+		for _, ts := range []*types.TraversalSpec{
+			TsDatacenterVM,
+			TsDatacenterHost,
+			TsDatacenterNet,
+			TsDatacenterDatastore,
+		} {
+			if specs[ts] {
+				selectSet = append(selectSet, ts)
+			}
+		}
+		set = append(
+			set,
+			types.ObjectSpec{
+				Obj:       dc,
+				SelectSet: selectSet,
+			})
+	}
+	added := map[types.ManagedObjectReference]bool{}
+	for _, spec := range set {
+		added[spec.Obj] = true
+	}
+	// Folders and datacenters containing (or contained by)
+	// the scoped datacenters.
+	for ref, container := range r.containers {
+		switch ref.Type {
+		case Folder, Datacenter:
+		default:
+			continue
+		}
+		if added[ref] {
+			continue
+		}
+		dc, found := r.Datacenter(ref)
+		if found {
+			if _, scoped := traversal[dc]; !scoped {
+				continue
+			}
+		} else if !r.contains(ref, traversal) {
+			continue
+		}
+		set = append(
+			set,
+			types.ObjectSpec{
+				Obj: container.Ref,
+			})
+	}
+
+	return
+}
+
+// The folder contains a scoped datacenter.
+func (r *Scope) contains(
+	folder types.ManagedObjectReference,
+	scoped map[types.ManagedObjectReference]map[*types.TraversalSpec]bool) bool {
+	for dc := range scoped {
+		ref := dc
+		for {
+			container, found := r.containers[ref]
+			if !found || container.Parent == nil {
+				break
+			}
+			if *container.Parent == folder {
+				return true
+			}
+			ref = *container.Parent
+		}
+	}
+
+	return false
+}
+
+// Page size.
+// Max objects in each retrieved page and update set.
+func (r *Collector) pageSize() int32 {
+	if s, found := r.provider.Spec.Settings[api.PageSize]; found {
+		n, err := strconv.Atoi(s)
+		if err == nil && n > 0 {
+			return int32(n)
+		}
+		r.log.Info(
+			"page size not valid.",
+			"setting",
+			s)
+	}
+
+	return MaxObjectUpdates
+}
+
+// Build the collection scope.
+// Resolves the scope paths using the folders, datacenters
+// and clusters found in the inventory.
+func (r *Collector) scope(ctx context.Context) (scope *Scope, err error) {
+	scope = &Scope{
+		Paths: r.provider.Scope(),
+	}
+	if len(scope.Paths) == 0 {
+		return
+	}
+	spec := types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{
+			r.objectSpec(),
+		},
+		PropSet: []types.PropertySpec{
+			{
+				Type:    Folder,
+				PathSet: []string{fName, fParent},
+			},
+			{
+				Type:    Datacenter,
+				PathSet: []string{fName, fParent},
+			},
+			{
+				Type:    ComputeResource,
+				PathSet: []string{fName, fParent},
+			},
+			{
+				Type:    Cluster,
+				PathSet: []string{fName, fParent},
+			},
+		},
+	}
+	err = r.retrieve(
+		ctx,
+		spec,
+		func(page []types.ObjectContent) (err error) {
+			for _, object := range page {
+				container := &Container{Ref: object.Obj}
+				for _, p := range object.PropSet {
+					switch p.Name {
+					case fName:
+						if s, cast := p.Val.(string); cast {
+							container.Name = s
+						}
+					case fParent:
+						if ref, cast := p.Val.(types.ManagedObjectReference); cast {
+							container.Parent = &ref
+						}
+					}
+				}
+				scope.Add(container)
+			}
+			return
+		})
+
+	return
+}
+
+// Retrieve properties.
+// Paged using RetrievePropertiesEx so that at most one
+// page of objects is held in memory.
+func (r *Collector) retrieve(
+	ctx context.Context,
+	spec types.PropertyFilterSpec,
+	fn func([]types.ObjectContent) error) (err error) {
+	//
+	pc := r.client.ServiceContent.PropertyCollector
+	response, err := methods.RetrievePropertiesEx(
+		ctx,
+		r.client,
+		&types.RetrievePropertiesEx{
+			This:    pc,
+			SpecSet: []types.PropertyFilterSpec{spec},
+			Options: types.RetrieveOptions{
+				MaxObjects: r.pageSize(),
+			},
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	result := response.Returnval
+	for result != nil {
+		err = fn(result.Objects)
+		if err != nil {
+			if result.Token != "" {
+				_, _ = methods.CancelRetrievePropertiesEx(
+					context.Background(),
+					r.client,
+					&types.CancelRetrievePropertiesEx{
+						This:  pc,
+						Token: result.Token,
+					})
+			}
+			return
+		}
+		if result.Token == "" {
+			break
+		}
+		next, nErr := methods.ContinueRetrievePropertiesEx(
+			ctx,
+			r.client,
+			&types.ContinueRetrievePropertiesEx{
+				This:  pc,
+				Token: result.Token,
+			})
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		result = &next.Returnval
+	}
+
+	return
+}
@@ -0,0 +1,113 @@
+package vsphere
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var _ = Describe("vSphere collection scope", func() {
+	ref := func(kind, id string) types.ManagedObjectReference {
+		return types.ManagedObjectReference{Type: kind, Value: id}
+	}
+	root := ref(Folder, "group-d1")
+	east := ref(Folder, "group-f1")
+	dc1 := ref(Datacenter, "datacenter-1")
+	dc2 := ref(Datacenter, "datacenter-2")
+	vmFolder := ref(Folder, "group-v1")
+	hostFolder := ref(Folder, "group-h1")
+	teamA := ref(Folder, "group-v2")
+	cluster1 := ref(Cluster, "domain-c1")
+	dc2Folder := ref(Folder, "group-v3")
+	newScope := func(paths ...string) *Scope {
+		scope := &Scope{Paths: paths}
+		add := func(r types.ManagedObjectReference, name string, parent *types.ManagedObjectReference) {
+			scope.Add(&Container{Ref: r, Name: name, Parent: parent})
+		}
+		add(root, "Datacenters", nil)
+		add(dc1, "dc1", &root)
+		add(vmFolder, "vm", &dc1)
+		add(hostFolder, "host", &dc1)
+		add(teamA, "team-a", &vmFolder)
+		add(cluster1, "cluster1", &hostFolder)
+		add(east, "east", &root)
+		add(dc2, "dc2", &east)
+		add(dc2Folder, "vm", &dc2)
+		return scope
+	}
+	objects := func(set []types.ObjectSpec) map[types.ManagedObjectReference]types.ObjectSpec {
+		found := map[types.ManagedObjectReference]types.ObjectSpec{}
+		for _, spec := range set {
+			found[spec.Obj] = spec
+		}
+		return found
+	}
+
+	It("should build inventory paths", func() {
+		scope := newScope()
+		Expect(scope.Path(root)).To(Equal("/"))
+		Expect(scope.Path(cluster1)).To(Equal("/dc1/host/cluster1"))
+		Expect(scope.Path(dc2)).To(Equal("/east/dc2"))
+		dc, found := scope.Datacenter(teamA)
+		Expect(found).To(BeTrue())
+		Expect(dc).To(Equal(dc1))
+		_, found = scope.Datacenter(east)
+		Expect(found).To(BeFalse())
+	})
+	It("should resolve scope paths", func() {
+		roots, err := newScope("/dc1/vm/team-a/", "east/dc2").Roots()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roots).To(Equal([]types.ManagedObjectReference{teamA, dc2}))
+		_, err = newScope("/dc1/host/missing").Roots()
+		Expect(err).To(HaveOccurred())
+	})
+	It("should reject scope outside a datacenter", func() {
+		_, err := newScope("/east").ObjectSet()
+		Expect(err).To(HaveOccurred())
+	})
+	It("should scope by cluster", func() {
+		set, err := newScope("/dc1/host/cluster1").ObjectSet()
+		Expect(err).ToNot(HaveOccurred())
+		found := objects(set)
+		Expect(found).To(HaveKey(cluster1))
+		Expect(found[cluster1].Skip).To(BeNil())
+		Expect(found[cluster1].SelectSet).To(ConsistOf(TsClusterHostVM))
+		Expect(found[dc1].SelectSet).To(ConsistOf(
+			TsRootFolder,
+			TsDatacenterNet,
+			TsDatacenterDatastore))
+		Expect(found).To(HaveKey(root))
+		Expect(found).To(HaveKey(vmFolder))
+		Expect(found).To(HaveKey(teamA))
+		Expect(found).ToNot(HaveKey(east))
+		Expect(found).ToNot(HaveKey(dc2))
+		Expect(found).ToNot(HaveKey(dc2Folder))
+	})
+	It("should scope by folder", func() {
+		set, err := newScope("/dc1/vm/team-a").ObjectSet()
+		Expect(err).ToNot(HaveOccurred())
+		found := objects(set)
+		Expect(found[dc1].SelectSet).To(ConsistOf(
+			TsRootFolder,
+			TsDatacenterHost,
+			TsDatacenterNet,
+			TsDatacenterDatastore))
+		Expect(found[teamA].SelectSet).To(ConsistOf(TsRootFolder))
+		Expect(len(set)).To(Equal(5))
+	})
+	It("should scope by datacenter", func() {
+		set, err := newScope("/east/dc2").ObjectSet()
+		Expect(err).ToNot(HaveOccurred())
+		found := objects(set)
+		Expect(found[dc2].SelectSet).To(ConsistOf(
+			TsRootFolder,
+			TsDatacenterVM,
+			TsDatacenterHost,
+			TsDatacenterNet,
+			TsDatacenterDatastore))
+		Expect(found).To(HaveKey(east))
+		Expect(found).To(HaveKey(root))
+		Expect(found).To(HaveKey(dc2Folder))
+		Expect(found).ToNot(HaveKey(vmFolder))
+	})
+})
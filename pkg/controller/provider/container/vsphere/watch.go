@@ -267,6 +267,7 @@ func (r *VMEventHandler) validate(vm *model.VM) (err error) {
 		Ref: refapi.Ref{
 			ID: vm.ID,
 		},
+		Namespace: r.Provider.Namespace,
 	}
 	r.log.V(4).Info(
 		"Validate VM.",
@@ -44,6 +44,7 @@ import (
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -85,6 +86,7 @@ func Add(mgr manager.Manager) error {
 		catalog:   &Catalog{},
 		container: container,
 		web:       web,
+		elected:   mgr.Elected(),
 	}
 
 	web.Start()
@@ -97,6 +99,8 @@ func Add(mgr manager.Manager) error {
 		controller.Options{
 			MaxConcurrentReconciles: 10,
 			Reconciler:              reconciler,
+			// Replicas not elected serve the inventory.
+			NeedLeaderElection: ptr.To(false),
 		})
 	if err != nil {
 		log.Trace(err)
@@ -131,6 +135,8 @@ type Reconciler struct {
 	catalog   *Catalog
 	container *libcontainer.Container
 	web       *libweb.WebServer
+	// Closed when elected.
+	elected <-chan struct{}
 }
 
 // Reconcile a Inventory CR.
@@ -157,9 +163,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 			r.Log.Info("Provider deleted.")
 			err = nil
 			if deleted, found := r.catalog.get(request); found {
-				if r, found := r.container.Delete(deleted); found {
-					r.Shutdown()
-					_ = r.DB().Close(true)
+				if collector, found := r.container.Delete(deleted); found {
+					collector.Shutdown()
+					_ = collector.DB().Close(r.leader())
 				}
 			}
 		}
@@ -169,6 +175,13 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 
 	}
 
+	// Not elected.
+	if !r.leader() {
+		err = r.updateReader(provider)
+		result.RequeueAfter = base.SlowReQ
+		return
+	}
+
 	defer func() {
 		r.Log.V(2).Info("Conditions.", "all", provider.Status.Conditions)
 	}()
@@ -184,9 +197,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 
 	// Updated.
 	if !provider.HasReconciled() {
-		if r, found := r.container.Delete(provider); found {
-			r.Shutdown()
-			_ = r.DB().Close(true)
+		if collector, found := r.container.Delete(provider); found {
+			collector.Shutdown()
+			_ = collector.DB().Close(true)
 		}
 	}
 
@@ -314,8 +327,8 @@ func (r *Reconciler) updateProvider(provider *api.Provider) (err error) {
 
 // Update the container.
 func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
-	if _, found := r.container.Get(provider); found {
-		if provider.HasReconciled() {
+	if current, found := r.container.Get(provider); found {
+		if _, reader := current.(*Reader); !reader && provider.HasReconciled() {
 			r.Log.V(1).Info(
 				"Provider not reconciled, postponing.")
 			return
@@ -329,7 +342,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	}
 	log.Info("Update container.")
 	if current, found := r.container.Get(provider); found {
-		current.Shutdown()
+		r.container.Delete(provider)
 		_ = current.DB().Close(true)
 		r.Log.V(2).Info(
 			"Shutdown found collector.")
@@ -356,8 +369,58 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	return
 }
 
+// Update the reader.
+// The replicas not elected serve the inventory collected
+// by the leader into the shared DB.
+func (r *Reconciler) updateReader(provider *api.Provider) (err error) {
+	if current, found := r.container.Get(provider); found {
+		*(current.Owner().(*api.Provider)) = *provider
+		return
+	}
+	db := r.getDB(provider)
+	err = db.Open(false)
+	if err != nil {
+		return
+	}
+	err = r.container.Add(&Reader{db: db, provider: provider})
+	if err != nil {
+		return
+	}
+
+	r.Log.V(2).Info(
+		"Inventory reader added.")
+
+	return
+}
+
+// The replica is elected.
+// The leader collects the inventory, purges the DB when
+// a collector is (re)started and deletes it along with the
+// provider. Always elected when the DB is not shared.
+func (r *Reconciler) leader() bool {
+	select {
+	case <-r.elected:
+		return true
+	default:
+		return false
+	}
+}
+
 // Build DB for provider.
 func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
+	models := model.Models(provider)
+	if Settings.Inventory.Database != "" {
+		backend := &libmodel.Postgres{
+			DSN:    Settings.Inventory.Database,
+			Schema: "provider_" + string(provider.UID),
+		}
+		db = libmodel.NewWith(backend, models...)
+		r.Log.Info(
+			"Opening DB.",
+			"schema",
+			backend.String())
+		return
+	}
 	dir := Settings.Inventory.WorkingDir
 	dir = filepath.Join(
 		dir,
@@ -366,7 +429,6 @@ func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	_ = os.MkdirAll(dir, 0755)
 	file := string(provider.UID) + ".db"
 	path := filepath.Join(dir, file)
-	models := model.Models(provider)
 	db = libmodel.New(path, models...)
 	r.Log.Info(
 		"Opening DB.",
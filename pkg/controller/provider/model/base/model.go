@@ -2,6 +2,8 @@ package base
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 )
@@ -43,9 +45,42 @@ func (r InvalidKindError) Error() string {
 	return fmt.Sprintf("Kind %#v not valid.", r.Object)
 }
 
+// Concern categories.
+const (
+	ConcernCritical    = "Critical"
+	ConcernWarning     = "Warning"
+	ConcernInformation = "Information"
+)
+
+// Invalid concern ID characters.
+var ConcernIDRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
 // VM concerns.
 type Concern struct {
+	// Stable ID.
+	// Used to acknowledge the concern on a plan.
+	ID         string `json:"id"`
 	Label      string `json:"label"`
 	Category   string `json:"category"`
 	Assessment string `json:"assessment"`
+	// User-defined policy (namespace/name) that
+	// reported the concern.
+	Policy string `json:"policy,omitempty"`
+}
+
+// Assign the ID when not reported by the policy.
+// Derived from the label (and the user-defined policy)
+// so that it is stable across validations.
+func (r *Concern) AssignID() {
+	if r.ID != "" {
+		return
+	}
+	r.ID = strings.Trim(
+		ConcernIDRegex.ReplaceAllString(
+			strings.ToLower(r.Label),
+			"-"),
+		"-")
+	if r.Policy != "" {
+		r.ID = r.Policy + "/" + r.ID
+	}
 }
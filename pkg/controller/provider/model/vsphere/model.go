@@ -223,10 +223,11 @@ type DVSHost struct {
 
 type Datastore struct {
 	Base
-	Type            string `sql:""`
-	Capacity        int64  `sql:""`
-	Free            int64  `sql:""`
-	MaintenanceMode string `sql:""`
+	Type            string   `sql:""`
+	Capacity        int64    `sql:""`
+	Free            int64    `sql:""`
+	MaintenanceMode string   `sql:""`
+	BackingDevices  []string `sql:""`
 }
 
 type VM struct {
@@ -249,6 +250,7 @@ type VM struct {
 	MemoryMB              int32          `sql:""`
 	GuestName             string         `sql:""`
 	GuestID               string         `sql:""`
+	HostName              string         `sql:""`
 	BalloonedMemory       int32          `sql:""`
 	IpAddress             string         `sql:""`
 	NumaNodeAffinity      []string       `sql:""`
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Inventory reader.
+// Serves the inventory collected by the leader into the
+// shared DB on the replicas not elected. The reader does not
+// collect; the DB forwards the events committed by the leader
+// to the watches. Has parity when the leader reported the
+// inventory created.
+type Reader struct {
+	// DB client.
+	db libmodel.DB
+	// Provider.
+	provider *api.Provider
+}
+
+// The name.
+func (r *Reader) Name() string {
+	return r.provider.Name
+}
+
+// The provider.
+func (r *Reader) Owner() meta.Object {
+	return r.provider
+}
+
+// Start.
+// NO-OP.
+func (r *Reader) Start() error {
+	return nil
+}
+
+// Shutdown.
+// NO-OP.
+func (r *Reader) Shutdown() {
+}
+
+// The leader reported the inventory created.
+func (r *Reader) HasParity() bool {
+	return r.provider.Status.HasCondition(InventoryCreated)
+}
+
+// Get the DB.
+func (r *Reader) DB() libmodel.DB {
+	return r.db
+}
+
+// Test connection.
+// NO-OP.
+func (r *Reader) Test() (_ int, err error) {
+	return
+}
+
+// Reset.
+// NO-OP.
+func (r *Reader) Reset() {
+}
+
+// Version.
+// NO-OP.
+func (r *Reader) Version() (_, _, _, _ string, err error) {
+	return
+}
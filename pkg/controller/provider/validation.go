@@ -344,6 +344,10 @@ func (r *Reconciler) inventoryCreated(provider *api.Provider) error {
 		return nil
 	}
 	if r, found := r.container.Get(provider); found {
+		if _, reader := r.(*Reader); reader {
+			// replaced by a collector.
+			return nil
+		}
 		if r.HasParity() {
 			provider.Status.SetCondition(
 				libcnd.Condition{
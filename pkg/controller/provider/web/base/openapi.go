@@ -0,0 +1,129 @@
+package base
+
+import (
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI query parameters.
+var (
+	DetailQuery = libweb.Parameter{
+		Name:        DetailParam,
+		In:          "query",
+		Description: "Detail level: (0-N|all).",
+		Schema:      &libweb.Schema{Type: "string"},
+	}
+	NameQuery = libweb.Parameter{
+		Name:        NameParam,
+		In:          "query",
+		Description: "Filter by name (path).",
+		Schema:      &libweb.Schema{Type: "string"},
+	}
+	NsQuery = libweb.Parameter{
+		Name:        NsParam,
+		In:          "query",
+		Description: "Filter by namespace.",
+		Schema:      &libweb.Schema{Type: "string"},
+	}
+	LimitQuery = libweb.Parameter{
+		Name:        "limit",
+		In:          "query",
+		Description: "Page limit.",
+		Schema:      &libweb.Schema{Type: "integer"},
+	}
+	OffsetQuery = libweb.Parameter{
+		Name:        "offset",
+		In:          "query",
+		Description: "Page offset.",
+		Schema:      &libweb.Schema{Type: "integer"},
+	}
+)
+
+// Query parameters supported by collections.
+func ListQuery() []libweb.Parameter {
+	return []libweb.Parameter{
+		DetailQuery,
+		NameQuery,
+		LimitQuery,
+		OffsetQuery,
+	}
+}
+
+// Query parameters supported by provider collections.
+func ProviderQuery() []libweb.Parameter {
+	return []libweb.Parameter{
+		DetailQuery,
+		NsQuery,
+	}
+}
+
+// Documented provider routes.
+// The provider collection and provider (item) routes.
+func ProviderRoutes(tag, collection, item string, resource interface{}, watch bool) []libweb.Route {
+	return []libweb.Route{
+		{
+			Path:      collection,
+			Tag:       tag,
+			Operation: "ListProviders",
+			Summary:   "List " + tag + " providers.",
+			Resource:  resource,
+			List:      true,
+			Watch:     watch,
+			Query:     ProviderQuery(),
+		},
+		{
+			Path:      item,
+			Tag:       tag,
+			Operation: "GetProvider",
+			Summary:   "Get " + tag + " provider by UID.",
+			Resource:  resource,
+			Query:     []libweb.Parameter{DetailQuery},
+		},
+	}
+}
+
+// Documented resource routes.
+// The collection and (item) routes for resources of the specified kind.
+func ResourceRoutes(tag, kind, plural, collection, item string, resource interface{}, watch bool) []libweb.Route {
+	return []libweb.Route{
+		{
+			Path:      collection,
+			Tag:       tag,
+			Operation: "List" + plural,
+			Summary:   "List " + tag + " " + plural + ".",
+			Resource:  resource,
+			List:      true,
+			Watch:     watch,
+			Query:     ListQuery(),
+		},
+		{
+			Path:      item,
+			Tag:       tag,
+			Operation: "Get" + kind,
+			Summary:   "Get " + tag + " " + kind + " by ID.",
+			Resource:  resource,
+		},
+	}
+}
+
+// Documented tree route.
+func TreeRoute(tag, kind, path string, resource interface{}) libweb.Route {
+	return libweb.Route{
+		Path:      path,
+		Tag:       tag,
+		Operation: kind + "Tree",
+		Summary:   "Get the " + tag + " " + kind + " tree.",
+		Resource:  resource,
+		Query:     []libweb.Parameter{DetailQuery},
+	}
+}
+
+// Documented workload route.
+func WorkloadRoute(tag, path string, resource interface{}) libweb.Route {
+	return libweb.Route{
+		Path:      path,
+		Tag:       tag,
+		Operation: "GetWorkload",
+		Summary:   "Get " + tag + " workload (VM) by ID.",
+		Resource:  resource,
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// Query parameter.
+type Param = libweb.Param
+
+// Path parameters.
+type Params = base.Params
+
+// Detail level query parameter.
+func Detail(level int) Param {
+	return Param{Key: base.DetailParam, Value: strconv.Itoa(level)}
+}
+
+// Name (filter) query parameter.
+func Name(name string) Param {
+	return Param{Key: base.NameParam, Value: name}
+}
+
+// Namespace (filter) query parameter.
+func Namespace(namespace string) Param {
+	return Param{Key: base.NsParam, Value: namespace}
+}
+
+// Page limit query parameter.
+func Limit(n int) Param {
+	return Param{Key: "limit", Value: strconv.Itoa(n)}
+}
+
+// Page offset query parameter.
+func Offset(n int) Param {
+	return Param{Key: "offset", Value: strconv.Itoa(n)}
+}
+
+// Reply status (not OK) error.
+type StatusError struct {
+	// Request URL.
+	URL string
+	// HTTP status.
+	Status int
+	// Reason (X-Reason header).
+	Reason string
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf(
+		"GET %s: %d %s",
+		e.URL,
+		e.Status,
+		http.StatusText(e.Status))
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	return msg
+}
+
+// Inventory API client.
+type Client struct {
+	// Base URL.
+	// Example: https://forklift-inventory.konveyor-forklift.svc:8443
+	URL string
+	// Bearer token.
+	Token string
+	// Transport.
+	Transport http.RoundTripper
+}
+
+// HTTP GET.
+// The path parameters are substituted and the reply
+// is decoded into `out`.  Returns StatusError when
+// the reply status is not (200) OK.
+func (r *Client) get(path string, params Params, out interface{}, query ...Param) (err error) {
+	url := strings.TrimRight(r.URL, "/") + "/" +
+		strings.TrimLeft(base.Link(path, params), "/")
+	client := libweb.Client{
+		Transport: r.Transport,
+		Header:    http.Header{},
+	}
+	if r.Token != "" {
+		client.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+	status, err := client.Get(url, out, query...)
+	if err != nil {
+		return
+	}
+	if status != http.StatusOK {
+		err = liberr.Wrap(
+			&StatusError{
+				URL:    url,
+				Status: status,
+				Reason: client.Reply.Header.Get(base.ReasonHeader),
+			})
+	}
+
+	return
+}
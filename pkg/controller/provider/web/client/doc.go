@@ -0,0 +1,15 @@
+/*
+Package client provides a typed client for the inventory API.
+
+The typed (per-provider) methods are generated from the
+documented routes. See: web.Routes().
+
+	c := client.Client{
+	    URL:   "https://forklift-inventory:8443",
+	    Token: token,
+	}
+	vms, err := c.VSphere().ListVMs(providerUID, client.Detail(1))
+*/
+package client
+
+//go:generate go run ./gen
@@ -0,0 +1,143 @@
+// Generates the typed inventory client (zz_generated.client.go)
+// from the documented inventory routes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// Files.
+const (
+	Output      = "zz_generated.client.go"
+	Boilerplate = "../../../../../hack/boilerplate.go.txt"
+)
+
+// Go type (name) by route group (tag).
+var Groups = map[string]string{
+	"openshift": "OpenShift",
+	"vsphere":   "VSphere",
+	"ovirt":     "OVirt",
+	"openstack": "OpenStack",
+	"ova":       "OVA",
+}
+
+func main() {
+	boilerplate, err := os.ReadFile(Boilerplate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	content, err := Generate(web.Routes(), boilerplate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = os.WriteFile(Output, content, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Generate the client source.
+func Generate(routes []libweb.Route, boilerplate []byte) (content []byte, err error) {
+	imports := map[string]bool{}
+	body := &bytes.Buffer{}
+	tags := []string{}
+	byTag := map[string][]libweb.Route{}
+	for _, route := range routes {
+		if _, found := byTag[route.Tag]; !found {
+			tags = append(tags, route.Tag)
+		}
+		byTag[route.Tag] = append(byTag[route.Tag], route)
+	}
+	for _, tag := range tags {
+		receiver := "Client"
+		client := "r"
+		if tag != "" {
+			receiver = Groups[tag]
+			if receiver == "" {
+				err = fmt.Errorf("group: %s not mapped", tag)
+				return
+			}
+			client = "r.client"
+			fmt.Fprintf(body, "// %s API.\n", receiver)
+			fmt.Fprintf(body, "type %s struct {\n\tclient *Client\n}\n\n", receiver)
+			fmt.Fprintf(body, "// %s API.\n", receiver)
+			fmt.Fprintf(
+				body,
+				"func (r *Client) %s() *%s {\n\treturn &%s{client: r}\n}\n\n",
+				receiver,
+				receiver,
+				receiver)
+		}
+		for _, route := range byTag[tag] {
+			rt := reflect.TypeOf(route.Resource)
+			for rt.Kind() == reflect.Ptr {
+				rt = rt.Elem()
+			}
+			alias := path.Base(rt.PkgPath())
+			imports[rt.PkgPath()] = true
+			resource := alias + "." + rt.Name()
+			args := []string{}
+			params := []string{}
+			for _, p := range route.PathParams() {
+				args = append(args, p+" string")
+				params = append(params, fmt.Sprintf("%q: %s", p, p))
+			}
+			args = append(args, "query ...Param")
+			out := "resource *" + resource
+			init := "\tresource = &" + resource + "{}\n"
+			ref := "resource"
+			if route.List {
+				out = "list []" + resource
+				init = ""
+				ref = "&list"
+			}
+			fmt.Fprintf(body, "// %s\n", route.Summary)
+			fmt.Fprintf(
+				body,
+				"func (r *%s) %s(%s) (%s, err error) {\n",
+				receiver,
+				route.Operation,
+				strings.Join(args, ", "),
+				out)
+			body.WriteString(init)
+			fmt.Fprintf(
+				body,
+				"\terr = %s.get(\n\t\t%q,\n\t\tParams{%s},\n\t\t%s,\n\t\tquery...)\n",
+				client,
+				"/"+strings.TrimLeft(route.Path, "/"),
+				strings.Join(params, ", "),
+				ref)
+			body.WriteString("\treturn\n}\n\n")
+		}
+	}
+	paths := []string{}
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	header := &bytes.Buffer{}
+	header.WriteString("//go:build !ignore_autogenerated\n\n")
+	header.Write(boilerplate)
+	header.WriteString("\n\n// Code generated by gen. DO NOT EDIT.\n\n")
+	header.WriteString("package client\n\nimport (\n")
+	for _, p := range paths {
+		fmt.Fprintf(header, "\t%q\n", p)
+	}
+	header.WriteString(")\n\n")
+	header.Write(body.Bytes())
+	content, err = format.Source(header.Bytes())
+	return
+}
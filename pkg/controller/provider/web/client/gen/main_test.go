@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/onsi/gomega"
+)
+
+// The generated client is current with the documented routes.
+func TestGenerated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	boilerplate, err := os.ReadFile("../" + Boilerplate)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	generated, err := os.ReadFile("../" + Output)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	content, err := Generate(web.Routes(), boilerplate)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(string(content)).To(gomega.Equal(string(generated)))
+}
@@ -15,6 +15,9 @@ import (
 func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = []libweb.RequestHandler{
 		&libweb.SchemaHandler{},
+		&libweb.OpenAPIHandler{
+			Document: Document,
+		},
 		&ProviderHandler{
 			Handler: base.Handler{
 				Container: container,
@@ -0,0 +1,40 @@
+package ocp
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI group (tag).
+const Tag = string(api.OpenShift)
+
+// Documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = append(
+		routes,
+		base.ProviderRoutes(Tag, ProvidersRoot, ProviderRoot, Provider{}, false)...)
+	routes = append(
+		routes,
+		base.TreeRoute(Tag, "Namespace", TreeNamespaceRoot, TreeNode{}))
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Namespace", "Namespaces", NamespacesRoot, NamespaceRoot, Namespace{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "StorageClass", "StorageClasses", StorageClassesRoot, StorageClassRoot, StorageClass{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "NetworkAttachmentDefinition", "NetworkAttachmentDefinitions", NadsRoot, NadRoot, NetworkAttachmentDefinition{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "InstanceType", "InstanceTypes", InstancesRoot, InstanceRoot, InstanceType{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "ClusterInstanceType", "ClusterInstanceTypes", ClusterInstancesRoot, ClusterInstanceRoot, ClusterInstanceType{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VM", "VMs", VMsRoot, VMRoot, VM{}, true)...)
+
+	return
+}
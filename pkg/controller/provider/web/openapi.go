@@ -0,0 +1,41 @@
+package web
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI document info.
+const (
+	Title   = "Forklift Inventory"
+	Version = "v1"
+)
+
+// All documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = []libweb.Route{
+		{
+			Path:      base.ProvidersRoot,
+			Operation: "ListProviders",
+			Summary:   "List providers (by type).",
+			Resource:  Provider{},
+			Query:     base.ProviderQuery(),
+		},
+	}
+	routes = append(routes, ocp.Routes()...)
+	routes = append(routes, vsphere.Routes()...)
+	routes = append(routes, ovirt.Routes()...)
+	routes = append(routes, openstack.Routes()...)
+	routes = append(routes, ova.Routes()...)
+	return
+}
+
+// Build the OpenAPI document.
+func Document() *libweb.Document {
+	return libweb.NewDocument(Title, Version, Routes())
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/forklift-controller/pkg/lib/inventory/container"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+	"github.com/onsi/gomega"
+)
+
+func TestOpenAPI(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	for _, h := range All(container.New()) {
+		h.AddRoutes(router)
+	}
+	document := Document()
+	// Every (GET) route is documented.
+	routed := map[string]bool{}
+	for _, r := range router.Routes() {
+		g.Expect(r.Method).To(gomega.Equal("GET"))
+		if r.Path == "/schema" || r.Path == libweb.OpenAPIRoot {
+			continue
+		}
+		route := libweb.Route{Path: strings.TrimSuffix(r.Path, "/")}
+		path := route.OpenAPIPath()
+		routed[path] = true
+		g.Expect(document.Paths).To(gomega.HaveKey(path), path)
+	}
+	// Every documented path is routed.
+	operations := map[string]bool{}
+	for path, item := range document.Paths {
+		g.Expect(routed).To(gomega.HaveKey(path), path)
+		g.Expect(item.Get).ToNot(gomega.BeNil())
+		g.Expect(operations).ToNot(gomega.HaveKey(item.Get.OperationID))
+		operations[item.Get.OperationID] = true
+		for _, p := range item.Get.Parameters {
+			if p.In == "path" {
+				g.Expect(path).To(gomega.ContainSubstring("{"+p.Name+"}"), path)
+			}
+		}
+	}
+	// Every reference is resolved.
+	content, err := json.Marshal(document)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	var refs func(in interface{})
+	refs = func(in interface{}) {
+		switch v := in.(type) {
+		case map[string]interface{}:
+			for k, x := range v {
+				if k == "$ref" {
+					name := strings.TrimPrefix(x.(string), "#/components/schemas/")
+					g.Expect(document.Components.Schemas).To(gomega.HaveKey(name))
+					continue
+				}
+				refs(x)
+			}
+		case []interface{}:
+			for _, x := range v {
+				refs(x)
+			}
+		}
+	}
+	decoded := map[string]interface{}{}
+	err = json.Unmarshal(content, &decoded)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	refs(decoded)
+	// Resources.
+	vm := document.Components.Schemas["web.vsphere.VM"]
+	g.Expect(vm).ToNot(gomega.BeNil())
+	g.Expect(vm.Properties).To(gomega.HaveKey("id"))
+	g.Expect(vm.Properties).To(gomega.HaveKey("selfLink"))
+	g.Expect(vm.Properties).To(gomega.HaveKey("disks"))
+}
@@ -0,0 +1,55 @@
+package openstack
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI group (tag).
+const Tag = string(api.OpenStack)
+
+// Documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = append(
+		routes,
+		base.ProviderRoutes(Tag, ProvidersRoot, ProviderRoot, Provider{}, true)...)
+	routes = append(
+		routes,
+		base.TreeRoute(Tag, "Project", TreeProjectRoot, TreeNode{}))
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Region", "Regions", RegionsRoot, RegionRoot, Region{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Project", "Projects", ProjectsRoot, ProjectRoot, Project{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Image", "Images", ImagesRoot, ImageRoot, Image{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Flavor", "Flavors", FlavorsRoot, FlavorRoot, Flavor{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Snapshot", "Snapshots", SnapshotsRoot, SnapshotRoot, Snapshot{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Volume", "Volumes", VolumesRoot, VolumeRoot, Volume{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VolumeType", "VolumeTypes", VolumeTypesRoot, VolumeTypeRoot, VolumeType{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Network", "Networks", NetworksRoot, NetworkRoot, Network{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Subnet", "Subnets", SubnetsRoot, SubnetRoot, Subnet{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VM", "VMs", VMsRoot, VMRoot, VM{}, true)...)
+	routes = append(
+		routes,
+		base.WorkloadRoute(Tag, WorkloadRoot, Workload{}))
+
+	return
+}
@@ -0,0 +1,34 @@
+package ova
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI group (tag).
+const Tag = string(api.Ova)
+
+// Documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = append(
+		routes,
+		base.ProviderRoutes(Tag, ProvidersRoot, ProviderRoot, Provider{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Network", "Networks", NetworksRoot, NetworkRoot, Network{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Disk", "Disks", DisksRoot, DiskRoot, Disk{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Storage", "Storages", StoragesRoot, StorageRoot, Storage{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VM", "VMs", VMsRoot, VMRoot, VM{}, true)...)
+	routes = append(
+		routes,
+		base.WorkloadRoute(Tag, WorkloadRoot, Workload{}))
+
+	return
+}
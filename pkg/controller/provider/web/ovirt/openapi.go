@@ -0,0 +1,55 @@
+package ovirt
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI group (tag).
+const Tag = string(api.OVirt)
+
+// Documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = append(
+		routes,
+		base.ProviderRoutes(Tag, ProvidersRoot, ProviderRoot, Provider{}, true)...)
+	routes = append(
+		routes,
+		base.TreeRoute(Tag, "Cluster", TreeClusterRoot, TreeNode{}))
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "DataCenter", "DataCenters", DataCentersRoot, DataCenterRoot, DataCenter{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Cluster", "Clusters", ClustersRoot, ClusterRoot, Cluster{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "ServerCpu", "ServerCpus", ServerCpusRoot, ServerCpuRoot, ServerCpu{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Host", "Hosts", HostsRoot, HostRoot, Host{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Network", "Networks", NetworksRoot, NetworkRoot, Network{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "NICProfile", "NICProfiles", NICProfilesRoot, NICProfileRoot, NICProfile{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "DiskProfile", "DiskProfiles", DiskProfilesRoot, DiskProfileRoot, DiskProfile{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "StorageDomain", "StorageDomains", StorageDomainsRoot, StorageDomainRoot, StorageDomain{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Disk", "Disks", DisksRoot, DiskRoot, Disk{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VM", "VMs", VMsRoot, VMRoot, VM{}, true)...)
+	routes = append(
+		routes,
+		base.WorkloadRoute(Tag, WorkloadRoot, Workload{}))
+
+	return
+}
@@ -0,0 +1,47 @@
+package vsphere
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+)
+
+// OpenAPI group (tag).
+const Tag = string(api.VSphere)
+
+// Documented routes.
+func Routes() (routes []libweb.Route) {
+	routes = append(
+		routes,
+		base.ProviderRoutes(Tag, ProvidersRoot, ProviderRoot, Provider{}, true)...)
+	routes = append(
+		routes,
+		base.TreeRoute(Tag, "Host", TreeHostRoot, TreeNode{}),
+		base.TreeRoute(Tag, "VM", TreeVmRoot, TreeNode{}))
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Folder", "Folders", FoldersRoot, FolderRoot, Folder{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Datacenter", "Datacenters", DatacentersRoot, DatacenterRoot, Datacenter{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Cluster", "Clusters", ClustersRoot, ClusterRoot, Cluster{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Host", "Hosts", HostsRoot, HostRoot, Host{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Network", "Networks", NetworksRoot, NetworkRoot, Network{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "Datastore", "Datastores", DatastoresRoot, DatastoreRoot, Datastore{}, true)...)
+	routes = append(
+		routes,
+		base.ResourceRoutes(Tag, "VM", "VMs", VMsRoot, VMRoot, VM{}, true)...)
+	routes = append(
+		routes,
+		base.WorkloadRoute(Tag, WorkloadRoot, Workload{}))
+
+	return
+}
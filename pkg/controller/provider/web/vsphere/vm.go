@@ -226,6 +226,7 @@ type VM struct {
 	MemoryMB              int32                `json:"memoryMB"`
 	GuestName             string               `json:"guestName"`
 	GuestID               string               `json:"guestId"`
+	HostName              string               `json:"hostName"`
 	BalloonedMemory       int32                `json:"balloonedMemory"`
 	IpAddress             string               `json:"ipAddress"`
 	StorageUsed           int64                `json:"storageUsed"`
@@ -254,6 +255,7 @@ func (r *VM) With(m *model.VM) {
 	r.MemoryMB = m.MemoryMB
 	r.GuestName = m.GuestName
 	r.GuestID = m.GuestID
+	r.HostName = m.HostName
 	r.BalloonedMemory = m.BalloonedMemory
 	r.IpAddress = m.IpAddress
 	r.StorageUsed = m.StorageUsed
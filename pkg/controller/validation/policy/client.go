@@ -9,6 +9,7 @@ import (
 	"net/http"
 	liburl "net/url"
 	"os"
+	"sync"
 	"time"
 
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
@@ -30,6 +31,13 @@ var Settings = &settings.Settings
 // Pool (singleton).
 var Agent Pool
 
+// Embedded engine (singleton).
+var embedded struct {
+	Engine
+	once sync.Once
+	err  error
+}
+
 // Error reported by the service.
 type ValidationError struct {
 	Errors []string
@@ -54,6 +62,15 @@ func (r *Client) Version(path string) (version int, err error) {
 	if !r.Enabled() {
 		return
 	}
+	if Settings.PolicyAgent.Embedded {
+		engine, nErr := r.engine()
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		version, err = engine.Version(path)
+		return
+	}
 	out := &struct {
 		Result struct {
 			Version int `json:"rules_version"`
@@ -77,13 +94,25 @@ func (r *Client) Version(path string) (version int, err error) {
 }
 
 // Validate the VM.
+// The namespace (of the provider) scopes the user-defined
+// policies evaluated by the embedded engine.
 func (r *Client) Validate(
 	path string,
+	namespace string,
 	workload interface{}) (version int, concerns []model.Concern, err error) {
 	//
 	if !r.Enabled() {
 		return
 	}
+	if Settings.PolicyAgent.Embedded {
+		engine, nErr := r.engine()
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		version, concerns, err = engine.Validate(path, namespace, workload)
+		return
+	}
 	in := &struct {
 		Input interface{} `json:"input"`
 	}{}
@@ -114,6 +143,50 @@ func (r *Client) Validate(
 	return
 }
 
+// Add (or replace) a user-defined policy.
+// Supported only by the embedded engine.
+func (r *Client) PutPolicy(policy *Policy) (err error) {
+	if !Settings.PolicyAgent.Embedded {
+		err = liberr.New("embedded policy engine required.")
+		return
+	}
+	engine, err := r.engine()
+	if err != nil {
+		return
+	}
+	err = engine.Put(policy)
+	return
+}
+
+// Delete a user-defined policy.
+func (r *Client) DeletePolicy(id string) (err error) {
+	if !Settings.PolicyAgent.Embedded {
+		return
+	}
+	engine, err := r.engine()
+	if err != nil {
+		return
+	}
+	err = engine.Delete(id)
+	return
+}
+
+// The embedded engine.
+// The policies are loaded on first use.
+func (r *Client) engine() (engine *Engine, err error) {
+	embedded.once.Do(func() {
+		fsys, err := Policies()
+		if err != nil {
+			embedded.err = err
+			return
+		}
+		embedded.err = embedded.Engine.Load(fsys)
+	})
+	engine = &embedded.Engine
+	err = embedded.err
+	return
+}
+
 // Get request.
 func (r *Client) get(path string, out interface{}) (err error) {
 	parsedURL, err := liburl.Parse(Settings.PolicyAgent.URL)
@@ -216,6 +289,8 @@ type Task struct {
 	Path string
 	// VM reference.
 	Ref refapi.Ref
+	// Provider namespace.
+	Namespace string
 	// Revision number of the VM being validated.
 	Revision int64
 	// Context.
@@ -317,7 +392,10 @@ func (r *Worker) run() {
 			task.started = time.Now()
 			workload, err := task.Workload(task.Ref.ID)
 			if err == nil {
-				task.Version, task.Concerns, task.Error = r.client.Validate(task.Path, workload)
+				task.Version, task.Concerns, task.Error = r.client.Validate(task.Path, task.Namespace, workload)
+				for i := range task.Concerns {
+					task.Concerns[i].AssignID()
+				}
 				task.completed = time.Now()
 			} else {
 				task.Error = err
@@ -400,6 +478,34 @@ func (r *Pool) Version(path string) (version int, err error) {
 	return r.Client.Version(path)
 }
 
+// Validate the workload (synchronously).
+// Used to validate VMs for a plan with the destination context.
+func (r *Pool) Validate(
+	path string,
+	namespace string,
+	workload interface{}) (concerns []model.Concern, err error) {
+	//
+	_, concerns, err = r.Client.Validate(path, namespace, workload)
+	if err != nil {
+		return
+	}
+	for i := range concerns {
+		concerns[i].AssignID()
+	}
+
+	return
+}
+
+// Add (or replace) a user-defined policy.
+func (r *Pool) PutPolicy(policy *Policy) (err error) {
+	return r.Client.PutPolicy(policy)
+}
+
+// Delete a user-defined policy.
+func (r *Pool) DeletePolicy(id string) (err error) {
+	return r.Client.DeletePolicy(id)
+}
+
 // Submit validation task.
 // Queue validation request.
 func (r *Pool) Submit(task *Task) (err error) {
@@ -0,0 +1,114 @@
+package policy
+
+import (
+	"encoding/json"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Validation input (destination) key.
+const DestinationKey = "destination"
+
+// Policy package by (source) provider type.
+var Packages = map[api.ProviderType]string{
+	api.VSphere:   "vmware",
+	api.OVirt:     "ovirt",
+	api.OpenStack: "openstack",
+	api.Ova:       "ova",
+}
+
+// Validation (data API) path for the provider type.
+// Example: /v1/data/io/konveyor/forklift/vmware/validate
+func ValidationPath(providerType api.ProviderType) (path string, found bool) {
+	pkg, found := Packages[providerType]
+	if found {
+		path = DataPrefix + "io/konveyor/forklift/" + pkg + "/validate"
+	}
+
+	return
+}
+
+// Policy version (data API) path for the provider type.
+// Example: /v1/data/io/konveyor/forklift/vmware/rules_version
+func VersionPath(providerType api.ProviderType) (path string, found bool) {
+	pkg, found := Packages[providerType]
+	if found {
+		path = DataPrefix + "io/konveyor/forklift/" + pkg + "/rules_version"
+	}
+
+	return
+}
+
+// Destination (cluster) context.
+// Included in the validation input when VMs are
+// validated for a plan.
+type Destination struct {
+	// KubeVirt feature gates.
+	FeatureGates []string `json:"featureGates"`
+	// Schedulable nodes.
+	Nodes []Node `json:"nodes"`
+	// Storage classes.
+	StorageClasses []StorageClass `json:"storageClasses"`
+	// Networks (network attachment definitions).
+	Networks []Network `json:"networks"`
+}
+
+// Destination node.
+type Node struct {
+	// Name.
+	Name string `json:"name"`
+	// Allocatable CPUs.
+	CPU int64 `json:"cpu"`
+	// Allocatable memory (bytes).
+	Memory int64 `json:"memory"`
+	// Labels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Destination storage class.
+type StorageClass struct {
+	// Name.
+	Name string `json:"name"`
+	// Provisioner.
+	Provisioner string `json:"provisioner"`
+	// Default storage class.
+	Default bool `json:"default"`
+	// Supported volume modes.
+	VolumeModes []string `json:"volumeModes"`
+	// Supported access modes.
+	AccessModes []string `json:"accessModes"`
+}
+
+// Destination network.
+type Network struct {
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Name.
+	Name string `json:"name"`
+	// CNI plugin type. Example: sriov.
+	Type string `json:"type"`
+	// Device (plugin) resource name.
+	ResourceName string `json:"resourceName,omitempty"`
+}
+
+// Build the validation input.
+// The workload with the destination context added.
+func Input(workload interface{}, destination *Destination) (input map[string]interface{}, err error) {
+	b, err := json.Marshal(workload)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	input = make(map[string]interface{})
+	err = json.Unmarshal(b, &input)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if destination != nil {
+		input[DestinationKey] = destination
+	}
+
+	return
+}
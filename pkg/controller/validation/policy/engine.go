@@ -0,0 +1,523 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/validation"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// Data API (path) prefix.
+const DataPrefix = "/v1/data/"
+
+// User-defined policies (package).
+// Relative to the provider package.
+const PoliciesPackage = "policies"
+
+// Package clause pattern.
+var PackageRegex = regexp.MustCompile(`(?m)^\s*package\s+\S+\s*$`)
+
+// Invalid (package) identifier characters.
+var IdentRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Evaluation deadline.
+var EvalTimeout = time.Second * 10
+
+// Built-in functions not available to policies.
+// Policies may not reach the network or inspect
+// the controller (runtime).
+var Denied = []string{
+	"http.send",
+	"net.*",
+	"opa.runtime",
+}
+
+// Capabilities.
+// The built-in functions less the denied functions.
+func Capabilities() (capabilities *ast.Capabilities) {
+	capabilities = ast.CapabilitiesForThisVersion()
+	builtins := []*ast.Builtin{}
+	for _, builtin := range capabilities.Builtins {
+		denied := false
+		for _, pattern := range Denied {
+			if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard {
+				denied = strings.HasPrefix(builtin.Name, prefix)
+			} else {
+				denied = builtin.Name == pattern
+			}
+			if denied {
+				break
+			}
+		}
+		if !denied {
+			builtins = append(builtins, builtin)
+		}
+	}
+	capabilities.Builtins = builtins
+	capabilities.AllowNet = []string{}
+	return
+}
+
+// User-defined policy.
+type Policy struct {
+	// ID (namespace/name).
+	ID string
+	// Namespace.
+	// The policy applies to the VMs of the providers
+	// in the namespace.
+	Namespace string
+	// Provider package. Example: vmware.
+	Package string
+	// Rego rules.
+	Rego string
+}
+
+// Package (path).
+// Example: io.konveyor.forklift.vmware.policies.p_ns_name_1a2b3c4d
+func (r *Policy) path() string {
+	return strings.Join(
+		[]string{
+			"io.konveyor.forklift",
+			r.Package,
+			PoliciesPackage,
+			r.ident(),
+		},
+		".")
+}
+
+// Package identifier.
+// Suffixed with the hash of the ID which is
+// not unique once the characters are replaced.
+func (r *Policy) ident() string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(r.ID))
+	return fmt.Sprintf(
+		"p_%s_%08x",
+		IdentRegex.ReplaceAllString(r.ID, "_"),
+		h.Sum32())
+}
+
+// Parse the module.
+// The package clause is assigned.
+func (r *Policy) module() (module *ast.Module, err error) {
+	rules := PackageRegex.ReplaceAllString(r.Rego, "")
+	module, err = ast.ParseModule(
+		r.ID+".rego",
+		"package "+r.path()+"\n"+rules)
+	if err != nil {
+		err = liberr.Wrap(err, "policy", r.ID)
+	}
+
+	return
+}
+
+// Embedded policy engine.
+// Evaluates the Rego policies in-process using the same
+// (data API) paths as the policy agent.
+// User-defined policies are evaluated along with the provider
+// policies and the concerns reported are tagged with the policy.
+type Engine struct {
+	// Compiled policies.
+	compiler *ast.Compiler
+	// Prepared queries by path.
+	prepared map[string]rego.PreparedEvalQuery
+	// Provider (loaded) modules.
+	modules map[string]*ast.Module
+	// User-defined policies by ID.
+	policies map[string]*Policy
+	// Revision.
+	// Content hash of the user-defined policies.
+	revision int
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// Load (and compile) the policies.
+// Test (*_test.rego) modules are excluded.
+func (r *Engine) Load(fsys fs.FS) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	modules, err := Modules(fsys, false)
+	if err != nil {
+		return
+	}
+	r.modules = modules
+	err = r.compile(r.policies)
+	if err != nil {
+		return
+	}
+
+	log.V(1).Info(
+		"Policies loaded.",
+		"modules",
+		len(modules))
+
+	return
+}
+
+// Add (or replace) a user-defined policy.
+// The policy is not added when it does not compile.
+func (r *Engine) Put(policy *Policy) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if current, found := r.policies[policy.ID]; found {
+		if *current == *policy {
+			return
+		}
+	}
+	policies := map[string]*Policy{}
+	for id, p := range r.policies {
+		policies[id] = p
+	}
+	policies[policy.ID] = policy
+	err = r.compile(policies)
+	if err != nil {
+		return
+	}
+
+	log.V(1).Info(
+		"Policy added.",
+		"policy",
+		policy.ID,
+		"revision",
+		r.revision)
+
+	return
+}
+
+// Delete a user-defined policy.
+func (r *Engine) Delete(id string) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, found := r.policies[id]; !found {
+		return
+	}
+	policies := map[string]*Policy{}
+	for pid, p := range r.policies {
+		if pid != id {
+			policies[pid] = p
+		}
+	}
+	err = r.compile(policies)
+	if err != nil {
+		return
+	}
+
+	log.V(1).Info(
+		"Policy deleted.",
+		"policy",
+		id,
+		"revision",
+		r.revision)
+
+	return
+}
+
+// Compile the provider modules and the user-defined policies.
+// On success, the compiler, policies and revision are replaced
+// and the prepared queries discarded.
+func (r *Engine) compile(policies map[string]*Policy) (err error) {
+	modules := map[string]*ast.Module{}
+	for p, m := range r.modules {
+		modules[p] = m
+	}
+	for _, policy := range policies {
+		module, pErr := policy.module()
+		if pErr != nil {
+			err = pErr
+			return
+		}
+		modules[policy.ID] = module
+	}
+	compiler := ast.NewCompiler().WithCapabilities(Capabilities())
+	compiler.Compile(modules)
+	if compiler.Failed() {
+		err = liberr.Wrap(compiler.Errors)
+		return
+	}
+	r.compiler = compiler
+	r.policies = policies
+	r.revision = revision(policies)
+	r.prepared = make(map[string]rego.PreparedEvalQuery)
+
+	return
+}
+
+// Revision of the user-defined policies.
+// Content hash (31 bits) of the policies; zero when
+// there are no policies.
+func revision(policies map[string]*Policy) (n int) {
+	if len(policies) == 0 {
+		return
+	}
+	ids := []string{}
+	for id := range policies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	h := fnv.New32a()
+	for _, id := range ids {
+		policy := policies[id]
+		for _, s := range []string{policy.ID, policy.Namespace, policy.Package, policy.Rego} {
+			_, _ = h.Write([]byte(s))
+			_, _ = h.Write([]byte{0})
+		}
+	}
+	n = int(h.Sum32() & 0x7fffffff)
+	return
+}
+
+// Policy version.
+// The provider policies version plus the revision
+// (content hash) of the user-defined policies.
+func (r *Engine) Version(path string) (version int, err error) {
+	out := &struct {
+		Version int `json:"rules_version"`
+	}{}
+	found, err := r.eval(path, nil, out)
+	if err != nil {
+		return
+	}
+	if !found {
+		err = liberr.New(
+			"policy not found.",
+			"path",
+			path)
+		return
+	}
+
+	r.mutex.Lock()
+	version = out.Version + r.revision
+	r.mutex.Unlock()
+
+	return
+}
+
+// Validate the VM.
+// The user-defined policies in the (provider) namespace
+// are evaluated.
+func (r *Engine) Validate(
+	path string,
+	namespace string,
+	workload interface{}) (version int, concerns []model.Concern, err error) {
+	//
+	out := &struct {
+		Version  int             `json:"rules_version"`
+		Concerns []model.Concern `json:"concerns"`
+		Errors   []string        `json:"errors"`
+	}{}
+	found, err := r.eval(path, workload, out)
+	if err != nil {
+		return
+	}
+	if !found {
+		err = liberr.New(
+			"policy not found.",
+			"path",
+			path)
+		return
+	}
+	if len(out.Errors) > 0 {
+		err = liberr.Wrap(
+			&ValidationError{
+				Errors: out.Errors,
+			})
+		return
+	}
+	reported, err := r.policyConcerns(path, namespace, workload)
+	if err != nil {
+		return
+	}
+
+	concerns = append(out.Concerns, reported...)
+
+	r.mutex.Lock()
+	version = out.Version + r.revision
+	r.mutex.Unlock()
+
+	return
+}
+
+// Concerns reported by user-defined policies.
+// Evaluated using the `policies` package within the
+// package (path) of the provider policies. Concerns
+// reported by policies in other namespaces are ignored.
+func (r *Engine) policyConcerns(
+	validatePath string,
+	namespace string,
+	workload interface{}) (concerns []model.Concern, err error) {
+	//
+	policyPath := path.Join(path.Dir(validatePath), PoliciesPackage)
+	out := map[string]struct {
+		Concerns []model.Concern `json:"concerns"`
+	}{}
+	found, err := r.eval(policyPath, workload, &out)
+	if err != nil || !found {
+		return
+	}
+	r.mutex.Lock()
+	byIdent := map[string]*Policy{}
+	for _, policy := range r.policies {
+		byIdent[policy.ident()] = policy
+	}
+	r.mutex.Unlock()
+	for ident, result := range out {
+		policy, found := byIdent[ident]
+		if !found || policy.Namespace != namespace {
+			continue
+		}
+		for _, concern := range result.Concerns {
+			concern.Policy = policy.ID
+			concerns = append(concerns, concern)
+		}
+	}
+
+	return
+}
+
+// Evaluate the query for the path.
+// The input (when not nil) and the result are
+// converted using JSON. The evaluation is canceled
+// after the deadline.
+func (r *Engine) eval(path string, input interface{}, out interface{}) (found bool, err error) {
+	query, err := r.query(path)
+	if err != nil {
+		return
+	}
+	options := []rego.EvalOption{}
+	if input != nil {
+		var b []byte
+		b, err = json.Marshal(input)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		var document interface{}
+		err = json.Unmarshal(b, &document)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		options = append(options, rego.EvalInput(document))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), EvalTimeout)
+	defer cancel()
+	result, err := query.Eval(ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	if len(result) == 0 || len(result[0].Expressions) == 0 {
+		return
+	}
+	found = true
+	b, err := json.Marshal(result[0].Expressions[0].Value)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Prepared query for the path.
+// Example: /v1/data/io/konveyor/forklift/vmware/validate
+// => data.io.konveyor.forklift.vmware.validate
+func (r *Engine) query(path string) (query rego.PreparedEvalQuery, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.compiler == nil {
+		err = liberr.New("policies not loaded.")
+		return
+	}
+	query, found := r.prepared[path]
+	if found {
+		return
+	}
+	ref := strings.Trim(strings.TrimPrefix(path, DataPrefix), "/")
+	ref = "data." + strings.ReplaceAll(ref, "/", ".")
+	query, err = rego.New(
+		rego.Query(ref),
+		rego.Compiler(r.compiler)).PrepareForEval(context.TODO())
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+
+	r.prepared[path] = query
+
+	return
+}
+
+// Policy modules.
+// Parsed from the .rego files in the file system.
+func Modules(fsys fs.FS, tests bool) (modules map[string]*ast.Module, err error) {
+	modules = make(map[string]*ast.Module)
+	err = fs.WalkDir(
+		fsys,
+		".",
+		func(p string, entry fs.DirEntry, wErr error) (err error) {
+			if wErr != nil {
+				err = wErr
+				return
+			}
+			if entry.IsDir() || path.Ext(p) != ".rego" {
+				return
+			}
+			if !tests && strings.HasSuffix(p, "_test.rego") {
+				return
+			}
+			b, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return
+			}
+			module, err := ast.ParseModule(p, string(b))
+			if err != nil {
+				return
+			}
+			modules[p] = module
+			return
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Policies file system.
+// The directory when specified, else the policies
+// embedded in the controller.
+func Policies() (fsys fs.FS, err error) {
+	dir := Settings.PolicyAgent.Policies
+	if dir != "" {
+		fsys = os.DirFS(dir)
+		return
+	}
+	fsys, err = fs.Sub(validation.Policies, "policies")
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
@@ -0,0 +1,275 @@
+package policy
+
+import (
+	"context"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/open-policy-agent/opa/tester"
+)
+
+var _ = Describe("Embedded policy engine", func() {
+	fsys, err := Policies()
+	It("should find the policies", func() {
+		Expect(err).ToNot(HaveOccurred())
+	})
+	It("should pass the policy tests", func() {
+		modules, err := Modules(fsys, true)
+		Expect(err).ToNot(HaveOccurred())
+		ch, err := tester.NewRunner().Run(context.TODO(), modules)
+		Expect(err).ToNot(HaveOccurred())
+		n := 0
+		for result := range ch {
+			Expect(result.Error).ToNot(HaveOccurred(), result.String())
+			Expect(result.Fail).To(BeFalse(), result.String())
+			n++
+		}
+		Expect(n).To(BeNumerically(">", 0))
+	})
+	It("should validate", func() {
+		engine := &Engine{}
+		err := engine.Load(fsys)
+		Expect(err).ToNot(HaveOccurred())
+		version, err := engine.Version("/v1/data/io/konveyor/forklift/vmware/rules_version")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(BeNumerically(">", 0))
+		vm := map[string]interface{}{
+			"name":        "my_vm",
+			"disks":       []interface{}{},
+			"networks":    []interface{}{},
+			"devices":     []interface{}{},
+			"cpuAffinity": []interface{}{},
+		}
+		v2, concerns, err := engine.Validate("/v1/data/io/konveyor/forklift/vmware/validate", "", vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v2).To(Equal(version))
+		labels := []string{}
+		for _, c := range concerns {
+			labels = append(labels, c.Label)
+		}
+		Expect(labels).To(ContainElement("Invalid VM Name"))
+		// Errors reported.
+		_, _, err = engine.Validate("/v1/data/io/konveyor/forklift/vmware/validate", "", map[string]interface{}{})
+		Expect(err).To(HaveOccurred())
+		// Not found.
+		_, err = engine.Version("/v1/data/io/konveyor/forklift/none/rules_version")
+		Expect(err).To(HaveOccurred())
+	})
+	It("should evaluate user-defined policies", func() {
+		engine := &Engine{}
+		err := engine.Load(fsys)
+		Expect(err).ToNot(HaveOccurred())
+		versionPath := "/v1/data/io/konveyor/forklift/vmware/rules_version"
+		validatePath := "/v1/data/io/konveyor/forklift/vmware/validate"
+		version, err := engine.Version(versionPath)
+		Expect(err).ToNot(HaveOccurred())
+		// Not valid.
+		err = engine.Put(
+			&Policy{
+				ID:      "test/bad",
+				Package: "vmware",
+				Rego:    "concerns[flag] {",
+			})
+		Expect(err).To(HaveOccurred())
+		v2, err := engine.Version(versionPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v2).To(Equal(version))
+		// Valid.
+		err = engine.Put(
+			&Policy{
+				ID:        "test/name",
+				Namespace: "test",
+				Package:   "vmware",
+				Rego: `
+package ignored
+concerns[flag] {
+	input.name == "my_vm"
+	flag := {
+		"category": "Warning",
+		"label": "Reserved name",
+		"assessment": "The name is reserved."
+	}
+}`,
+			})
+		Expect(err).ToNot(HaveOccurred())
+		v2, err = engine.Version(versionPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v2).ToNot(Equal(version))
+		vm := map[string]interface{}{
+			"name":        "my_vm",
+			"disks":       []interface{}{},
+			"networks":    []interface{}{},
+			"devices":     []interface{}{},
+			"cpuAffinity": []interface{}{},
+		}
+		v3, concerns, err := engine.Validate(validatePath, "test", vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v3).To(Equal(v2))
+		policies := map[string]string{}
+		for _, c := range concerns {
+			policies[c.Label] = c.Policy
+		}
+		Expect(policies).To(HaveKeyWithValue("Reserved name", "test/name"))
+		Expect(policies).To(HaveKeyWithValue("Invalid VM Name", ""))
+		// Other namespace.
+		_, concerns, err = engine.Validate(validatePath, "other", vm)
+		Expect(err).ToNot(HaveOccurred())
+		for _, c := range concerns {
+			Expect(c.Policy).To(BeEmpty())
+		}
+		// Update not valid; the previous rules are kept.
+		err = engine.Put(
+			&Policy{
+				ID:        "test/name",
+				Namespace: "test",
+				Package:   "vmware",
+				Rego:      "concerns[flag] {",
+			})
+		Expect(err).To(HaveOccurred())
+		_, concerns, err = engine.Validate(validatePath, "test", vm)
+		Expect(err).ToNot(HaveOccurred())
+		policies = map[string]string{}
+		for _, c := range concerns {
+			policies[c.Label] = c.Policy
+		}
+		Expect(policies).To(HaveKeyWithValue("Reserved name", "test/name"))
+		// Unchanged.
+		err = engine.Put(
+			&Policy{
+				ID:        "test/name",
+				Namespace: "test",
+				Package:   "vmware",
+				Rego:      engine.policies["test/name"].Rego,
+			})
+		Expect(err).ToNot(HaveOccurred())
+		v3, _ = engine.Version(versionPath)
+		Expect(v3).To(Equal(v2))
+		// Deleted.
+		rego := engine.policies["test/name"].Rego
+		err = engine.Delete("test/name")
+		Expect(err).ToNot(HaveOccurred())
+		_, concerns, err = engine.Validate(validatePath, "test", vm)
+		Expect(err).ToNot(HaveOccurred())
+		for _, c := range concerns {
+			Expect(c.Policy).To(BeEmpty())
+		}
+		v3, _ = engine.Version(versionPath)
+		Expect(v3).To(Equal(version))
+		// Versioned by content.
+		err = engine.Put(
+			&Policy{
+				ID:        "test/name",
+				Namespace: "test",
+				Package:   "vmware",
+				Rego:      rego,
+			})
+		Expect(err).ToNot(HaveOccurred())
+		v3, _ = engine.Version(versionPath)
+		Expect(v3).To(Equal(v2))
+	})
+	It("should deny network and runtime built-ins", func() {
+		engine := &Engine{}
+		err := engine.Load(fsys)
+		Expect(err).ToNot(HaveOccurred())
+		for _, call := range []string{
+			`http.send({"method": "get", "url": "http://example.com"})`,
+			`net.lookup_ip_addr("example.com")`,
+			`opa.runtime()`,
+		} {
+			err = engine.Put(
+				&Policy{
+					ID:      "test/denied",
+					Package: "vmware",
+					Rego:    "concerns[flag] {\n\tx := " + call + "\n\tflag := x\n}",
+				})
+			Expect(err).To(HaveOccurred(), call)
+		}
+		err = engine.Put(
+			&Policy{
+				ID:      "test/allowed",
+				Package: "vmware",
+				Rego:    "concerns[flag] {\n\tcount(input.nics) > 8\n\tflag := {}\n}",
+			})
+		Expect(err).ToNot(HaveOccurred())
+	})
+	It("should cancel evaluation after the deadline", func() {
+		engine := &Engine{}
+		err := engine.Load(fsys)
+		Expect(err).ToNot(HaveOccurred())
+		err = engine.Put(
+			&Policy{
+				ID:        "test/slow",
+				Namespace: "test",
+				Package:   "vmware",
+				Rego: `
+concerns[flag] {
+	some i, j
+	numbers.range(1, 5000)[i]
+	numbers.range(1, 5000)[j]
+	i * j == -1
+	flag := {}
+}`,
+			})
+		Expect(err).ToNot(HaveOccurred())
+		timeout := EvalTimeout
+		EvalTimeout = time.Millisecond * 10
+		defer func() {
+			EvalTimeout = timeout
+		}()
+		mark := time.Now()
+		_, _, err = engine.Validate(
+			"/v1/data/io/konveyor/forklift/vmware/validate",
+			"test",
+			map[string]interface{}{
+				"name":        "test",
+				"disks":       []interface{}{},
+				"networks":    []interface{}{},
+				"devices":     []interface{}{},
+				"cpuAffinity": []interface{}{},
+			})
+		Expect(err).To(MatchError(ContainSubstring("cancel")))
+		Expect(time.Since(mark)).To(BeNumerically("<", time.Second))
+	})
+	It("should validate with the destination context", func() {
+		engine := &Engine{}
+		err := engine.Load(fsys)
+		Expect(err).ToNot(HaveOccurred())
+		path, found := ValidationPath(api.VSphere)
+		Expect(found).To(BeTrue())
+		vm := map[string]interface{}{
+			"name":        "test",
+			"cpuCount":    16,
+			"memoryMB":    1024,
+			"disks":       []interface{}{},
+			"networks":    []interface{}{},
+			"devices":     []interface{}{},
+			"cpuAffinity": []interface{}{},
+		}
+		input, err := Input(vm, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(input).ToNot(HaveKey(DestinationKey))
+		_, concerns, err := engine.Validate(path, "", input)
+		Expect(err).ToNot(HaveOccurred())
+		for _, c := range concerns {
+			Expect(c.Label).ToNot(Equal("Insufficient node CPU capacity"))
+		}
+		input, err = Input(
+			vm,
+			&Destination{
+				Nodes: []Node{
+					{Name: "n1", CPU: 8, Memory: 1 << 34},
+				},
+			})
+		Expect(err).ToNot(HaveOccurred())
+		_, concerns, err = engine.Validate(path, "", input)
+		Expect(err).ToNot(HaveOccurred())
+		labels := []string{}
+		for _, c := range concerns {
+			labels = append(labels, c.Label)
+		}
+		Expect(labels).To(ContainElement("Insufficient node CPU capacity"))
+		Expect(labels).ToNot(ContainElement("Insufficient node memory capacity"))
+	})
+})
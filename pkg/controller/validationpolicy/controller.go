@@ -0,0 +1,143 @@
+package validationpolicy
+
+import (
+	"context"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/storage/names"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// Name.
+	Name = "validationpolicy"
+)
+
+// Package logger.
+var log = logging.WithName(Name)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Creates a new ValidationPolicy Controller and adds it to the Manager.
+// The policies are loaded into the (embedded) policy engine. Changes
+// bump the policy version which triggers re-validation of the VMs.
+// A policy applies to the VMs of the providers in its namespace.
+func Add(mgr manager.Manager) error {
+	reconciler := &Reconciler{
+		Reconciler: base.Reconciler{
+			EventRecorder: mgr.GetEventRecorderFor(Name),
+			Client:        mgr.GetClient(),
+			Log:           log,
+		},
+	}
+	cnt, err := controller.New(
+		Name,
+		mgr,
+		controller.Options{
+			Reconciler: reconciler,
+		})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+	// Primary CR.
+	err = cnt.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&api.ValidationPolicy{},
+		),
+		&handler.EnqueueRequestForObject{},
+		&PolicyPredicate{})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciles a ValidationPolicy object.
+type Reconciler struct {
+	base.Reconciler
+}
+
+// Reconcile a ValidationPolicy CR.
+// Note: Must not a pointer receiver to ensure that the
+// logger and other state is not shared.
+func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, err error) {
+	r.Log = logging.WithName(
+		names.SimpleNameGenerator.GenerateName(Name+"|"),
+		"policy",
+		request)
+	r.Started()
+	defer func() {
+		result.RequeueAfter = r.Ended(
+			result.RequeueAfter,
+			err)
+		err = nil
+	}()
+
+	// Fetch the CR.
+	vp := &api.ValidationPolicy{}
+	err = r.Get(context.TODO(), request.NamespacedName, vp)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			r.Log.Info("Policy deleted.")
+			err = policy.Agent.DeletePolicy(request.String())
+		}
+		return
+	}
+	defer func() {
+		r.Log.V(2).Info("Conditions.", "all", vp.Status.Conditions)
+	}()
+
+	// Begin staging conditions.
+	vp.Status.BeginStagingConditions()
+
+	// Validations.
+	err = r.validate(vp)
+	if err != nil {
+		return
+	}
+
+	// Ready.
+	// A policy not valid is not loaded and the policy
+	// previously loaded (if any) remains in effect.
+	if !vp.Status.HasBlockerCondition() {
+		vp.Status.SetCondition(libcnd.Condition{
+			Type:     libcnd.Ready,
+			Status:   True,
+			Category: Required,
+			Message:  "The policy is ready.",
+		})
+	}
+
+	// End staging conditions.
+	vp.Status.EndStagingConditions()
+
+	// Record events.
+	r.Record(vp, vp.Status.Conditions)
+
+	// Apply changes.
+	vp.Status.ObservedGeneration = vp.Generation
+	err = r.Status().Update(context.TODO(), vp)
+	if err != nil {
+		return
+	}
+
+	// Done
+	return
+}
@@ -0,0 +1,30 @@
+package validationpolicy
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+type PolicyPredicate struct {
+	predicate.Funcs
+}
+
+func (r PolicyPredicate) Create(e event.CreateEvent) bool {
+	_, cast := e.Object.(*api.ValidationPolicy)
+	return cast
+}
+
+func (r PolicyPredicate) Update(e event.UpdateEvent) bool {
+	object, cast := e.ObjectNew.(*api.ValidationPolicy)
+	if !cast {
+		return false
+	}
+	changed := object.Status.ObservedGeneration < object.Generation
+	return changed
+}
+
+func (r PolicyPredicate) Delete(e event.DeleteEvent) bool {
+	_, cast := e.Object.(*api.ValidationPolicy)
+	return cast
+}
@@ -0,0 +1,85 @@
+package validationpolicy
+
+import (
+	"path"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+)
+
+// Types
+const (
+	EngineNotEmbedded = "EngineNotEmbedded"
+	TypeNotValid      = "ProviderTypeNotSupported"
+	RegoNotValid      = "RegoNotValid"
+)
+
+// Categories
+const (
+	Required = libcnd.Required
+	Advisory = libcnd.Advisory
+	Critical = libcnd.Critical
+	Error    = libcnd.Error
+	Warn     = libcnd.Warn
+)
+
+// Reasons
+const (
+	NotSet       = "NotSet"
+	NotSupported = "NotSupported"
+	NotValid     = "NotValid"
+)
+
+// Statuses
+const (
+	True  = libcnd.True
+	False = libcnd.False
+)
+
+// Validate the policy.
+// The rules are loaded into the policy engine when valid.
+// The rules that do not compile are reported and the rules
+// previously loaded (if any) are kept.
+func (r *Reconciler) validate(vp *api.ValidationPolicy) (err error) {
+	if !Settings.PolicyAgent.Embedded {
+		vp.Status.SetCondition(libcnd.Condition{
+			Type:     EngineNotEmbedded,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "User-defined policies require the embedded policy engine.",
+		})
+		return
+	}
+	pkg, found := policy.Packages[vp.Spec.ProviderType]
+	if !found {
+		vp.Status.SetCondition(libcnd.Condition{
+			Type:     TypeNotValid,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "The provider type is not supported.",
+		})
+		return
+	}
+	pErr := policy.Agent.PutPolicy(
+		&policy.Policy{
+			ID:        path.Join(vp.Namespace, vp.Name),
+			Namespace: vp.Namespace,
+			Package:   pkg,
+			Rego:      vp.Spec.Rego,
+		})
+	if pErr != nil {
+		vp.Status.SetCondition(libcnd.Condition{
+			Type:     RegoNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "The rego rules are not valid; the rules previously loaded remain in effect.",
+			Items:    []string{pErr.Error()},
+		})
+	}
+
+	return
+}
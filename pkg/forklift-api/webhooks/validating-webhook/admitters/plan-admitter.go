@@ -25,7 +25,7 @@ type PlanAdmitter struct {
 
 func (admitter *PlanAdmitter) validateStorage() error {
 
-	if admitter.plan.Spec.Warm {
+	if admitter.plan.SnapshotCopy() {
 		log.Info("Warm migration supports all storages, passing")
 		return nil
 	}
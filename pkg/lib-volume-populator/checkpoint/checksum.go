@@ -0,0 +1,53 @@
+package checkpoint
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// File read by the kubelet as the termination message of the populator.
+const TerminationLog = "/dev/termination-log"
+
+// Checksum of the data transferred into a volume.
+type Checksum struct {
+	// Algorithm (md5, sha1, sha256, sha384, sha512).
+	Algorithm string
+	// Hex encoded digest.
+	Digest string
+	// Bytes covered by the digest.
+	Size int64
+}
+
+// Format as "<algorithm>:<digest>:<size>".
+func (r *Checksum) String() string {
+	return fmt.Sprintf("%s:%s:%d", r.Algorithm, r.Digest, r.Size)
+}
+
+// Parse a checksum formatted by String().
+func ParseChecksum(s string) (checksum *Checksum, err error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		err = fmt.Errorf("malformed checksum: %q", s)
+		return
+	}
+	size, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		err = fmt.Errorf("malformed checksum size: %q", s)
+		return
+	}
+	checksum = &Checksum{
+		Algorithm: strings.ToLower(parts[0]),
+		Digest:    strings.ToLower(parts[1]),
+		Size:      size,
+	}
+	return
+}
+
+// Report the checksum as the termination message so that
+// the controller can record it on the populator CR.
+func (r *Checksum) Report(path string) (err error) {
+	err = os.WriteFile(path, []byte(r.String()), 0644)
+	return
+}
@@ -0,0 +1,34 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "termination-log")
+	checksum := &Checksum{Algorithm: "sha256", Digest: "abc123", Size: 4096}
+	if err := checksum.Report(path); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseChecksum(string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != *checksum {
+		t.Fatalf("expected %v, got %v", checksum, parsed)
+	}
+}
+
+func TestParseChecksumMalformed(t *testing.T) {
+	for _, s := range []string{"", "sha256:abc", "sha256::10", "sha256:abc:ten"} {
+		if _, err := ParseChecksum(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package checkpoint
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Deallocate the range of a file, it reads back as zeroes.
+func punchHole(file *os.File, offset, length int64) error {
+	return unix.Fallocate(int(file.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, offset, length)
+}
+
+// Zero the range of a block device without writing the zeroes.
+func zeroRange(file *os.File, offset, length int64) error {
+	r := [2]uint64{uint64(offset), uint64(length)}
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, file.Fd(), unix.BLKZEROOUT, uintptr(unsafe.Pointer(&r[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package checkpoint
+
+import (
+	"errors"
+	"os"
+)
+
+func punchHole(file *os.File, offset, length int64) error {
+	return errors.ErrUnsupported
+}
+
+func zeroRange(file *os.File, offset, length int64) error {
+	return errors.ErrUnsupported
+}
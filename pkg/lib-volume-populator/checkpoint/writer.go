@@ -0,0 +1,327 @@
+package checkpoint
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"k8s.io/klog/v2"
+)
+
+// Bytes written between checkpoints.
+const Interval = 1 << 30
+
+// Size of the blocks checked for zeroes.
+const BlockSize = 64 << 10
+
+var zeroBlock = make([]byte, BlockSize)
+
+// Returned when the transferred data does not match the expected digest.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// Writer writes a stream sequentially into a volume.
+// The written data is synced every Interval bytes and the
+// synced offset is reported with the state of the hashes
+// so that an interrupted transfer can be resumed from it.
+type Writer struct {
+	// Called with the synced offset and the (base64 encoded)
+	// state of the hashes at the offset.
+	Checkpoint func(offset int64, state string)
+	// Bytes between checkpoints.
+	Interval int64
+	// Skip writing zero blocks. File volumes are left
+	// with holes and block volumes are zeroed in place.
+	Sparse bool
+	// Digests of the volume content.
+	hashes []hash.Hash
+	file   *os.File
+	device bool
+	// Size of a file volume.
+	size   int64
+	offset int64
+	// Offset up to which the data is stored, the
+	// zeroes between it and the offset are pending.
+	written int64
+	synced  int64
+	skipped int64
+}
+
+// Open the volume for writing at the offset.
+// Resuming is only possible when the volume holds the
+// data before the offset and the state of the hashes
+// reported with the checkpoint is restored, otherwise
+// writing starts at 0.
+func Open(volumePath string, offset int64, state string, hashes ...hash.Hash) (w *Writer, err error) {
+	file, err := os.OpenFile(volumePath, os.O_RDWR|os.O_CREATE, 0650)
+	if err != nil {
+		return
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return
+	}
+	if offset < 0 || info.Mode().IsRegular() && info.Size() < offset {
+		offset = 0
+	}
+	if offset > 0 && len(hashes) > 0 {
+		if rErr := restore(hashes, state); rErr != nil {
+			klog.Warning("Cannot resume the transfer: ", rErr)
+			for _, h := range hashes {
+				h.Reset()
+			}
+			offset = 0
+		}
+	}
+	w = &Writer{
+		Interval: Interval,
+		hashes:   hashes,
+		file:     file,
+		device:   !info.Mode().IsRegular(),
+		size:     info.Size(),
+		offset:   offset,
+		written:  offset,
+		synced:   offset,
+	}
+	return
+}
+
+// Restore the state of the hashes.
+func restore(hashes []hash.Hash, state string) (err error) {
+	if state == "" {
+		err = errors.New("no hash state")
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(state)
+	if err != nil {
+		return
+	}
+	for _, h := range hashes {
+		unmarshaler, ok := h.(encoding.BinaryUnmarshaler)
+		if !ok {
+			err = fmt.Errorf("hash %T cannot be restored", h)
+			return
+		}
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			err = errors.New("malformed hash state")
+			return
+		}
+		data = data[n:]
+		err = unmarshaler.UnmarshalBinary(data[:length])
+		if err != nil {
+			return
+		}
+		data = data[length:]
+	}
+	if len(data) > 0 {
+		err = errors.New("malformed hash state")
+	}
+	return
+}
+
+// The (base64 encoded) state of the hashes.
+// Empty when a hash cannot be saved.
+func (w *Writer) State() (state string) {
+	var data []byte
+	for _, h := range w.hashes {
+		marshaler, ok := h.(encoding.BinaryMarshaler)
+		if !ok {
+			return
+		}
+		saved, err := marshaler.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = binary.AppendUvarint(data, uint64(len(saved)))
+		data = append(data, saved...)
+	}
+	state = base64.StdEncoding.EncodeToString(data)
+	return
+}
+
+// The offset of the next write.
+func (w *Writer) Offset() int64 {
+	return w.offset
+}
+
+// Bytes of zero blocks not written to the volume.
+func (w *Writer) Skipped() int64 {
+	return w.skipped
+}
+
+// Write the data at the current offset.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	for n < len(p) {
+		block := p[n:]
+		if w.Sparse {
+			size := BlockSize - w.offset%BlockSize
+			if int64(len(block)) > size {
+				block = block[:size]
+			}
+			if bytes.Equal(block, zeroBlock[:len(block)]) {
+				w.offset += int64(len(block))
+				n += len(block)
+				continue
+			}
+		}
+		err = w.flushZeroes()
+		if err != nil {
+			break
+		}
+		var m int
+		m, err = w.write(block, w.offset)
+		w.offset += int64(m)
+		w.written = w.offset
+		n += m
+		if err != nil {
+			break
+		}
+	}
+	for _, h := range w.hashes {
+		_, _ = h.Write(p[:n])
+	}
+	if err != nil {
+		return
+	}
+	if w.offset-w.synced >= w.Interval {
+		err = w.Sync()
+	}
+	return
+}
+
+// Store the pending zeroes.
+func (w *Writer) flushZeroes() (err error) {
+	offset, length := w.written, w.offset-w.written
+	if length == 0 {
+		return
+	}
+	if w.device {
+		err = zeroRange(w.file, offset, length)
+	} else if offset < w.size {
+		// Past the end of the file are holes already.
+		err = punchHole(w.file, offset, min(length, w.size-offset))
+	}
+	if err != nil {
+		err = w.writeZeroes(offset, length)
+		if err != nil {
+			return
+		}
+	} else {
+		w.skipped += length
+	}
+	if !w.device && w.size < w.offset {
+		err = w.file.Truncate(w.offset)
+		if err != nil {
+			return
+		}
+		w.size = w.offset
+	}
+	w.written = w.offset
+	return
+}
+
+// Write zeroes when the volume cannot zero the range.
+func (w *Writer) writeZeroes(offset, length int64) (err error) {
+	for length > 0 {
+		block := zeroBlock[:min(length, BlockSize)]
+		var n int
+		n, err = w.write(block, offset)
+		if err != nil {
+			return
+		}
+		offset += int64(n)
+		length -= int64(n)
+	}
+	return
+}
+
+func (w *Writer) write(p []byte, offset int64) (n int, err error) {
+	n, err = w.file.WriteAt(p, offset)
+	if end := offset + int64(n); !w.device && end > w.size {
+		w.size = end
+	}
+	return
+}
+
+// Sync the written data and report the checkpoint.
+func (w *Writer) Sync() (err error) {
+	err = w.flushZeroes()
+	if err != nil {
+		return
+	}
+	err = w.file.Sync()
+	if err != nil {
+		return
+	}
+	w.synced = w.offset
+	if w.Checkpoint != nil {
+		w.Checkpoint(w.synced, w.State())
+	}
+	return
+}
+
+// Sync and close the volume.
+func (w *Writer) Close() (err error) {
+	err = w.Sync()
+	if err != nil {
+		_ = w.file.Close()
+		return
+	}
+	err = w.file.Close()
+	return
+}
+
+// Digest of the data written to the volume
+// computed by the first hash.
+func (w *Writer) Sum() []byte {
+	if len(w.hashes) == 0 {
+		return nil
+	}
+	return w.hashes[0].Sum(nil)
+}
+
+// Verify the digest of the data written to the volume
+// matches the expected (hex encoded) digest.
+func (w *Writer) Verify(expected string) (err error) {
+	actual := hex.EncodeToString(w.Sum())
+	if actual != expected {
+		err = fmt.Errorf("%w: expected %s, got %s", ErrChecksum, expected, actual)
+	}
+	return
+}
+
+// Read the first size bytes of the volume back and
+// verify their digest matches the digest of the data written.
+func (w *Writer) VerifyVolume(volumePath string, h hash.Hash) (err error) {
+	actual, err := Digest(volumePath, w.offset, h)
+	if err != nil {
+		return
+	}
+	if !bytes.Equal(actual, w.Sum()) {
+		err = fmt.Errorf("%w: volume %s does not match the data transferred", ErrChecksum, volumePath)
+	}
+	return
+}
+
+// Digest of the first size bytes of the volume.
+func Digest(volumePath string, size int64, h hash.Hash) (sum []byte, err error) {
+	file, err := os.Open(volumePath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	_, err = io.CopyN(h, file, size)
+	if err != nil {
+		return
+	}
+	sum = h.Sum(nil)
+	return
+}
@@ -0,0 +1,193 @@
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriterCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	w, err := Open(path, 0, "", sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checkpoints []int64
+	w.Interval = 4
+	w.Checkpoint = func(offset int64, state string) {
+		checkpoints = append(checkpoints, offset)
+	}
+	for _, p := range []string{"abc", "def", "gh"} {
+		if _, err = w.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []int64{6, 8}
+	if len(checkpoints) != len(expected) {
+		t.Fatalf("expected checkpoints %v, got %v", expected, checkpoints)
+	}
+	for i := range expected {
+		if checkpoints[i] != expected[i] {
+			t.Fatalf("expected checkpoints %v, got %v", expected, checkpoints)
+		}
+	}
+}
+
+func TestWriterResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	w, err := Open(path, 0, "", sha256.New(), md5.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state string
+	w.Checkpoint = func(offset int64, s string) {
+		state = s
+	}
+	if _, err = w.Write([]byte("abcd")); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if state == "" {
+		t.Fatal("expected the hash state")
+	}
+	// The data before the offset is not read back.
+	if err = os.WriteFile(path, []byte("XXXXXXXX"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h := md5.New()
+	w, err = Open(path, 4, state, sha256.New(), h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Offset() != 4 {
+		t.Fatalf("expected offset 4, got %d", w.Offset())
+	}
+	if _, err = w.Write([]byte("efgh")); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, []byte("XXXXefgh")) {
+		t.Fatalf("unexpected content %q", content)
+	}
+	sum := sha256.Sum256([]byte("abcdefgh"))
+	if err = w.Verify(hex.EncodeToString(sum[:])); err != nil {
+		t.Fatal(err)
+	}
+	if md5sum := md5.Sum([]byte("abcdefgh")); !bytes.Equal(h.Sum(nil), md5sum[:]) {
+		t.Fatal("unexpected md5 digest")
+	}
+	if err = w.VerifyVolume(path, sha256.New()); !errors.Is(err, ErrChecksum) {
+		t.Fatal("expected the volume to not match")
+	}
+	if err = w.Verify("00"); !errors.Is(err, ErrChecksum) {
+		t.Fatal("expected checksum mismatch")
+	}
+}
+
+func TestWriterResumeWithoutState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, []byte("abcdXXXX"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []string{"", "AAAA", "not base64"} {
+		w, err := Open(path, 4, state, sha256.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Offset() != 0 {
+			t.Fatalf("expected offset 0, got %d", w.Offset())
+		}
+		_ = w.Close()
+	}
+}
+
+func TestWriterResumeBeyondVolume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, []byte("ab"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Open(path, 4, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Offset() != 0 {
+		t.Fatalf("expected offset 0, got %d", w.Offset())
+	}
+}
+
+func TestWriterSparse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("X"), 3*BlockSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Open(path, 0, "", sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Sparse = true
+	data := append([]byte("data"), make([]byte, 3*BlockSize)...)
+	data = append(data, []byte("end")...)
+	data = append(data, make([]byte, BlockSize)...)
+	if _, err = w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data) {
+		t.Fatal("unexpected content")
+	}
+	if w.Skipped() == 0 {
+		t.Fatal("expected zero blocks to be skipped")
+	}
+	sum := sha256.Sum256(data)
+	if err = w.Verify(hex.EncodeToString(sum[:])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriterSparseTrailingZeroes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	w, err := Open(path, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Sparse = true
+	if _, err = w.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(make([]byte, 2*BlockSize)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 2*BlockSize+4 {
+		t.Fatalf("expected size %d, got %d", 2*BlockSize+4, info.Size())
+	}
+}
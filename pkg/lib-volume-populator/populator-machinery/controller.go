@@ -31,9 +31,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/checkpoint"
+	"github.com/konveyor/forklift-controller/pkg/lib-volume-populator/relay"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -79,6 +82,17 @@ const (
 	AnnPopulatorReCreations  = "recreations"
 
 	qemuGroup = 107
+
+	vddkContainerName = "vddk-side-car"
+	vddkVolumeName    = "vddk-vol-mount"
+	vddkMountPath     = "/opt"
+
+	relayVolumeName = "relay-certs"
+
+	scratchVolumeName = "scratch"
+	scratchMountPath  = "/scratch"
+
+	httpPopulatorKind = "HttpVolumePopulator"
 )
 
 type empty struct{}
@@ -99,6 +113,16 @@ var (
 			resource:           "openstackvolumepopulators",
 			regexKey:           "openstack_volume_populator",
 		},
+		"VSphereVolumePopulator": {
+			storageResourceKey: "backing_file",
+			resource:           "vspherevolumepopulators",
+			regexKey:           "vsphere_volume_populator",
+		},
+		"HttpVolumePopulator": {
+			storageResourceKey: "url",
+			resource:           "httpvolumepopulators",
+			regexKey:           "http_volume_populator",
+		},
 	}
 
 	monitoredPVCs = map[string]interface{}{}
@@ -517,6 +541,15 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 	pvcSize := pvc.Spec.Resources.Requests.Storage().Value()
 	args = append(args, fmt.Sprintf("--pvc-size=%d", pvcSize))
 	args = append(args, fmt.Sprintf("--owner-uid=%s", pvc.UID))
+	// Resume an interrupted transfer from the last checkpoint
+	checkpoint, found, err := unstructured.NestedInt64(crInstance.Object, "status", "checkpoint")
+	if err == nil && found && checkpoint > 0 {
+		args = append(args, fmt.Sprintf("--resume-offset=%d", checkpoint))
+		state, _, _ := unstructured.NestedString(crInstance.Object, "status", "checkpointState")
+		if state != "" {
+			args = append(args, fmt.Sprintf("--resume-state=%s", state))
+		}
+	}
 
 	var waitForFirstConsumer bool
 	var nodeName string
@@ -616,6 +649,27 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 				Spec: makePopulatePodSpec(pvcPrimeName, secretName),
 			}
 			pod.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName = pvcPrimeName
+			vddkImage, found, err := unstructured.NestedString(crInstance.Object, "spec", "vddkImage")
+			if err != nil {
+				return err
+			}
+			if found && vddkImage != "" {
+				addVddkInitContainer(&pod.Spec, vddkImage)
+			}
+			relaySecret, found, err := unstructured.NestedString(crInstance.Object, "spec", "transferRelay", "secret")
+			if err != nil {
+				return err
+			}
+			if found && relaySecret != "" {
+				addRelayVolume(&pod.Spec, relaySecret)
+			}
+			scratch, err := scratchNeeded(crInstance)
+			if err != nil {
+				return err
+			}
+			if scratch {
+				addScratchVolume(&pod.Spec, pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+			}
 			con := &pod.Spec.Containers[0]
 			con.Image = c.imageName
 			con.Args = args
@@ -627,12 +681,10 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 					},
 				}
 			} else {
-				con.VolumeMounts = []corev1.VolumeMount{
-					{
-						Name:      populatorPodVolumeName,
-						MountPath: c.mountPath,
-					},
-				}
+				con.VolumeMounts = append(con.VolumeMounts, corev1.VolumeMount{
+					Name:      populatorPodVolumeName,
+					MountPath: c.mountPath,
+				})
 			}
 
 			if waitForFirstConsumer {
@@ -726,6 +778,11 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 			return fmt.Errorf("Failed to find PVC for populator pod")
 		}
 
+		err = c.recordChecksum(pod, pvc, crInstance)
+		if err != nil {
+			return err
+		}
+
 		// Get PV
 		var pv *corev1.PersistentVolume
 		c.addNotification(key, "pv", "", pvcPrime.Spec.VolumeName)
@@ -831,6 +888,7 @@ func (c *controller) updatePvc(ctx context.Context, pvc *corev1.PersistentVolume
 func (c *controller) updateProgress(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim, cr *unstructured.Unstructured) error {
 	populatorKind := pvc.Spec.DataSourceRef.Kind
 	importRegExp := regexp.MustCompile("progress\\{ownerUID=\"" + string(pvc.UID) + "\"\\} (\\d+\\.?\\d*)")
+	checkpointRegExp := regexp.MustCompile("checkpoint\\{ownerUID=\"" + string(pvc.UID) + "\"(?:,state=\"([^\"]*)\")?\\} (\\S+)")
 
 	url, err := getMetricsURL(pod)
 	if err != nil {
@@ -885,6 +943,24 @@ func (c *controller) updateProgress(pod *corev1.Pod, pvc *corev1.PersistentVolum
 		return err
 	}
 
+	if match = checkpointRegExp.FindStringSubmatch(string(body)); match != nil {
+		checkpoint, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			klog.V(5).Info("Could not convert checkpoint: ", err)
+			return err
+		}
+		err = unstructured.SetNestedField(latestPopulator.Object, int64(checkpoint), "status", "checkpoint")
+		if err != nil {
+			klog.V(5).Info("Failed to update checkpoint: ", err)
+			return err
+		}
+		err = unstructured.SetNestedField(latestPopulator.Object, match[1], "status", "checkpointState")
+		if err != nil {
+			klog.V(5).Info("Failed to update checkpoint state: ", err)
+			return err
+		}
+	}
+
 	_, err = c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Update(context.TODO(), latestPopulator, metav1.UpdateOptions{})
 	if err != nil {
 		klog.V(5).Info("Failed to update CR ", err)
@@ -898,6 +974,67 @@ func (c *controller) updateProgress(pod *corev1.Pod, pvc *corev1.PersistentVolum
 	return nil
 }
 
+// Record the checksum reported by the populator in its
+// termination message on the populator CR.
+func (c *controller) recordChecksum(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim, cr *unstructured.Unstructured) error {
+	checksum := podChecksum(pod)
+	if checksum == nil {
+		return nil
+	}
+	if _, found, _ := unstructured.NestedString(cr.Object, "status", "checksum"); found {
+		return nil
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    *pvc.Spec.DataSourceRef.APIGroup,
+		Version:  "v1beta1",
+		Resource: populatorToResource[pvc.Spec.DataSourceRef.Kind].resource,
+	}
+	latestPopulator, err := c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Get(context.TODO(), cr.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, found, _ := unstructured.NestedString(latestPopulator.Object, "status", "checksum"); found {
+		return nil
+	}
+	err = unstructured.SetNestedField(latestPopulator.Object, checksum.Algorithm+":"+checksum.Digest, "status", "checksum")
+	if err != nil {
+		return err
+	}
+	err = unstructured.SetNestedField(latestPopulator.Object, checksum.Size, "status", "checksumSize")
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Update(context.TODO(), latestPopulator, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.Info("Recorded checksum: ", checksum.String(), " for PVC: ", pvc.Name)
+
+	return nil
+}
+
+// Checksum reported in the termination message of the populator container.
+func podChecksum(pod *corev1.Pod) *checkpoint.Checksum {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != populatorContainerName || status.State.Terminated == nil {
+			continue
+		}
+		message := status.State.Terminated.Message
+		if message == "" {
+			return nil
+		}
+		checksum, err := checkpoint.ParseChecksum(message)
+		if err != nil {
+			klog.V(5).Info("Ignoring the termination message: ", err)
+			return nil
+		}
+		return checksum
+	}
+
+	return nil
+}
+
 func updatePopulatorProgress(progress int64, cr *unstructured.Unstructured) error {
 	if err := unstructured.SetNestedField(cr.Object, fmt.Sprintf("%d", progress), "status", "progress"); err != nil {
 		return err
@@ -907,7 +1044,7 @@ func updatePopulatorProgress(progress int64, cr *unstructured.Unstructured) erro
 }
 
 func makePopulatePodSpec(pvcPrimeName, secretName string) corev1.PodSpec {
-	return corev1.PodSpec{
+	spec := corev1.PodSpec{
 		Containers: []corev1.Container{
 			{
 				Name:  populatorContainerName,
@@ -920,15 +1057,6 @@ func makePopulatePodSpec(pvcPrimeName, secretName string) corev1.PodSpec {
 						Drop: []corev1.Capability{"ALL"},
 					},
 				},
-				EnvFrom: []corev1.EnvFromSource{
-					{
-						SecretRef: &corev1.SecretEnvSource{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: secretName,
-							},
-						},
-					},
-				},
 			},
 		},
 		SecurityContext: &corev1.PodSecurityContext{
@@ -949,6 +1077,128 @@ func makePopulatePodSpec(pvcPrimeName, secretName string) corev1.PodSpec {
 			},
 		},
 	}
+	// The secret is optional for some populators.
+	if secretName != "" {
+		spec.Containers[0].EnvFrom = []corev1.EnvFromSource{
+			{
+				SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+				},
+			},
+		}
+	}
+	return spec
+}
+
+// Add an init container copying the VDDK library
+// into a volume shared with the populator container.
+func addVddkInitContainer(spec *corev1.PodSpec, vddkImage string) {
+	spec.InitContainers = append(spec.InitContainers, corev1.Container{
+		Name:            vddkContainerName,
+		Image:           vddkImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      vddkVolumeName,
+				MountPath: vddkMountPath,
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: vddkVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	con := &spec.Containers[0]
+	con.VolumeMounts = append(con.VolumeMounts, corev1.VolumeMount{
+		Name:      vddkVolumeName,
+		MountPath: vddkMountPath,
+	})
+}
+
+// Mount the secret holding the mTLS material
+// used to reach the transfer relay.
+func addRelayVolume(spec *corev1.PodSpec, secretName string) {
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: relayVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	})
+	con := &spec.Containers[0]
+	con.VolumeMounts = append(con.VolumeMounts, corev1.VolumeMount{
+		Name:      relayVolumeName,
+		MountPath: relay.CertDir,
+		ReadOnly:  true,
+	})
+}
+
+// The populator downloads the image before converting it.
+// The other populators stream the images into the volume, the
+// HTTP populator downloads the images verified by the checksum,
+// read from S3 (with the credentials in the secret) or through
+// the relay.
+func scratchNeeded(cr *unstructured.Unstructured) (needed bool, err error) {
+	if cr.GetKind() != httpPopulatorKind {
+		return
+	}
+	format, _, err := unstructured.NestedString(cr.Object, "spec", "format")
+	if err != nil || format == "" || format == "raw" {
+		return
+	}
+	for _, field := range []string{"checksum", "secretName"} {
+		value, _, nErr := unstructured.NestedString(cr.Object, "spec", field)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		if value != "" {
+			needed = true
+			return
+		}
+	}
+	_, needed, err = unstructured.NestedMap(cr.Object, "spec", "transferRelay")
+	return
+}
+
+// Mount an empty dir the populator downloads the
+// image to before converting it into the volume.
+// The image is not larger than the volume, the empty
+// dir is limited to (and the ephemeral storage requested
+// for) the size of the volume.
+func addScratchVolume(spec *corev1.PodSpec, size resource.Quantity) {
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: scratchVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				SizeLimit: &size,
+			},
+		},
+	})
+	con := &spec.Containers[0]
+	if con.Resources.Requests == nil {
+		con.Resources.Requests = corev1.ResourceList{}
+	}
+	con.Resources.Requests[corev1.ResourceEphemeralStorage] = size
+	con.VolumeMounts = append(con.VolumeMounts, corev1.VolumeMount{
+		Name:      scratchVolumeName,
+		MountPath: scratchMountPath,
+	})
+	con.Env = append(con.Env, corev1.EnvVar{
+		Name:  "TMPDIR",
+		Value: scratchMountPath,
+	})
 }
 
 func (c *controller) ensureFinalizer(ctx context.Context, pvc *corev1.PersistentVolumeClaim, finalizer string, want bool) error {
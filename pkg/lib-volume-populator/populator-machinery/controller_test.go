@@ -0,0 +1,88 @@
+package populator_machinery
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPodChecksum(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: populatorContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Message: "sha256:ABCDEF:1024",
+						},
+					},
+				},
+			},
+		},
+	}
+	checksum := podChecksum(pod)
+	if checksum == nil || checksum.Algorithm != "sha256" || checksum.Digest != "abcdef" || checksum.Size != 1024 {
+		t.Errorf("Unexpected checksum %v", checksum)
+	}
+
+	pod.Status.ContainerStatuses[0].State.Terminated.Message = "panic: runtime error"
+	if checksum = podChecksum(pod); checksum != nil {
+		t.Errorf("Expected no checksum, got %v", checksum)
+	}
+
+	pod.Status.ContainerStatuses[0].State.Terminated = nil
+	if checksum = podChecksum(pod); checksum != nil {
+		t.Errorf("Expected no checksum, got %v", checksum)
+	}
+}
+
+func TestAddScratchVolume(t *testing.T) {
+	spec := makePopulatePodSpec("prime", "")
+	size := resource.MustParse("10Gi")
+	addScratchVolume(&spec, size)
+	if len(spec.Volumes) != 2 || spec.Volumes[1].EmptyDir == nil {
+		t.Fatalf("Expected the scratch volume, got %v", spec.Volumes)
+	}
+	if limit := spec.Volumes[1].EmptyDir.SizeLimit; limit == nil || limit.Cmp(size) != 0 {
+		t.Errorf("Unexpected size limit %v", limit)
+	}
+	con := spec.Containers[0]
+	if request := con.Resources.Requests[corev1.ResourceEphemeralStorage]; request.Cmp(size) != 0 {
+		t.Errorf("Unexpected ephemeral storage request %v", request)
+	}
+	if len(con.VolumeMounts) != 1 || con.VolumeMounts[0].MountPath != scratchMountPath {
+		t.Errorf("Unexpected mounts %v", con.VolumeMounts)
+	}
+	if len(con.Env) != 1 || con.Env[0].Name != "TMPDIR" || con.Env[0].Value != scratchMountPath {
+		t.Errorf("Unexpected environment %v", con.Env)
+	}
+}
+
+func TestScratchNeeded(t *testing.T) {
+	cr := func(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"kind": kind, "spec": spec}}
+	}
+	for _, tc := range []struct {
+		name   string
+		cr     *unstructured.Unstructured
+		needed bool
+	}{
+		{"streamed", cr(httpPopulatorKind, map[string]interface{}{"format": "qcow2"}), false},
+		{"raw", cr(httpPopulatorKind, map[string]interface{}{"format": "raw", "checksum": "md5:00"}), false},
+		{"checksum", cr(httpPopulatorKind, map[string]interface{}{"format": "qcow2", "checksum": "md5:00"}), true},
+		{"secret", cr(httpPopulatorKind, map[string]interface{}{"format": "qcow2", "secretName": "s3"}), true},
+		{"relay", cr(httpPopulatorKind, map[string]interface{}{"format": "qcow2", "transferRelay": map[string]interface{}{"secret": "relay"}}), true},
+		{"openstack", cr("OpenstackVolumePopulator", map[string]interface{}{"format": "qcow2", "secretName": "openstack"}), false},
+	} {
+		needed, err := scratchNeeded(tc.cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if needed != tc.needed {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.needed, needed)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// Largest read waiting for the limiter.
+const chunkSize = 64 << 10
+
+// Rate written to the nbdkit rate file when not limited,
+// nbdkit has no value for an unlimited rate (bits per second).
+const unlimitedBits = 1 << 50
+
+// Days of the week.
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// Rate during a time window (UTC).
+type Window struct {
+	// Days the window starts, every day when empty.
+	Days []time.Weekday
+	// Start, minutes after midnight.
+	Start int
+	// End, minutes after midnight. The window ends
+	// the next day when the end is not after the start.
+	End int
+	// Bytes per second. Zero is not limited.
+	Rate int64
+}
+
+// Parse a window: [<day>,...]/<HH:MM>-<HH:MM>=<rate>.
+func ParseWindow(value string) (window Window, err error) {
+	spec, rateValue, found := strings.Cut(value, "=")
+	if !found {
+		err = fmt.Errorf("window %q has no rate", value)
+		return
+	}
+	window.Rate, err = strconv.ParseInt(rateValue, 10, 64)
+	if err != nil || window.Rate < 0 {
+		err = fmt.Errorf("window %q has an invalid rate", value)
+		return
+	}
+	days, times, found := strings.Cut(spec, "/")
+	if !found {
+		times = days
+		days = ""
+	}
+	if days != "" {
+		for _, name := range strings.Split(days, ",") {
+			day, known := weekdays[name]
+			if !known {
+				err = fmt.Errorf("window %q has an invalid day %q", value, name)
+				return
+			}
+			window.Days = append(window.Days, day)
+		}
+	}
+	start, end, found := strings.Cut(times, "-")
+	if !found {
+		err = fmt.Errorf("window %q has no end", value)
+		return
+	}
+	if window.Start, err = ParseTime(start); err != nil {
+		return
+	}
+	window.End, err = ParseTime(end)
+	return
+}
+
+// Parse HH:MM into minutes after midnight.
+func ParseTime(value string) (minutes int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		err = fmt.Errorf("invalid time %q", value)
+		return
+	}
+	minutes = t.Hour()*60 + t.Minute()
+	return
+}
+
+// The window contains the time.
+func (r *Window) Contains(t time.Time) bool {
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+	if r.End > r.Start {
+		return r.startsOn(t.Weekday()) && minutes >= r.Start && minutes < r.End
+	}
+	// Spans midnight.
+	if minutes >= r.Start {
+		return r.startsOn(t.Weekday())
+	}
+	if minutes < r.End {
+		return r.startsOn((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// The window starts on the day.
+func (r *Window) startsOn(day time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, d := range r.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule of rates.
+// Implements flag.Value, each value adds a window.
+type Schedule struct {
+	// Bytes per second outside of the windows. Zero is not limited.
+	Default int64
+	// Windows.
+	Windows []Window
+}
+
+// Rate at the time.
+func (r *Schedule) Rate(t time.Time) int64 {
+	for i := range r.Windows {
+		if r.Windows[i].Contains(t) {
+			return r.Windows[i].Rate
+		}
+	}
+	return r.Default
+}
+
+// Any rate is limited.
+func (r *Schedule) Limited() bool {
+	if r == nil {
+		return false
+	}
+	if r.Default > 0 {
+		return true
+	}
+	for _, window := range r.Windows {
+		if window.Rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag value.
+func (r *Schedule) String() string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d windows", len(r.Windows))
+}
+
+// Add a window.
+func (r *Schedule) Set(value string) (err error) {
+	window, err := ParseWindow(value)
+	if err != nil {
+		return
+	}
+	r.Windows = append(r.Windows, window)
+	return
+}
+
+// Arguments enabling the nbdkit rate filter.
+// The filter options precede the plugin and the
+// parameters follow it. The rate file must be
+// maintained with Maintain().
+func (r *Schedule) NbdkitArgs(rateFile string) (options, params []string) {
+	if !r.Limited() {
+		return
+	}
+	options = []string{"--filter=rate"}
+	params = []string{
+		"rate=" + r.bits(time.Now()),
+		"rate-file=" + rateFile,
+	}
+	return
+}
+
+// Write the current rate (bits per second) to the nbdkit
+// rate file and rewrite it when the rate changes, until
+// the context is done.
+func (r *Schedule) Maintain(ctx context.Context, rateFile string) (err error) {
+	if !r.Limited() {
+		return
+	}
+	current := r.bits(time.Now())
+	err = os.WriteFile(rateFile, []byte(current+"\n"), 0600)
+	if err != nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				bits := r.bits(now)
+				if bits == current {
+					continue
+				}
+				wErr := os.WriteFile(rateFile, []byte(bits+"\n"), 0600)
+				if wErr != nil {
+					klog.Warning("Failed to update the rate file: ", wErr)
+					continue
+				}
+				klog.Info("Transfer rate (bits/s): ", bits)
+				current = bits
+			}
+		}
+	}()
+	return
+}
+
+// Rate at the time in bits per second.
+func (r *Schedule) bits(t time.Time) string {
+	bytes := r.Rate(t)
+	if bytes <= 0 {
+		return strconv.FormatInt(unlimitedBits, 10)
+	}
+	return strconv.FormatInt(bytes*8, 10)
+}
+
+// Reader limiting the read rate to the schedule.
+type Reader struct {
+	reader   io.Reader
+	schedule *Schedule
+	limiter  *rate.Limiter
+	// Rate the limiter is set to.
+	current int64
+	// Current time.
+	now func() time.Time
+}
+
+// Limit the rate of the reader.
+// Returns the reader when the schedule is not limited.
+func NewReader(reader io.Reader, schedule *Schedule) io.Reader {
+	if !schedule.Limited() {
+		return reader
+	}
+	return &Reader{
+		reader:   reader,
+		schedule: schedule,
+		limiter:  rate.NewLimiter(rate.Inf, chunkSize),
+		current:  -1,
+		now:      time.Now,
+	}
+}
+
+// Read waits for the limiter.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	bytes := r.schedule.Rate(r.now())
+	if bytes != r.current {
+		r.current = bytes
+		if bytes > 0 {
+			r.limiter.SetLimit(rate.Limit(bytes))
+			r.limiter.SetBurst(int(min(bytes, chunkSize)))
+			klog.Info("Transfer rate (bytes/s): ", bytes)
+		} else {
+			r.limiter.SetLimit(rate.Inf)
+			klog.Info("Transfer rate not limited.")
+		}
+	}
+	if bytes > 0 && len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+	n, err = r.reader.Read(p)
+	if n > 0 && bytes > 0 {
+		wErr := r.limiter.WaitN(context.Background(), n)
+		if wErr != nil && err == nil {
+			err = wErr
+		}
+	}
+	return
+}
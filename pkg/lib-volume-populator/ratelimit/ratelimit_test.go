@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	window, err := ParseWindow("Mon,Fri/09:00-17:30=1024")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if window.Start != 540 || window.End != 1050 || window.Rate != 1024 || len(window.Days) != 2 {
+		t.Errorf("Unexpected window %+v", window)
+	}
+	window, err = ParseWindow("22:00-06:00=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(window.Days) != 0 || window.Start != 1320 || window.End != 360 {
+		t.Errorf("Unexpected window %+v", window)
+	}
+	for _, value := range []string{"09:00-17:00", "Xyz/09:00-17:00=1", "09:00=1", "25:00-26:00=1", "09:00-17:00=-1"} {
+		if _, err = ParseWindow(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestScheduleRate(t *testing.T) {
+	schedule := &Schedule{Default: 100}
+	for _, value := range []string{"Mon/09:00-17:00=10", "Fri/22:00-06:00=0"} {
+		if err := schedule.Set(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2024-01-01 is a Monday.
+	for _, c := range []struct {
+		time string
+		rate int64
+	}{
+		{"2024-01-01T08:59:00Z", 100},
+		{"2024-01-01T09:00:00Z", 10},
+		{"2024-01-01T17:00:00Z", 100},
+		{"2024-01-02T09:30:00Z", 100},
+		{"2024-01-05T23:00:00Z", 0},
+		{"2024-01-06T05:59:00Z", 0},
+		{"2024-01-06T23:00:00Z", 100},
+		{"2024-01-07T01:00:00Z", 100},
+	} {
+		now, err := time.Parse(time.RFC3339, c.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rate := schedule.Rate(now); rate != c.rate {
+			t.Errorf("%s: expected %d, got %d", c.time, c.rate, rate)
+		}
+	}
+}
+
+func TestNbdkitArgs(t *testing.T) {
+	options, params := (&Schedule{}).NbdkitArgs("/tmp/rate")
+	if options != nil || params != nil {
+		t.Errorf("Expected no arguments when not limited")
+	}
+	options, params = (&Schedule{Default: 1000}).NbdkitArgs("/tmp/rate")
+	if strings.Join(options, " ") != "--filter=rate" || strings.Join(params, " ") != "rate=8000 rate-file=/tmp/rate" {
+		t.Errorf("Unexpected arguments %v %v", options, params)
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 192<<10)
+	reader := NewReader(bytes.NewReader(data), &Schedule{})
+	if _, limited := reader.(*Reader); limited {
+		t.Error("Expected the reader not to be limited")
+	}
+	reader = NewReader(bytes.NewReader(data), &Schedule{Default: 256 << 10})
+	started := time.Now()
+	read, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Fatal("Unexpected data read")
+	}
+	// The first 64KiB burst is free, the remaining 128KiB take 0.5s.
+	if elapsed := time.Since(started); elapsed < 400*time.Millisecond {
+		t.Errorf("Read too fast: %s", elapsed)
+	}
+}
@@ -0,0 +1,294 @@
+package relay
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"k8s.io/klog/v2"
+)
+
+// Files of the mTLS material.
+const (
+	CertFile = "tls.crt"
+	KeyFile  = "tls.key"
+	CAFile   = "ca.crt"
+)
+
+// Directory the relay secret is mounted at in the populator pods.
+const CertDir = "/etc/forklift/relay"
+
+// Header carrying the URL read by the relay.
+const UpstreamHeader = "X-Forklift-Upstream"
+
+// Content encoding of compressed responses.
+const EncodingZstd = "zstd"
+
+// Compression disabling the compression of the stream.
+const CompressionNone = "none"
+
+// Redirects followed when reading the upstream.
+const maxRedirects = 10
+
+// Request headers forwarded to the upstream.
+var forwarded = []string{"Accept", "Authorization", "Range", "X-Auth-Token"}
+
+// Response headers returned from the upstream.
+var returned = []string{"Accept-Ranges", "Content-Length", "Content-Range", "Content-Type", "Etag", "Last-Modified"}
+
+// TLS configuration of the relay.
+// Clients must present a certificate signed by the CA.
+func ServerTLS(dir string) (config *tls.Config, err error) {
+	cert, pool, err := load(dir)
+	if err != nil {
+		return
+	}
+	config = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return
+}
+
+// TLS configuration of the relay clients.
+// The relay certificate must be signed by the CA.
+func ClientTLS(dir string) (config *tls.Config, err error) {
+	cert, pool, err := load(dir)
+	if err != nil {
+		return
+	}
+	config = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return
+}
+
+// Load the certificate and the CA from the directory.
+func load(dir string) (cert tls.Certificate, pool *x509.CertPool, err error) {
+	cert, err = tls.LoadX509KeyPair(filepath.Join(dir, CertFile), filepath.Join(dir, KeyFile))
+	if err != nil {
+		return
+	}
+	ca, err := os.ReadFile(filepath.Join(dir, CAFile))
+	if err != nil {
+		return
+	}
+	pool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		err = fmt.Errorf("no certificates found in %s", filepath.Join(dir, CAFile))
+	}
+	return
+}
+
+// Server.
+// Reads the URL passed in the upstream header and streams the
+// response back, compressed when the client accepts zstd.
+type Server struct {
+	// Hosts the relay reads from. Entries starting
+	// with a dot match the subdomains.
+	AllowedHosts []string
+	// Client reading the upstream.
+	Client *http.Client
+}
+
+// Serve a request.
+func (r *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	upstream, err := url.Parse(req.Header.Get(UpstreamHeader))
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") {
+		http.Error(w, "invalid upstream URL", http.StatusBadRequest)
+		return
+	}
+	if !r.allowed(upstream.Hostname()) {
+		http.Error(w, "upstream host not allowed", http.StatusForbidden)
+		return
+	}
+	request, err := http.NewRequestWithContext(req.Context(), req.Method, upstream.String(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, name := range forwarded {
+		if value := req.Header.Get(name); value != "" {
+			request.Header.Set(name, value)
+		}
+	}
+	client := http.Client{}
+	if r.Client != nil {
+		client = *r.Client
+	}
+	client.CheckRedirect = r.checkRedirect
+	response, err := client.Do(request)
+	if err != nil {
+		klog.Error("Failed to read the upstream: ", err)
+		http.Error(w, "failed to read the upstream", http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+	for _, name := range returned {
+		if value := response.Header.Get(name); value != "" {
+			w.Header().Set(name, value)
+		}
+	}
+	compress := req.Method == http.MethodGet &&
+		(response.StatusCode == http.StatusOK || response.StatusCode == http.StatusPartialContent) &&
+		acceptsZstd(req)
+	if compress {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", EncodingZstd)
+	}
+	w.WriteHeader(response.StatusCode)
+	if !compress {
+		_, _ = io.Copy(w, response.Body)
+		return
+	}
+	encoder, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	if err != nil {
+		klog.Error("Failed to create the encoder: ", err)
+		return
+	}
+	_, err = io.Copy(encoder, response.Body)
+	if err != nil {
+		klog.Error("Failed to relay the upstream: ", err)
+	}
+	err = encoder.Close()
+	if err != nil {
+		klog.Error("Failed to relay the upstream: ", err)
+	}
+}
+
+// The host is allowed.
+func (r *Server) allowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range r.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Follow the redirects to the allowed hosts only.
+func (r *Server) checkRedirect(req *http.Request, via []*http.Request) (err error) {
+	if len(via) >= maxRedirects {
+		err = fmt.Errorf("stopped after %d redirects", maxRedirects)
+		return
+	}
+	if !r.allowed(req.URL.Hostname()) {
+		err = fmt.Errorf("redirect to %s not allowed", req.URL.Hostname())
+	}
+	return
+}
+
+// The client accepts zstd encoded responses.
+func acceptsZstd(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(encoding) == EncodingZstd {
+			return true
+		}
+	}
+	return false
+}
+
+// Transport.
+// Sends the requests through the relay and
+// decompresses the responses.
+type Transport struct {
+	// Relay URL.
+	URL *url.URL
+	// Request compressed responses.
+	Compress bool
+	// Transport to the relay.
+	Base http.RoundTripper
+}
+
+// Build a transport to the relay authenticating
+// with the mTLS material found in the directory.
+func NewTransport(relayURL string, compress bool, dir string) (transport *Transport, err error) {
+	u, err := url.Parse(relayURL)
+	if err != nil {
+		return
+	}
+	if u.Scheme != "https" {
+		err = errors.New("the relay URL must use https")
+		return
+	}
+	config, err := ClientTLS(dir)
+	if err != nil {
+		return
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = config
+	transport = &Transport{
+		URL:      u,
+		Compress: compress,
+		Base:     base,
+	}
+	return
+}
+
+// Send the request through the relay.
+func (r *Transport) RoundTrip(req *http.Request) (response *http.Response, err error) {
+	relayed := req.Clone(req.Context())
+	relayed.URL = &url.URL{
+		Scheme: r.URL.Scheme,
+		Host:   r.URL.Host,
+		Path:   "/",
+	}
+	relayed.Host = r.URL.Host
+	relayed.Header.Set(UpstreamHeader, req.URL.String())
+	if r.Compress {
+		relayed.Header.Set("Accept-Encoding", EncodingZstd)
+	}
+	response, err = r.Base.RoundTrip(relayed)
+	if err != nil {
+		return
+	}
+	response.Request = req
+	if response.Header.Get("Content-Encoding") != EncodingZstd {
+		return
+	}
+	decoder, err := zstd.NewReader(response.Body)
+	if err != nil {
+		_ = response.Body.Close()
+		response = nil
+		return
+	}
+	response.Body = &decodedBody{decoder: decoder, body: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return
+}
+
+// Decompressed response body.
+type decodedBody struct {
+	decoder *zstd.Decoder
+	body    io.ReadCloser
+}
+
+func (r *decodedBody) Read(p []byte) (int, error) {
+	return r.decoder.Read(p)
+}
+
+func (r *decodedBody) Close() error {
+	r.decoder.Close()
+	return r.body.Close()
+}
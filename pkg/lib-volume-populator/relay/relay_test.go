@@ -0,0 +1,212 @@
+package relay
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var content = bytes.Repeat([]byte("forklift"), 64<<10)
+
+// Write a certificate signed by the CA and the CA to the directory.
+func writeCerts(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, serial int64) string {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "relay"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, block string, der []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: block, Bytes: der})
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(CertFile, "CERTIFICATE", der)
+	write(KeyFile, "EC PRIVATE KEY", keyDer)
+	write(CAFile, "CERTIFICATE", ca.Raw)
+	return dir
+}
+
+// Start an upstream and a relay.
+// Returns the upstream URL, the relay URL and the client certificates directory.
+func setup(t *testing.T) (upstreamURL, relayURL, clientDir string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			_, port, _ := net.SplitHostPort(r.Host)
+			http.Redirect(w, r, "http://localhost:"+port+"/disk.img", http.StatusFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.ServeContent(w, r, "disk.img", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(upstream.Close)
+
+	config, err := ServerTLS(writeCerts(t, ca, caKey, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	relay := httptest.NewUnstartedServer(&Server{AllowedHosts: []string{"127.0.0.1"}})
+	relay.TLS = config
+	relay.StartTLS()
+	t.Cleanup(relay.Close)
+
+	upstreamURL = upstream.URL + "/disk.img"
+	relayURL = relay.URL
+	clientDir = writeCerts(t, ca, caKey, 3)
+	return
+}
+
+func get(t *testing.T, client *http.Client, url, ranges string) (response *http.Response, body []byte) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Authorization", "Bearer token")
+	if ranges != "" {
+		request.Header.Set("Range", ranges)
+	}
+	response, err = client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err = io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestRelayCompressed(t *testing.T) {
+	upstreamURL, relayURL, clientDir := setup(t)
+	transport, err := NewTransport(relayURL, true, clientDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: transport}
+
+	response, body := get(t, client, upstreamURL, "")
+	if response.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+		t.Fatalf("Unexpected response %s, %d bytes", response.Status, len(body))
+	}
+	if !response.Uncompressed {
+		t.Error("Expected a compressed response")
+	}
+
+	response, body = get(t, client, upstreamURL, "bytes=100-")
+	if response.StatusCode != http.StatusPartialContent || !bytes.Equal(body, content[100:]) {
+		t.Fatalf("Unexpected response %s, %d bytes", response.Status, len(body))
+	}
+}
+
+func TestRelayUncompressed(t *testing.T) {
+	upstreamURL, relayURL, clientDir := setup(t)
+	transport, err := NewTransport(relayURL, false, clientDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, body := get(t, &http.Client{Transport: transport}, upstreamURL, "")
+	if response.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+		t.Fatalf("Unexpected response %s, %d bytes", response.Status, len(body))
+	}
+	if response.Uncompressed || response.ContentLength != int64(len(content)) {
+		t.Error("Expected an uncompressed response")
+	}
+}
+
+func TestRelayRejected(t *testing.T) {
+	upstreamURL, relayURL, clientDir := setup(t)
+	transport, err := NewTransport(relayURL, true, clientDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, _ := get(t, &http.Client{Transport: transport}, "http://localhost:1/disk.img", "")
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected the host to be rejected, got %s", response.Status)
+	}
+	response, _ = get(t, &http.Client{Transport: transport}, strings.TrimSuffix(upstreamURL, "disk.img")+"redirect", "")
+	if response.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the redirect to be rejected, got %s", response.Status)
+	}
+
+	// Without a client certificate.
+	config, err := ClientTLS(clientDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Certificates = nil
+	anonymous := &Transport{
+		URL:  transport.URL,
+		Base: &http.Transport{TLSClientConfig: config},
+	}
+	request, err := http.NewRequest(http.MethodGet, upstreamURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err = (&http.Client{Transport: anonymous}).Do(request)
+	if err == nil {
+		response.Body.Close()
+		t.Error("Expected the client without a certificate to be rejected")
+	}
+}
+
+func TestTransportRequiresHTTPS(t *testing.T) {
+	if _, err := NewTransport("http://relay:8443", true, t.TempDir()); err == nil {
+		t.Error("Expected the plain http relay URL to be rejected")
+	}
+}
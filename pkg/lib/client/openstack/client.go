@@ -78,6 +78,9 @@ type Client struct {
 	imageService        *gophercloud.ServiceClient
 	networkService      *gophercloud.ServiceClient
 	blockStorageService *gophercloud.ServiceClient
+	// Transport of the image data downloads.
+	// The API requests use the provider client.
+	DataTransport http.RoundTripper
 }
 
 func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
@@ -1462,6 +1465,80 @@ func (c *Client) DownloadImage(imageID string) (data io.ReadCloser, err error) {
 	return
 }
 
+// Download the image data starting at the offset.
+// Returns whether the server honored the range request,
+// otherwise the data starts at the beginning of the image.
+func (c *Client) DownloadImageFrom(imageID string, offset int64) (data io.ReadCloser, ranged bool, err error) {
+	if c.DataTransport != nil {
+		data, ranged, err = c.downloadImageData(imageID, offset)
+		return
+	}
+	if offset <= 0 {
+		data, err = c.DownloadImage(imageID)
+		return
+	}
+	err = c.connectImageServiceAPI()
+	if err != nil {
+		return
+	}
+	url := c.imageService.ServiceURL("images", imageID, "file")
+	resp, err := c.imageService.Get(url, nil, &gophercloud.RequestOpts{
+		MoreHeaders:      map[string]string{"Range": fmt.Sprintf("bytes=%d-", offset)},
+		OkCodes:          []int{http.StatusOK, http.StatusPartialContent},
+		KeepResponseBody: true,
+	})
+	if err != nil {
+		return
+	}
+	data = resp.Body
+	ranged = resp.StatusCode == http.StatusPartialContent
+	return
+}
+
+// URL of the image data and the token authorizing the requests.
+func (c *Client) ImageData(imageID string) (url, token string, err error) {
+	err = c.connectImageServiceAPI()
+	if err != nil {
+		return
+	}
+	url = c.imageService.ServiceURL("images", imageID, "file")
+	token = c.provider.Token()
+	return
+}
+
+// Download the image data through the data transport.
+func (c *Client) downloadImageData(imageID string, offset int64) (data io.ReadCloser, ranged bool, err error) {
+	err = c.connectImageServiceAPI()
+	if err != nil {
+		return
+	}
+	request, err := http.NewRequest(http.MethodGet, c.imageService.ServiceURL("images", imageID, "file"), nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("X-Auth-Token", c.provider.Token())
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	response, err := (&http.Client{Transport: c.DataTransport}).Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		ranged = true
+	default:
+		response.Body.Close()
+		err = liberr.New(fmt.Sprintf("failed to download the image %s: %s", imageID, response.Status))
+		return
+	}
+	data = response.Body
+	return
+}
+
 func (c *Client) UnsetImageMetadata(volumeID, key string) (err error) {
 	err = c.connectBlockStorageServiceAPI()
 	if err != nil {
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"database/sql"
+	"io"
+	"os"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Storage backend.
+// Provides DB connections and the dialect used to
+// generate and execute SQL.
+type Backend interface {
+	// Open a DB connection.
+	Open() (*sql.DB, error)
+	// Delete the stored DB.
+	Delete() error
+	// Max concurrent writers.
+	Writers() int
+	// Column DDL.
+	Column(f *Field) string
+	// Insert conflict clause.
+	// Appended to INSERT statements.
+	OnConflict() string
+	// The insert conflicted with an existing row.
+	Conflict(result sql.Result, err error) bool
+	// Bind statement params.
+	// Returns the statement and params to be passed
+	// to the driver.
+	Bind(stmt string, params []interface{}) (string, []interface{})
+	// Notify (external) listeners of staged events.
+	// Called within the transaction before commit.
+	Notify(tx *sql.Tx, staged fb.Iterator) error
+	// Listen for events committed by other processes.
+	// The handler is called for each notification. Returns
+	// nil when not supported; the caller must close the
+	// returned listener.
+	Listen(handler func(Notification)) (io.Closer, error)
+	// The DB is shared with other processes (replicas).
+	// A shared DB is not deleted when the open fails.
+	Shared() bool
+	// Description.
+	String() string
+}
+
+// SQLite backend.
+// The DB is stored in a file.
+type SQLite struct {
+	// File path.
+	Path string
+}
+
+// Open a DB connection.
+func (r *SQLite) Open() (db *sql.DB, err error) {
+	db, err = sql.Open("sqlite3", r.Path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pragma := []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA journal_mode = WAL",
+	}
+	for _, stmt := range pragma {
+		_, err = db.Exec(stmt)
+		if err != nil {
+			_ = db.Close()
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+// Delete the DB file.
+func (r *SQLite) Delete() (err error) {
+	err = os.Remove(r.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+// Max concurrent writers.
+// Even with journal=WAL, must be (1) to
+// prevent SQLITE_LOCKED error.
+func (r *SQLite) Writers() int {
+	return 1
+}
+
+// Column DDL.
+func (r *SQLite) Column(f *Field) string {
+	return f.DDL()
+}
+
+// Insert conflict clause.
+// Conflicts are reported as errors.
+func (r *SQLite) OnConflict() string {
+	return ""
+}
+
+// The insert conflicted with an existing row.
+func (r *SQLite) Conflict(_ sql.Result, err error) bool {
+	if sql3Err, cast := err.(sqlite3.Error); cast {
+		return sql3Err.Code == sqlite3.ErrConstraint
+	}
+
+	return false
+}
+
+// Bind statement params.
+// Named params are supported by the driver.
+func (r *SQLite) Bind(stmt string, params []interface{}) (string, []interface{}) {
+	return stmt, params
+}
+
+// Notify (external) listeners.
+// Not supported.
+func (r *SQLite) Notify(*sql.Tx, fb.Iterator) (err error) {
+	return
+}
+
+// Listen for events committed by other processes.
+// Not supported.
+func (r *SQLite) Listen(func(Notification)) (listener io.Closer, err error) {
+	return
+}
+
+// The DB file is private to the process.
+func (r *SQLite) Shared() bool {
+	return false
+}
+
+// Description.
+func (r *SQLite) String() string {
+	return r.Path
+}
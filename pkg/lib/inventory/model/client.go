@@ -2,7 +2,8 @@ package model
 
 import (
 	"database/sql"
-	"os"
+	"io"
+	"strconv"
 	"time"
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -44,8 +45,8 @@ type DB interface {
 
 // Database client.
 type Client struct {
-	// file path.
-	path string
+	// Storage backend.
+	backend Backend
 	// Model
 	models []interface{}
 	// Overall data model.
@@ -54,6 +55,8 @@ type Client struct {
 	pool Pool
 	// Journal
 	journal Journal
+	// Listener of events committed by other processes.
+	listener io.Closer
 	// Logger
 	log logging.LevelLogger
 }
@@ -63,10 +66,10 @@ type Client struct {
 // See: Pool.Open().
 func (r *Client) Open(delete bool) (err error) {
 	if delete {
-		_ = os.Remove(r.path)
-		r.log.V(3).Info("DB file deleted.")
+		_ = r.backend.Delete()
+		r.log.V(3).Info("DB deleted.")
 	}
-	err = r.pool.Open(1, 10, r.path, &r.journal)
+	err = r.pool.Open(r.backend, 10, &r.journal)
 	if err != nil {
 		r.log.V(3).Error(err, "open session pool failed.")
 		panic(err)
@@ -74,13 +77,19 @@ func (r *Client) Open(delete bool) (err error) {
 	defer func() {
 		if err != nil {
 			_ = r.pool.Close()
-			_ = os.Remove(r.path)
+			if !r.backend.Shared() {
+				_ = r.backend.Delete()
+			}
 		}
 	}()
 	err = r.build()
 	if err != nil {
 		panic(err)
 	}
+	r.listener, err = r.backend.Listen(r.forward)
+	if err != nil {
+		return
+	}
 
 	r.log.V(3).Info("session pool opened.")
 
@@ -90,6 +99,15 @@ func (r *Client) Open(delete bool) (err error) {
 // Close the database.
 // The session pool and journal are closed.
 func (r *Client) Close(delete bool) (err error) {
+	if r.listener != nil {
+		lErr := r.listener.Close()
+		if lErr != nil {
+			r.log.Error(
+				lErr,
+				"Error closing the listener.")
+		}
+		r.listener = nil
+	}
 	jErr := r.journal.Close()
 	if jErr != nil {
 		r.log.Error(
@@ -103,8 +121,8 @@ func (r *Client) Close(delete bool) (err error) {
 			"Error closing the session pool.")
 	}
 	if delete {
-		_ = os.Remove(r.path)
-		r.log.V(3).Info("DB file deleted.")
+		_ = r.backend.Delete()
+		r.log.V(3).Info("DB deleted.")
 	}
 
 	r.log.V(3).Info("DB closed.")
@@ -128,7 +146,7 @@ func (r *Client) Get(model Model) (err error) {
 	session := r.pool.Reader()
 	defer session.Return()
 	mark := time.Now()
-	err = Table{session.db}.Get(model)
+	err = Table{session.db, r.backend}.Get(model)
 	if err == nil {
 		r.log.V(4).Info(
 			"get succeeded.",
@@ -147,7 +165,7 @@ func (r *Client) List(list interface{}, options ListOptions) (err error) {
 	session := r.pool.Reader()
 	defer session.Return()
 	mark := time.Now()
-	err = Table{session.db}.List(list, options)
+	err = Table{session.db, r.backend}.List(list, options)
 	if err == nil {
 		r.log.V(4).Info(
 			"list succeeded.",
@@ -165,7 +183,7 @@ func (r *Client) Find(model interface{}, options ListOptions) (itr fb.Iterator,
 	session := r.pool.Reader()
 	defer session.Return()
 	mark := time.Now()
-	itr, err = Table{session.db}.Find(model, options)
+	itr, err = Table{session.db, r.backend}.Find(model, options)
 	if err == nil {
 		r.log.V(4).Info(
 			"list succeeded.",
@@ -183,7 +201,7 @@ func (r *Client) Count(model Model, predicate Predicate) (n int64, err error) {
 	session := r.pool.Reader()
 	defer session.Return()
 	mark := time.Now()
-	n, err = Table{session.db}.Count(model, predicate)
+	n, err = Table{session.db, r.backend}.Count(model, predicate)
 	if err == nil {
 		r.log.V(4).Info(
 			"count succeeded.",
@@ -205,18 +223,20 @@ func (r *Client) Begin(labels ...string) (tx *Tx, error error) {
 		err = liberr.Wrap(
 			err,
 			"db",
-			r.path)
+			r.backend.String())
 		return
 	}
 	tx = &Tx{
 		session: session,
+		backend: r.backend,
 		real:    realTx,
 		journal: &r.journal,
 		staged:  fb.NewList(),
 		dm:      r.dm,
 		labeler: Labeler{
-			tx:  realTx,
-			log: r.log,
+			tx:      realTx,
+			backend: r.backend,
+			log:     r.log,
 		},
 		started: time.Now(),
 		labels:  labels,
@@ -317,7 +337,7 @@ func (r *Client) Watch(model Model, handler EventHandler) (w *Watch, err error)
 	}()
 	options := handler.Options()
 	var snapshot fb.Iterator
-	if options.Snapshot {
+	if options.Snapshot && !w.Resumed() {
 		snapshot, err = r.Find(model, ListOptions{Detail: MaxDetail})
 		if err != nil {
 			return
@@ -349,6 +369,66 @@ func (r *Client) EndWatch(watch *Watch) {
 		Describe(watch.Model))
 }
 
+// Forward an event committed by another process
+// to the journal. The model is fetched unless deleted.
+func (r *Client) forward(n Notification) {
+	md, found := r.dm.Find(n.Kind)
+	if !found {
+		return
+	}
+	model, cast := md.NewModel().(Model)
+	if !cast {
+		return
+	}
+	r.journal.mutex.RLock()
+	watched := r.journal.hasWatch(model)
+	r.journal.mutex.RUnlock()
+	if !watched {
+		return
+	}
+	md, err := Inspect(model)
+	if err != nil {
+		return
+	}
+	pk := md.PkField()
+	if pk == nil {
+		return
+	}
+	switch ptr := pk.Ptr().(type) {
+	case *int64:
+		*ptr, err = strconv.ParseInt(n.Pk, 10, 64)
+		if err != nil {
+			return
+		}
+	case *string:
+		*ptr = n.Pk
+	}
+	pk.Push()
+	if n.Action != Deleted {
+		err = r.Get(model)
+		if err != nil {
+			r.log.V(3).Info(
+				"forwarded model not found.",
+				"kind",
+				n.Kind,
+				"pk",
+				n.Pk)
+			return
+		}
+	}
+	event := Event{
+		ID:     serial.next(1),
+		Action: n.Action,
+		Model:  model,
+	}
+	if n.Action == Updated {
+		event.Updated = model
+	}
+	staged := fb.NewList()
+	event.append(staged)
+	r.journal.Report(staged)
+}
+
 // Build the data model.
 func (r *Client) build() (err error) {
 	r.models = append(r.models, &Label{})
@@ -356,7 +436,7 @@ func (r *Client) build() (err error) {
 	if err != nil {
 		return err
 	}
-	ddls, err := r.dm.DDL()
+	ddls, err := r.dm.DDL(r.backend)
 	if err != nil {
 		return err
 	}
@@ -385,6 +465,8 @@ func (r *Client) build() (err error) {
 type Tx struct {
 	// DB session.
 	session *Session
+	// Storage backend.
+	backend Backend
 	// Journal.
 	journal *Journal
 	// Real transaction.
@@ -424,7 +506,7 @@ func (r *Tx) Execute(sql string) (result sql.Result, err error) {
 // Get the model.
 func (r *Tx) Get(model Model) (err error) {
 	mark := time.Now()
-	err = Table{r.real}.Get(model)
+	err = Table{r.real, r.backend}.Get(model)
 	if err == nil {
 		r.log.V(4).Info(
 			"get succeeded.",
@@ -441,7 +523,7 @@ func (r *Tx) Get(model Model) (err error) {
 // The `list` must be: *[]Model.
 func (r *Tx) List(list interface{}, options ListOptions) (err error) {
 	mark := time.Now()
-	err = Table{r.real}.List(list, options)
+	err = Table{r.real, r.backend}.List(list, options)
 	if err == nil {
 		r.log.V(4).Info(
 			"list succeeded.",
@@ -457,7 +539,7 @@ func (r *Tx) List(list interface{}, options ListOptions) (err error) {
 // List models.
 func (r *Tx) Find(model interface{}, options ListOptions) (itr fb.Iterator, err error) {
 	mark := time.Now()
-	itr, err = Table{r.real}.Find(model, options)
+	itr, err = Table{r.real, r.backend}.Find(model, options)
 	if err == nil {
 		r.log.V(4).Info(
 			"iter succeeded",
@@ -473,7 +555,7 @@ func (r *Tx) Find(model interface{}, options ListOptions) (itr fb.Iterator, err
 // Count models.
 func (r *Tx) Count(model Model, predicate Predicate) (n int64, err error) {
 	mark := time.Now()
-	n, err = Table{r.real}.Count(model, predicate)
+	n, err = Table{r.real, r.backend}.Count(model, predicate)
 	if err == nil {
 		r.log.V(4).Info(
 			"count succeeded.",
@@ -488,7 +570,7 @@ func (r *Tx) Count(model Model, predicate Predicate) (n int64, err error) {
 // Insert the model.
 func (r *Tx) Insert(model Model) (err error) {
 	mark := time.Now()
-	err = Table{r.real}.Insert(model)
+	err = Table{r.real, r.backend}.Insert(model)
 	if err != nil {
 		return
 	}
@@ -518,11 +600,11 @@ func (r *Tx) Insert(model Model) (err error) {
 func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 	mark := time.Now()
 	current := Clone(model)
-	err = Table{r.real}.Get(current)
+	err = Table{r.real, r.backend}.Get(current)
 	if err != nil {
 		return
 	}
-	err = Table{r.real}.Update(model, predicate...)
+	err = Table{r.real, r.backend}.Update(model, predicate...)
 	if err != nil {
 		return
 	}
@@ -551,7 +633,7 @@ func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 
 // Delete (cascading) of the model.
 func (r *Tx) Delete(model Model) (err error) {
-	err = Table{r.real}.Get(model)
+	err = Table{r.real, r.backend}.Get(model)
 	if err != nil {
 		if err == NotFound {
 			return
@@ -596,6 +678,12 @@ func (r *Tx) Commit() (err error) {
 		}
 	}()
 	mark := time.Now()
+	if r.staged.Len() > 0 {
+		err = r.backend.Notify(r.real, r.staged.Iter())
+		if err != nil {
+			return
+		}
+	}
 	err = r.real.Commit()
 	if err != nil {
 		return
@@ -644,7 +732,7 @@ func (r *Tx) End() (err error) {
 // The model must be complete (fetched from the DB).
 func (r *Tx) delete(model Model) (err error) {
 	mark := time.Now()
-	err = Table{r.real}.Delete(model)
+	err = Table{r.real, r.backend}.Delete(model)
 	if err != nil {
 		if err == NotFound {
 			err = nil
@@ -686,13 +774,15 @@ func (r *Tx) report() {
 type Labeler struct {
 	// DB transaction.
 	tx *sql.Tx
+	// Storage backend.
+	backend Backend
 	// Logger.
 	log logging.LevelLogger
 }
 
 // Insert labels for the model into the DB.
 func (r *Labeler) Insert(model Model) (err error) {
-	table := Table{r.tx}
+	table := Table{r.tx, r.backend}
 	kind := table.Name(model)
 	if labeled, cast := model.(Labeled); cast {
 		for l, v := range labeled.Labels() {
@@ -728,7 +818,7 @@ func (r *Labeler) Delete(model Model) (err error) {
 		return
 	}
 	list := []Label{}
-	table := Table{r.tx}
+	table := Table{r.tx, r.backend}
 	err = table.List(
 		&list,
 		ListOptions{
@@ -1,6 +1,10 @@
 // The `model` package essentially provides a lightweight object
 // relational model (ORM) based on sqlite3 intended to support the
 // needs of the `container` package.
+// The storage is provided by a pluggable `Backend`. SQLite (file)
+// is the default. PostgreSQL (schema per DB) is also supported
+// and may be shared by replicas: events committed by another
+// replica are reported by the journal.
 // Each entity (table) is modeled by a struct.  Each field (column)
 // is Described using tags:
 //
@@ -153,17 +157,24 @@ import (
 )
 
 // New database.
+// Stored in the SQLite file at the specified path.
 func New(path string, models ...interface{}) DB {
+	return NewWith(&SQLite{Path: path}, models...)
+}
+
+// New database.
+// Stored using the specified backend.
+func NewWith(backend Backend, models ...interface{}) DB {
 	client := &Client{
-		path:   path,
-		models: models,
+		backend: backend,
+		models:  models,
 	}
 	client.log = logging.WithName("model|db").WithValues(
-		"path",
-		path)
+		"db",
+		backend.String())
 	client.journal.log = logging.WithName("db|journal").WithValues(
 		"db",
-		path)
+		backend.String())
 
 	return client
 }
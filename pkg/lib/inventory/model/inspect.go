@@ -255,11 +255,11 @@ func (r *DataModel) Definitions() (list Definitions) {
 }
 
 // Build the DDL.
-func (r *DataModel) DDL() (list []string, err error) {
+func (r *DataModel) DDL(backend Backend) (list []string, err error) {
 	fkRelation := FkRelation{dm: r}
 	for _, md := range fkRelation.Definitions() {
 		var ddl []string
-		ddl, err = Table{}.DDL(md.model, r)
+		ddl, err = Table{Backend: backend}.DDL(md.model, r)
 		if err != nil {
 			return
 		}
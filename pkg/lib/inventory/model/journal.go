@@ -2,7 +2,9 @@ package model
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
@@ -13,6 +15,24 @@ import (
 // Serial number pool.
 var serial Serial
 
+// Journal history depth.
+// The number of committed transactions retained by
+// the journal so that watches may be resumed.
+var JournalHistory = 50
+
+// Journal history size.
+// The number of objects (events and models) retained
+// by the journal. The oldest transactions are discarded
+// until the retained objects are within the limit.
+var JournalHistoryObjects = 10000
+
+// Process epoch.
+// Event IDs are issued by a (per-process) serial number
+// pool that restarts after the process is restarted. The
+// epoch qualifies the event IDs so that IDs issued by a
+// previous process are not mistaken for retained events.
+var Epoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
 // Event Actions.
 var (
 	Started uint8 = 0x00
@@ -131,6 +151,15 @@ type WatchOptions struct {
 	// Initial snapshot.
 	// List models and report as `Created` events.
 	Snapshot bool
+	// Resume after the specified event ID.
+	// Events retained by the journal with a greater ID
+	// are replayed in place of the snapshot. When the
+	// journal no longer retains the event, the watch
+	// is not resumed and the snapshot is reported.
+	Since uint64
+	// The epoch in which the `Since` event ID was issued.
+	// The watch is not resumed when not the current epoch.
+	SinceEpoch string
 }
 
 // Event handler.
@@ -169,6 +198,10 @@ type Watch struct {
 	journal *Journal
 	// Logger.
 	log logging.LevelLogger
+	// Resumed (replaying retained events).
+	resumed bool
+	// Events with ID <= since are not reported.
+	since uint64
 	// Started
 	started bool
 	// Done
@@ -194,6 +227,13 @@ func (w *Watch) Alive() bool {
 	return !w.done
 }
 
+// The watch has been resumed.
+// Retained events (after WatchOptions.Since) are
+// replayed instead of reporting the snapshot.
+func (w *Watch) Resumed() bool {
+	return w.resumed
+}
+
 // Match by model `kind`.
 func (w *Watch) Match(model Model) bool {
 	return ref.ToKind(w.Model) == ref.ToKind(model)
@@ -254,6 +294,9 @@ func (w *Watch) Start(snapshot fb.Iterator) {
 				if !w.Match(event.Model) {
 					continue
 				}
+				if event.ID <= w.since {
+					continue
+				}
 				w.log.V(5).Info(
 					"event received.",
 					"event",
@@ -294,6 +337,12 @@ type Journal struct {
 	log logging.LevelLogger
 	// List of registered watches.
 	watches []*Watch
+	// Retained (committed) transactions.
+	history []*fb.List
+	// Objects retained in the history.
+	retainedObjects int
+	// The highest event ID no longer retained.
+	horizon uint64
 }
 
 // Watch a `watch` of the specified model.
@@ -317,6 +366,15 @@ func (r *Journal) Watch(model Model, handler EventHandler) (*Watch, error) {
 	}
 	r.watches = append(r.watches, watch)
 	watch.queue = make(chan fb.Iterator, 250)
+	options := handler.Options()
+	since := options.Since
+	if since > 0 && options.SinceEpoch == Epoch && r.retained(since) {
+		watch.resumed = true
+		watch.since = since
+		for _, staged := range r.history {
+			watch.queue <- staged.Iter()
+		}
+	}
 
 	r.log.V(3).Info(
 		"watch created.",
@@ -354,6 +412,34 @@ func (r *Journal) Report(staged *fb.List) {
 	for _, w := range r.watches {
 		w.notify(staged.Iter())
 	}
+	r.history = append(r.history, staged)
+	r.retainedObjects += staged.Len()
+	for len(r.history) > JournalHistory ||
+		(len(r.history) > 0 && r.retainedObjects > JournalHistoryObjects) {
+		evicted := r.history[0]
+		r.history = r.history[1:]
+		r.retainedObjects -= evicted.Len()
+		itr := evicted.Iter()
+		for {
+			event := Event{}
+			hasNext := event.next(itr)
+			if !hasNext {
+				break
+			}
+			if event.ID > r.horizon {
+				r.horizon = event.ID
+			}
+		}
+	}
+}
+
+// Determine whether events after the specified
+// event ID are retained. The ID must not have been
+// issued before the journal discarded it, nor may it
+// be greater than the last issued ID (issued by a
+// previous process).
+func (r *Journal) retained(id uint64) bool {
+	return id >= r.horizon && id <= serial.last(1)
 }
 
 // Close the journal.
@@ -428,3 +514,11 @@ func (r *Serial) next(key int) (sn uint64) {
 	r.pool[key] = sn
 	return
 }
+
+// Last serial number issued.
+func (r *Serial) last(key int) (sn uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sn = r.pool[key]
+	return
+}
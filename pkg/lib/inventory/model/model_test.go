@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
 	"testing"
 	"time"
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	"github.com/konveyor/forklift-controller/pkg/lib/ref"
 	"github.com/onsi/gomega"
 )
@@ -997,11 +999,8 @@ func TestWatch(t *testing.T) {
 		time.Sleep(time.Millisecond * 10)
 		if len(handlerA.created) != N ||
 			len(handlerA.updated) != N ||
-			len(handlerA.created) != N ||
 			len(handlerB.created) != N ||
 			len(handlerB.updated) != N ||
-			len(handlerB.created) != N ||
-			len(handlerC.created) != N ||
 			len(handlerC.created) != N {
 			continue
 		} else {
@@ -1153,6 +1152,130 @@ func TestWatch(t *testing.T) {
 	g.Expect(handlerD.done).To(gomega.BeTrue())
 }
 
+func TestResumeWatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New("/tmp/test-resume-watch.db", &TestObject{})
+	err := DB.Open(true)
+	defer func() {
+		_ = DB.Close(false)
+	}()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	N := 10
+	for i := 0; i < N; i++ {
+		err = DB.Insert(&TestObject{ID: i, Name: "Elmer"})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	mark := serial.last(1)
+	for i := N; i < N+2; i++ {
+		err = DB.Insert(&TestObject{ID: i, Name: "Elmer"})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	wait := func(h *TestHandler, n int) {
+		for i := 0; i < 100; i++ {
+			if len(h.created) == n && h.parity {
+				break
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+	// Resumed.
+	handlerA := &TestHandler{
+		options: WatchOptions{Snapshot: true, Since: mark, SinceEpoch: Epoch},
+		name:    "A",
+	}
+	watchA, err := DB.Watch(&TestObject{}, handlerA)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(watchA.Resumed()).To(gomega.BeTrue())
+	wait(handlerA, 2)
+	g.Expect(handlerA.created).To(gomega.Equal([]int{N, N + 1}))
+	// Unknown (future) event ID.
+	handlerB := &TestHandler{
+		options: WatchOptions{Snapshot: true, Since: serial.last(1) + 100, SinceEpoch: Epoch},
+		name:    "B",
+	}
+	watchB, err := DB.Watch(&TestObject{}, handlerB)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(watchB.Resumed()).To(gomega.BeFalse())
+	wait(handlerB, N+2)
+	g.Expect(len(handlerB.created)).To(gomega.Equal(N + 2))
+	// Issued by a previous process.
+	handlerE := &TestHandler{
+		options: WatchOptions{Snapshot: true, Since: mark, SinceEpoch: "previous"},
+		name:    "E",
+	}
+	watchE, err := DB.Watch(&TestObject{}, handlerE)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(watchE.Resumed()).To(gomega.BeFalse())
+	wait(handlerE, N+2)
+	g.Expect(len(handlerE.created)).To(gomega.Equal(N + 2))
+	// No longer retained.
+	for i := 0; i < JournalHistory; i++ {
+		object := &TestObject{ID: i % N}
+		_ = DB.Get(object)
+		object.Age++
+		err = DB.Update(object)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	handlerC := &TestHandler{
+		options: WatchOptions{Snapshot: true, Since: mark, SinceEpoch: Epoch},
+		name:    "C",
+	}
+	watchC, err := DB.Watch(&TestObject{}, handlerC)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(watchC.Resumed()).To(gomega.BeFalse())
+	wait(handlerC, N+2)
+	g.Expect(len(handlerC.created)).To(gomega.Equal(N + 2))
+}
+
+func TestJournalHistoryObjects(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	limit := JournalHistoryObjects
+	JournalHistoryObjects = 4
+	defer func() {
+		JournalHistoryObjects = limit
+	}()
+	journal := &Journal{}
+	for i := 0; i < 3; i++ {
+		staged := fb.NewList()
+		event := Event{ID: serial.next(1), Action: Created, Model: &TestObject{ID: i}}
+		event.append(staged)
+		journal.Report(staged)
+	}
+	// Each (created) event is 2 objects.
+	g.Expect(journal.history).To(gomega.HaveLen(2))
+	g.Expect(journal.retainedObjects).To(gomega.Equal(4))
+	g.Expect(journal.horizon).To(gomega.Equal(serial.last(1) - 2))
+}
+
+func TestForward(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New("/tmp/test-forward.db", &PlainObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	client := DB.(*Client)
+	err = DB.Insert(&PlainObject{ID: 18, Name: "Ashley"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	handler := &RemoteHandler{events: make(chan Event, 10)}
+	w, err := DB.Watch(&PlainObject{}, handler)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer w.End()
+	client.forward(Notification{Action: Updated, Kind: "PlainObject", Pk: "18"})
+	client.forward(Notification{Action: Created, Kind: "PlainObject", Pk: "19"})
+	client.forward(Notification{Action: Deleted, Kind: "PlainObject", Pk: "20"})
+	client.forward(Notification{Action: Created, Kind: "Unknown", Pk: "1"})
+	event := <-handler.events
+	g.Expect(event.Action).To(gomega.Equal(Updated))
+	g.Expect(event.Updated.(*PlainObject).Name).To(gomega.Equal("Ashley"))
+	// Model 19 is not found and not forwarded.
+	event = <-handler.events
+	g.Expect(event.Action).To(gomega.Equal(Deleted))
+	g.Expect(event.Model.Pk()).To(gomega.Equal("20"))
+	g.Consistently(handler.events).ShouldNot(gomega.Receive())
+}
+
 //nolint:errcheck
 func TestCloseDB(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
@@ -1550,3 +1673,123 @@ func fieldNames(fields []*Field) (names []string) {
 
 	return
 }
+
+func TestPostgresBind(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	backend := &Postgres{Schema: "Provider-1"}
+	options := ListOptions{
+		Predicate: Or(
+			Eq("Name", "Elmer"),
+			Gt("Age", 10)),
+	}
+	md, err := Inspect(&PlainObject{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = options.Build(md)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	stmt, err := Table{Backend: backend}.listSQL(md, &options)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	stmt, params := backend.Bind(stmt, options.Params())
+	g.Expect(stmt).To(gomega.ContainSubstring("Name = $1"))
+	g.Expect(stmt).To(gomega.ContainSubstring("Age > $2"))
+	g.Expect(stmt).ToNot(gomega.ContainSubstring(":"))
+	g.Expect(params).To(gomega.Equal([]interface{}{"Elmer", int64(10)}))
+	g.Expect(backend.String()).To(gomega.Equal("postgres:provider_1"))
+	// DDL.
+	ddl, err := Table{Backend: backend}.TableDDL(md, &DataModel{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ddl[0]).To(gomega.ContainSubstring("ID BIGINT PRIMARY KEY"))
+	g.Expect(ddl[0]).To(gomega.ContainSubstring("Name TEXT NOT NULL"))
+	stmt, err = Table{Backend: backend}.insertSQL(md)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(stmt).To(gomega.ContainSubstring("ON CONFLICT DO NOTHING"))
+	stmt, err = Table{}.insertSQL(md)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(stmt).ToNot(gomega.ContainSubstring("ON CONFLICT"))
+}
+
+// Requires a (local) PostgreSQL database.
+// Example: FORKLIFT_TEST_POSTGRES=postgres://user:pw@localhost/test?sslmode=disable
+func TestPostgres(t *testing.T) {
+	dsn, found := os.LookupEnv("FORKLIFT_TEST_POSTGRES")
+	if !found {
+		t.Skip("FORKLIFT_TEST_POSTGRES not set.")
+	}
+	g := gomega.NewGomegaWithT(t)
+	backend := &Postgres{DSN: dsn, Schema: "test-crud"}
+	DB := NewWith(
+		backend,
+		&PlainObject{},
+		&DetailA{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	// Replica sharing the schema.
+	replica := NewWith(
+		&Postgres{DSN: dsn, Schema: "test-crud"},
+		&PlainObject{},
+		&DetailA{})
+	err = replica.Open(false)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = replica.Close(false)
+	}()
+	handler := &RemoteHandler{events: make(chan Event, 10)}
+	w, err := replica.Watch(&PlainObject{}, handler)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer w.End()
+	plainA := &PlainObject{ID: 18, Name: "Ashley", Age: 17}
+	err = DB.Insert(plainA)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// Insert (conflict) updated.
+	plainA.Age = 18
+	err = DB.Insert(plainA)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	plainB := &PlainObject{ID: 18}
+	err = DB.Get(plainB)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(plainB.Age).To(gomega.Equal(18))
+	// Predicates.
+	list := []PlainObject{}
+	err = DB.List(&list, ListOptions{Predicate: Eq("Name", "Ashley")})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(list)).To(gomega.Equal(1))
+	// Cascade delete.
+	err = DB.Insert(&DetailA{PK: 1, FK: 18})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Delete(plainA)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	n, err := DB.Count(&DetailA{}, nil)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(n).To(gomega.Equal(int64(0)))
+	// Reported by the replica.
+	for _, action := range []uint8{Created, Updated, Deleted} {
+		select {
+		case event := <-handler.events:
+			g.Expect(event.Action).To(gomega.Equal(action))
+			g.Expect(event.Model.Pk()).To(gomega.Equal("18"))
+		case <-time.After(5 * time.Second):
+			t.Fatal("not notified.")
+		}
+	}
+}
+
+// Event handler reporting the events committed by
+// another (replica) process.
+type RemoteHandler struct {
+	StockEventHandler
+	events chan Event
+}
+
+func (r *RemoteHandler) Created(event Event) {
+	r.events <- event
+}
+
+func (r *RemoteHandler) Updated(event Event) {
+	r.events <- event
+}
+
+func (r *RemoteHandler) Deleted(event Event) {
+	r.events <- event
+}
@@ -0,0 +1,321 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
+	"github.com/lib/pq"
+)
+
+// Listener reconnect interval.
+const (
+	MinRetry = time.Second
+	MaxRetry = time.Minute
+)
+
+// Named param (reference) pattern.
+var ParamRegex = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Invalid schema (name) characters.
+var SchemaRegex = regexp.MustCompile(`[^a-z0-9_]`)
+
+// Backend (origin) serial number.
+var origins atomic.Uint64
+
+// PostgreSQL backend.
+// Each DB is stored in a dedicated schema so that a single
+// database may be shared by many (provider) inventories.
+// Committed events are published using NOTIFY on a channel
+// named for the schema so that the journal of each process
+// (replica) sharing the schema reports the events committed
+// by the others.
+type Postgres struct {
+	// Connection string (URL or key=value).
+	DSN string
+	// Schema (name).
+	Schema string
+	// Schema created.
+	created bool
+	// Origin of the published events.
+	origin string
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// Open a DB connection.
+// The schema is created as needed and set as
+// the search path.
+func (r *Postgres) Open() (db *sql.DB, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.origin == "" {
+		r.origin = fmt.Sprintf("%s.%d", Epoch, origins.Add(1))
+	}
+	if !r.created {
+		err = r.exec(
+			fmt.Sprintf(
+				"CREATE SCHEMA IF NOT EXISTS %s",
+				r.schema()))
+		if err != nil {
+			return
+		}
+		r.created = true
+	}
+	dsn, err := r.dsn()
+	if err != nil {
+		return
+	}
+	db, err = sql.Open("postgres", dsn+" search_path="+r.schema())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Delete the schema.
+func (r *Postgres) Delete() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	err = r.exec(
+		fmt.Sprintf(
+			"DROP SCHEMA IF EXISTS %s CASCADE",
+			r.schema()))
+	if err != nil {
+		return
+	}
+
+	r.created = false
+
+	return
+}
+
+// Max concurrent writers.
+func (r *Postgres) Writers() int {
+	return 1
+}
+
+// Column DDL.
+func (r *Postgres) Column(f *Field) string {
+	part := []string{
+		f.Name,     // name
+		"TEXT",     // type
+		"NOT NULL", // constraint
+	}
+	switch f.Value.Kind() {
+	case reflect.Bool,
+		reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+		part[1] = "BIGINT"
+	}
+	if f.Pk() {
+		part[2] = "PRIMARY KEY"
+	}
+
+	return strings.Join(part, " ")
+}
+
+// Insert conflict clause.
+// An error would abort the transaction so conflicts
+// are detected using the affected row count.
+func (r *Postgres) OnConflict() string {
+	return "ON CONFLICT DO NOTHING"
+}
+
+// The insert conflicted with an existing row.
+func (r *Postgres) Conflict(result sql.Result, err error) bool {
+	if err != nil || result == nil {
+		return false
+	}
+	n, err := result.RowsAffected()
+	return err == nil && n == 0
+}
+
+// Bind statement params.
+// Named (:name) params are replaced with ordinal ($n) params.
+func (r *Postgres) Bind(stmt string, params []interface{}) (string, []interface{}) {
+	named := map[string]interface{}{}
+	for _, p := range params {
+		if arg, cast := p.(sql.NamedArg); cast {
+			named[arg.Name] = arg.Value
+		}
+	}
+	if len(named) == 0 {
+		return stmt, params
+	}
+	bound := []interface{}{}
+	position := map[string]int{}
+	stmt = ParamRegex.ReplaceAllStringFunc(
+		stmt,
+		func(ref string) string {
+			name := ref[1:]
+			value, found := named[name]
+			if !found {
+				return ref
+			}
+			n, found := position[name]
+			if !found {
+				bound = append(bound, value)
+				n = len(bound)
+				position[name] = n
+			}
+			return fmt.Sprintf("$%d", n)
+		})
+
+	return stmt, bound
+}
+
+// Notify listeners of staged events.
+// The payload is the event (JSON) with the kind
+// and primary key of the model.
+func (r *Postgres) Notify(tx *sql.Tx, staged fb.Iterator) (err error) {
+	for {
+		event := Event{}
+		if !event.next(staged) {
+			break
+		}
+		payload := Notification{
+			Origin: r.origin,
+			ID:     event.ID,
+			Action: event.Action,
+			Kind:   Table{}.Name(event.Model),
+			Pk:     event.Model.Pk(),
+		}
+		b, jErr := json.Marshal(payload)
+		if jErr != nil {
+			err = liberr.Wrap(jErr)
+			return
+		}
+		_, err = tx.Exec(
+			"SELECT pg_notify($1, $2)",
+			r.schema(),
+			string(b))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+// Listen for events committed by other processes.
+// Notifications published by this backend are ignored.
+// Notifications missed while the listener reconnects
+// are not reported.
+func (r *Postgres) Listen(handler func(Notification)) (listener io.Closer, err error) {
+	dsn, err := r.dsn()
+	if err != nil {
+		return
+	}
+	pqListener := pq.NewListener(dsn, MinRetry, MaxRetry, nil)
+	err = pqListener.Listen(r.schema())
+	if err != nil {
+		_ = pqListener.Close()
+		err = liberr.Wrap(err)
+		return
+	}
+	r.mutex.Lock()
+	origin := r.origin
+	r.mutex.Unlock()
+	go func() {
+		for n := range pqListener.Notify {
+			if n == nil {
+				continue
+			}
+			payload := Notification{}
+			err := json.Unmarshal([]byte(n.Extra), &payload)
+			if err != nil || payload.Origin == origin {
+				continue
+			}
+			handler(payload)
+		}
+	}()
+	listener = pqListener
+	return
+}
+
+// The schema is shared with other processes.
+func (r *Postgres) Shared() bool {
+	return true
+}
+
+// Description.
+func (r *Postgres) String() string {
+	return "postgres:" + r.schema()
+}
+
+// Schema (identifier).
+func (r *Postgres) schema() string {
+	return SchemaRegex.ReplaceAllString(strings.ToLower(r.Schema), "_")
+}
+
+// Connection string (key=value).
+func (r *Postgres) dsn() (dsn string, err error) {
+	dsn = r.DSN
+	if strings.HasPrefix(dsn, "postgres://") ||
+		strings.HasPrefix(dsn, "postgresql://") {
+		dsn, err = pq.ParseURL(dsn)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+// Execute a statement outside of the schema.
+func (r *Postgres) exec(stmt string) (err error) {
+	dsn, err := r.dsn()
+	if err != nil {
+		return
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	_, err = db.Exec(stmt)
+	if err != nil {
+		err = liberr.Wrap(err, "sql", stmt)
+	}
+
+	return
+}
+
+// Event notification (payload).
+type Notification struct {
+	// Origin (backend) of the event.
+	Origin string `json:"origin"`
+	// Event ID.
+	ID uint64 `json:"id"`
+	// Event action.
+	Action uint8 `json:"action"`
+	// Model kind.
+	Kind string `json:"kind"`
+	// Model primary key.
+	Pk string `json:"pk"`
+}
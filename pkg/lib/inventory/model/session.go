@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -77,37 +78,25 @@ type Pool struct {
 }
 
 // Open the pool.
-// Create sessions with DB connections.
-// For sqlite3:
-//
-//	Even with journal=WAL, nWriter must be (1) to
-//	prevent SQLITE_LOCKED error.
-func (p *Pool) Open(nWriter, nReader int, path string, journal *Journal) (err error) {
+// Create sessions with DB connections provided
+// by the backend. See: Backend.Writers().
+func (p *Pool) Open(backend Backend, nReader int, journal *Journal) (err error) {
 	defer func() {
 		if err != nil {
 			_ = p.Close()
 		}
 	}()
 	p.journal = journal
+	nWriter := backend.Writers()
 	total := nWriter + nReader
 	p.next.writer = make(chan *Session, nWriter)
 	p.next.reader = make(chan *Session, nReader)
 	for id := 0; id < total; id++ {
 		session := &Session{id: id}
-		session.db, err = sql.Open("sqlite3", path)
+		session.db, err = backend.Open()
 		if err != nil {
 			return
 		}
-		pragma := []string{
-			"PRAGMA foreign_keys = ON",
-			"PRAGMA journal_mode = WAL",
-		}
-		for _, stmt := range pragma {
-			_, err = session.db.Exec(stmt)
-			if err != nil {
-				return
-			}
-		}
 		p.sessions = append(
 			p.sessions,
 			session)
@@ -14,7 +14,6 @@ import (
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
-	"github.com/mattn/go-sqlite3"
 )
 
 // DDL templates.
@@ -22,7 +21,7 @@ var TableDDL = `
 CREATE TABLE IF NOT EXISTS {{.Table}} (
 {{ range $i,$f := .Fields -}}
 {{ if $i }},{{ end -}}
-{{ $f.DDL }}
+{{ $.Column $f }}
 {{ end -}}
 {{ range $i,$c := .Constraints -}}
 ,{{ $c }}
@@ -54,7 +53,11 @@ VALUES (
 {{ if $i }},{{ end -}}
 {{ $f.Param }}
 {{ end -}}
-);
+)
+{{ if .OnConflict -}}
+{{ .OnConflict }}
+{{ end -}}
+;
 `
 
 var UpdateSQL = `
@@ -159,6 +162,9 @@ var (
 type Table struct {
 	// Database connection.
 	DB DBTX
+	// Storage backend.
+	// Defaults to SQLite.
+	Backend Backend
 }
 
 // Get the table name for the model.
@@ -214,6 +220,7 @@ func (t Table) TableDDL(md *Definition, dm *DataModel) (list []string, err error
 			Table:       md.Kind,
 			Fields:      md.RealFields(md.Fields),
 			Constraints: constraints,
+			Backend:     t.backend(),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -314,13 +321,11 @@ func (t Table) Insert(model interface{}) (err error) {
 		return
 	}
 	params := t.Params(md)
-	r, err := t.DB.Exec(stmt, params...)
+	r, err := t.exec(stmt, params)
+	if t.backend().Conflict(r, err) {
+		return t.Update(model)
+	}
 	if err != nil {
-		if sql3Err, cast := err.(sqlite3.Error); cast {
-			if sql3Err.Code == sqlite3.ErrConstraint {
-				return t.Update(model)
-			}
-		}
 		err = liberr.Wrap(
 			err,
 			"sql",
@@ -364,7 +369,7 @@ func (t Table) Update(model interface{}, predicate ...Predicate) (err error) {
 		return
 	}
 	params := append(t.Params(md), options.Params()...)
-	r, err := t.DB.Exec(stmt, params...)
+	r, err := t.exec(stmt, params)
 	if err != nil {
 		err = liberr.Wrap(
 			err,
@@ -409,7 +414,7 @@ func (t Table) Delete(model interface{}) (err error) {
 		return
 	}
 	params := t.Params(md)
-	r, err := t.DB.Exec(stmt, params...)
+	r, err := t.exec(stmt, params)
 	if err != nil {
 		err = liberr.Wrap(
 			err,
@@ -453,7 +458,7 @@ func (t Table) Get(model interface{}) (err error) {
 		return
 	}
 	params := t.Params(md)
-	row := t.DB.QueryRow(stmt, params...)
+	row := t.queryRow(stmt, params)
 	err = t.scan(row, md.Fields)
 	if err != nil {
 		err = liberr.Wrap(
@@ -505,7 +510,7 @@ func (t Table) List(list interface{}, options ListOptions) (err error) {
 		return
 	}
 	params := options.Params()
-	cursor, err := t.DB.Query(stmt, params...)
+	cursor, err := t.query(stmt, params)
 	if err != nil {
 		err = liberr.Wrap(
 			err,
@@ -559,7 +564,7 @@ func (t Table) Find(model interface{}, options ListOptions) (itr fb.Iterator, er
 		return
 	}
 	params := options.Params()
-	cursor, err := t.DB.Query(stmt, params...)
+	cursor, err := t.query(stmt, params)
 	if err != nil {
 		err = liberr.Wrap(err, "sql", stmt, "params", params)
 		return
@@ -611,7 +616,7 @@ func (t Table) Count(model interface{}, predicate Predicate) (count int64, err e
 	}
 	count = int64(0)
 	params := options.Params()
-	row := t.DB.QueryRow(stmt, params...)
+	row := t.queryRow(stmt, params)
 	err = row.Scan(&count)
 	if err != nil {
 		err = liberr.Wrap(
@@ -750,8 +755,9 @@ func (t Table) insertSQL(md *Definition) (sql string, err error) {
 	err = tpl.Execute(
 		bfr,
 		TmplData{
-			Table:  md.Kind,
-			Fields: md.RealFields(md.Fields),
+			Table:   md.Kind,
+			Fields:  md.RealFields(md.Fields),
+			Backend: t.backend(),
 		})
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -928,6 +934,35 @@ func (t Table) scan(row Row, fields []*Field) (err error) {
 	return
 }
 
+// Storage backend.
+func (t Table) backend() Backend {
+	if t.Backend != nil {
+		return t.Backend
+	}
+	return &SQLite{}
+}
+
+// Execute the statement.
+// Params are bound by the backend.
+func (t Table) exec(stmt string, params []interface{}) (sql.Result, error) {
+	stmt, params = t.backend().Bind(stmt, params)
+	return t.DB.Exec(stmt, params...)
+}
+
+// Execute the query.
+// Params are bound by the backend.
+func (t Table) query(stmt string, params []interface{}) (*sql.Rows, error) {
+	stmt, params = t.backend().Bind(stmt, params)
+	return t.DB.Query(stmt, params...)
+}
+
+// Execute the (single row) query.
+// Params are bound by the backend.
+func (t Table) queryRow(stmt string, params []interface{}) *sql.Row {
+	stmt, params = t.backend().Bind(stmt, params)
+	return t.DB.QueryRow(stmt, params...)
+}
+
 // Template data.
 type TmplData struct {
 	// Table name.
@@ -946,6 +981,24 @@ type TmplData struct {
 	Options *FilterOptions
 	// Count
 	Count bool
+	// Storage backend.
+	Backend Backend
+}
+
+// Column DDL.
+func (t TmplData) Column(f *Field) string {
+	if t.Backend != nil {
+		return t.Backend.Column(f)
+	}
+	return f.DDL()
+}
+
+// Insert conflict clause.
+func (t TmplData) OnConflict() string {
+	if t.Backend != nil {
+		return t.Backend.OnConflict()
+	}
+	return ""
 }
 
 // Predicate
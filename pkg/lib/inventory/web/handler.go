@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -109,23 +110,7 @@ type Event struct {
 
 // String representation.
 func (r *Event) String() string {
-	action := "unknown"
-	switch r.Action {
-	case model.Started:
-		action = "started"
-	case model.Parity:
-		action = "parity"
-	case model.Error:
-		action = "error"
-	case model.End:
-		action = "end"
-	case model.Created:
-		action = "created"
-	case model.Updated:
-		action = "updated"
-	case model.Deleted:
-		action = "deleted"
-	}
+	action := actionName(r.Action)
 	kind := ""
 	if r.Resource != nil {
 		kind = ref.ToKind(r.Resource)
@@ -137,6 +122,29 @@ func (r *Event) String() string {
 		kind)
 }
 
+// Action name.
+func actionName(action uint8) (name string) {
+	name = "unknown"
+	switch action {
+	case model.Started:
+		name = "started"
+	case model.Parity:
+		name = "parity"
+	case model.Error:
+		name = "error"
+	case model.End:
+		name = "end"
+	case model.Created:
+		name = "created"
+	case model.Updated:
+		name = "updated"
+	case model.Deleted:
+		name = "deleted"
+	}
+
+	return
+}
+
 // Watch (event) writer.
 // The writer is model event handler. Each event
 // is send (forwarded) to the watch client.  This
@@ -288,15 +296,38 @@ type Watched struct {
 	WatchRequest bool
 	// Watch options.
 	options model.WatchOptions
+	// Server-sent events requested.
+	stream bool
 }
 
 // Prepare the handler to fulfil the request.
 // Set the `WatchRequest` and `snapshot` fields based on passed headers.
 // The header value is a list of options.
+// A request accepting `text/event-stream` is a watch request
+// delivered as server-sent events. The options may also be passed
+// using the `watch` query parameter and the `Last-Event-ID` header
+// is used to resume the watch.
 func (h *Watched) Prepare(ctx *gin.Context) int {
 	header, found := ctx.Request.Header[WatchHeader]
 	h.WatchRequest = found
-	for _, option := range header {
+	h.stream = strings.Contains(
+		ctx.Request.Header.Get("Accept"),
+		EventStream)
+	options := header
+	if h.stream {
+		h.WatchRequest = true
+		options = append(options, ctx.QueryArray(WatchParam)...)
+		lastID := ctx.Request.Header.Get(LastEventIDHeader)
+		if len(lastID) != 0 {
+			epoch, since, err := parseEventID(lastID)
+			if err != nil {
+				return http.StatusBadRequest
+			}
+			h.options.SinceEpoch = epoch
+			h.options.Since = since
+		}
+	}
+	for _, option := range options {
 		switch option {
 		case WatchSnapshot:
 			h.options.Snapshot = true
@@ -307,12 +338,18 @@ func (h *Watched) Prepare(ctx *gin.Context) int {
 }
 
 // Watch model.
+// Events are pushed using a websocket or, when requested,
+// as server-sent events.
 func (r *Watched) Watch(
 	ctx *gin.Context,
 	db model.DB,
 	m model.Model,
 	rb ResourceBuilder) (err error) {
 	//
+	if r.stream {
+		err = r.watchStream(ctx, db, m, rb)
+		return
+	}
 	upGrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
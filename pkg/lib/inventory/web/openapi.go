@@ -0,0 +1,422 @@
+package web
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI.
+const (
+	// Specification version.
+	OpenAPIVersion = "3.0.3"
+	// Route.
+	OpenAPIRoot = "/openapi"
+)
+
+// Documented route.
+// Describes a (GET) route for the OpenAPI document.
+type Route struct {
+	// Path (gin syntax).
+	Path string
+	// Group (tag).
+	Tag string
+	// Operation name.
+	// Must be unique within the group.
+	Operation string
+	// Summary.
+	Summary string
+	// Returned resource.
+	Resource interface{}
+	// A list of resources is returned.
+	List bool
+	// The collection may be watched.
+	Watch bool
+	// Query parameters.
+	Query []Parameter
+}
+
+// Operation ID.
+// The group followed by the operation name.
+func (r *Route) OperationID() string {
+	if r.Tag == "" {
+		return r.Operation
+	}
+	return r.Tag + r.Operation
+}
+
+// Path parameters.
+// Listed in the order found in the path.
+func (r *Route) PathParams() (params []string) {
+	for _, part := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(part, ":") {
+			params = append(params, part[1:])
+		}
+	}
+
+	return
+}
+
+// OpenAPI (formatted) path.
+// Example: /providers/:provider => /providers/{provider}
+func (r *Route) OpenAPIPath() string {
+	parts := strings.Split(strings.TrimLeft(r.Path, "/"), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+
+	return "/" + strings.Join(parts, "/")
+}
+
+// OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Document info.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Path item.
+type PathItem struct {
+	Get *Operation `json:"get,omitempty"`
+}
+
+// Operation.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// Response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Media type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Build an OpenAPI document for the routes.
+func NewDocument(title, version string, routes []Route) (d *Document) {
+	d = &Document{
+		OpenAPI: OpenAPIVersion,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+	builder := SchemaBuilder{Schemas: d.Components.Schemas}
+	for i := range routes {
+		route := &routes[i]
+		op := &Operation{
+			OperationID: route.OperationID(),
+			Summary:     route.Summary,
+			Responses:   map[string]*Response{},
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+		for _, name := range route.PathParams() {
+			op.Parameters = append(
+				op.Parameters,
+				Parameter{
+					Name:     name,
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+		}
+		op.Parameters = append(op.Parameters, route.Query...)
+		schema := builder.Build(reflect.TypeOf(route.Resource))
+		if route.List {
+			schema = &Schema{
+				Type:  "array",
+				Items: schema,
+			}
+		}
+		ok := &Response{
+			Description: "OK",
+			Content: map[string]MediaType{
+				"application/json": {Schema: schema},
+			},
+		}
+		if route.Watch {
+			op.Parameters = append(
+				op.Parameters,
+				Parameter{
+					Name:        WatchHeader,
+					In:          "header",
+					Description: "Watch the collection (websocket).",
+					Schema:      &Schema{Type: "string"},
+				},
+				Parameter{
+					Name:        LastEventIDHeader,
+					In:          "header",
+					Description: "Resume the watch (event-stream) after the event <epoch>-<serial>.",
+					Schema:      &Schema{Type: "string"},
+				})
+			ok.Content[EventStream] = MediaType{
+				Schema: &Schema{Type: "string"},
+			}
+		}
+		op.Responses["200"] = ok
+		op.Responses["206"] = &Response{
+			Description: "Partial content (inventory not synchronized)."}
+		op.Responses["400"] = &Response{Description: "Bad request."}
+		op.Responses["401"] = &Response{Description: "Unauthorized."}
+		op.Responses["404"] = &Response{Description: "Not found."}
+		op.Responses["500"] = &Response{Description: "Internal error."}
+		d.Paths[route.OpenAPIPath()] = &PathItem{Get: op}
+	}
+
+	return
+}
+
+// Schema builder.
+// Builds schemas for Go types (as encoded by encoding/json).
+// Named structs are added to `Schemas` and referenced.
+type SchemaBuilder struct {
+	// Component schemas.
+	Schemas map[string]*Schema
+	// Type names (key) by type.
+	names map[reflect.Type]string
+	// Package path by short name.
+	packages map[string]string
+}
+
+// Build a schema for the type.
+func (r *SchemaBuilder) Build(t reflect.Type) (schema *Schema) {
+	if r.names == nil {
+		r.names = make(map[reflect.Type]string)
+	}
+	if t == nil {
+		schema = &Schema{}
+		return
+	}
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	defer func() {
+		if nullable && schema.Ref == "" {
+			schema.Nullable = true
+		}
+	}()
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		schema = &Schema{Type: "string", Format: "date-time"}
+		return
+	case reflect.TypeOf(json.RawMessage{}):
+		schema = &Schema{}
+		return
+	}
+	if r.marshaled(t) {
+		if t.Kind() == reflect.Struct && t.NumField() == 1 &&
+			t.Field(0).Type == reflect.TypeOf(time.Time{}) {
+			schema = &Schema{Type: "string", Format: "date-time"}
+		} else {
+			schema = &Schema{}
+		}
+		return
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		schema = &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16:
+		schema = &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint,
+		reflect.Uint32, reflect.Uint64:
+		schema = &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		schema = &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		schema = &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		schema = &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			schema = &Schema{Type: "string", Format: "byte"}
+			break
+		}
+		schema = &Schema{
+			Type:  "array",
+			Items: r.Build(t.Elem()),
+		}
+		nullable = nullable || t.Kind() == reflect.Slice
+	case reflect.Map:
+		schema = &Schema{
+			Type:                 "object",
+			AdditionalProperties: r.Build(t.Elem()),
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema = r.object(t)
+			break
+		}
+		name := r.name(t)
+		if _, found := r.Schemas[name]; !found {
+			r.Schemas[name] = &Schema{}
+			*r.Schemas[name] = *r.object(t)
+		}
+		schema = &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		schema = &Schema{}
+	}
+
+	return
+}
+
+// Build an object schema for the struct.
+// Embedded (anonymous) structs are flattened.
+func (r *SchemaBuilder) object(t reflect.Type) (schema *Schema) {
+	schema = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		inline := strings.Contains(tag, ",inline")
+		if ((field.Anonymous && name == "") || inline) && ft.Kind() == reflect.Struct {
+			embedded := r.object(ft)
+			for k, v := range embedded.Properties {
+				schema.Properties[k] = v
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = r.Build(field.Type)
+	}
+
+	return
+}
+
+// Type implements custom JSON encoding.
+func (r *SchemaBuilder) marshaled(t reflect.Type) bool {
+	jm := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	tm := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	pt := reflect.PtrTo(t)
+	return t.Implements(jm) || pt.Implements(jm) ||
+		t.Implements(tm) || pt.Implements(tm)
+}
+
+// Component name.
+// The last (2) package path elements and the type name.
+// Example: provider/web/vsphere.VM => web.vsphere.VM
+// The full package path is used when the short package
+// name has been claimed by another package.
+func (r *SchemaBuilder) name(t reflect.Type) (name string) {
+	if name, found := r.names[t]; found {
+		return name
+	}
+	if r.packages == nil {
+		r.packages = make(map[string]string)
+	}
+	path := strings.Split(t.PkgPath(), "/")
+	if len(path) > 2 {
+		path = path[len(path)-2:]
+	}
+	short := strings.Join(path, ".")
+	claimed, found := r.packages[short]
+	if !found {
+		r.packages[short] = t.PkgPath()
+		claimed = t.PkgPath()
+	}
+	if claimed == t.PkgPath() {
+		name = short + "." + t.Name()
+	} else {
+		name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+	}
+	r.names[t] = name
+	return
+}
+
+// OpenAPI (route) handler.
+// Serves the OpenAPI document.
+type OpenAPIHandler struct {
+	// Document builder.
+	Document func() *Document
+	// Built document.
+	built struct {
+		once    sync.Once
+		content []byte
+	}
+}
+
+// Add routes.
+func (h *OpenAPIHandler) AddRoutes(r *gin.Engine) {
+	r.GET(OpenAPIRoot, h.Get)
+}
+
+// Get the document.
+func (h *OpenAPIHandler) Get(ctx *gin.Context) {
+	h.built.once.Do(func() {
+		document := h.Document()
+		h.built.content, _ = json.Marshal(document)
+	})
+
+	ctx.Data(http.StatusOK, "application/json", h.built.content)
+}
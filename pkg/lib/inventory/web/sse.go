@@ -0,0 +1,305 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+)
+
+// Server-sent events.
+const (
+	// Media type.
+	EventStream = "text/event-stream"
+	// Header sent by the client to resume.
+	LastEventIDHeader = "Last-Event-ID"
+	// Query parameter used to pass watch options
+	// by clients (EventSource) unable to set headers.
+	WatchParam = "watch"
+)
+
+// Interval between heartbeat (comment) frames
+// used to keep proxies from closing idle streams.
+var Heartbeat = 30 * time.Second
+
+// Started event (stream) data.
+type StartedEvent struct {
+	// Watch ID.
+	ID uint64
+	// Resumed after the Last-Event-ID.
+	// When false, a client resuming the stream must
+	// discard resources it has cached.
+	Resumed bool
+}
+
+// Format the `id` of an event.
+// The journal serial number qualified by the process
+// epoch: <epoch>-<serial>.
+func eventID(id uint64) string {
+	return model.Epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// Parse the `Last-Event-ID` sent by the client.
+// An ID without an epoch is not in the current
+// epoch and the watch is not resumed.
+func parseEventID(s string) (epoch string, id uint64, err error) {
+	serial := s
+	if n := strings.LastIndex(s, "-"); n >= 0 {
+		epoch, serial = s[:n], s[n+1:]
+	}
+	id, err = strconv.ParseUint(serial, 10, 64)
+	if err != nil {
+		err = liberr.Wrap(err, "id", s)
+	}
+	return
+}
+
+// Stream (event) writer.
+// The writer is model event handler. Each event is
+// written to the response as a server-sent event.  The
+// `id` of each event is the journal serial number (qualified
+// by the process epoch) and is sent by the client as the
+// `Last-Event-ID` to resume.
+type StreamWriter struct {
+	mutex sync.Mutex
+	// Watch options.
+	options model.WatchOptions
+	// Response writer.
+	writer gin.ResponseWriter
+	// Resource.
+	builder ResourceBuilder
+	// Logger.
+	log logging.LevelLogger
+	// Watch ID.
+	watchID uint64
+	// Closed when the watch has ended.
+	ended chan struct{}
+	// End once.
+	once sync.Once
+	// Done.
+	done bool
+}
+
+// Watch options.
+func (r *StreamWriter) Options() model.WatchOptions {
+	return r.options
+}
+
+// Watch has started.
+// Called while the stream is held by Watched.watchStream()
+// which sends the `started` event once the watch has
+// been created.
+func (r *StreamWriter) Started(watchID uint64) {
+	r.log.V(3).Info("event: started.")
+	r.watchID = watchID
+}
+
+// Watch has parity.
+func (r *StreamWriter) Parity() {
+	r.log.V(3).Info("event: parity.")
+	r.send(model.Event{
+		Action: model.Parity,
+	})
+}
+
+// A model has been created.
+func (r *StreamWriter) Created(event model.Event) {
+	r.log.V(5).Info(
+		"event received.",
+		"event",
+		event.String())
+	r.send(event)
+}
+
+// A model has been updated.
+func (r *StreamWriter) Updated(event model.Event) {
+	r.log.V(5).Info(
+		"event received.",
+		"event",
+		event.String())
+	r.send(event)
+}
+
+// A model has been deleted.
+func (r *StreamWriter) Deleted(event model.Event) {
+	r.log.V(5).Info(
+		"event received.",
+		"event",
+		event.String())
+	r.send(event)
+}
+
+// An error has occurred delivering an event.
+func (r *StreamWriter) Error(err error) {
+	r.log.V(3).Info(
+		"event: error",
+		"error",
+		err.Error())
+	r.send(model.Event{
+		Action: model.Error,
+	})
+}
+
+// An event watch has ended.
+func (r *StreamWriter) End() {
+	r.log.V(3).Info("event: ended.")
+	r.send(model.Event{
+		Action: model.End,
+	})
+	r.close()
+}
+
+// Write event to the stream.
+func (r *StreamWriter) send(e model.Event) {
+	event := Event{
+		ID:     e.ID,
+		Labels: e.Labels,
+		Action: e.Action,
+	}
+	if e.Model != nil {
+		event.Resource = r.builder(e.Model)
+	}
+	if e.Updated != nil {
+		event.Updated = r.builder(e.Updated)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.write(e.ID, e.Action, event)
+	r.log.V(5).Info(
+		"event sent.",
+		"event",
+		event)
+}
+
+// Write a frame.
+// Events without an ID (snapshot) are sent without
+// the `id` field so the client retains the last ID.
+// The mutex must be held.
+func (r *StreamWriter) write(id uint64, action uint8, data interface{}) {
+	if r.done {
+		return
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		r.log.V(4).Error(err, "stream encode failed.")
+		return
+	}
+	frame := strings.Builder{}
+	if id > 0 {
+		frame.WriteString(fmt.Sprintf("id: %s\n", eventID(id)))
+	}
+	frame.WriteString(fmt.Sprintf("event: %s\n", actionName(action)))
+	frame.WriteString(fmt.Sprintf("data: %s\n\n", content))
+	_, err = r.writer.WriteString(frame.String())
+	if err != nil {
+		r.log.V(4).Error(err, "stream send failed.")
+		return
+	}
+	r.writer.Flush()
+}
+
+// Write a heartbeat (comment) frame.
+func (r *StreamWriter) heartbeat() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.done {
+		return
+	}
+	_, err := r.writer.WriteString(": heartbeat\n\n")
+	if err != nil {
+		r.log.V(4).Error(err, "stream send failed.")
+		return
+	}
+	r.writer.Flush()
+}
+
+// Close the stream.
+// Nothing is written after the stream is closed.
+func (r *StreamWriter) close() {
+	r.once.Do(func() {
+		r.mutex.Lock()
+		r.done = true
+		r.mutex.Unlock()
+		close(r.ended)
+	})
+}
+
+// Stream model events.
+// Blocks until the watch has ended or the
+// client has closed the connection.
+func (r *Watched) watchStream(
+	ctx *gin.Context,
+	db model.DB,
+	m model.Model,
+	rb ResourceBuilder) (err error) {
+	//
+	writer := &StreamWriter{
+		options: r.options,
+		writer:  ctx.Writer,
+		builder: rb,
+		ended:   make(chan struct{}),
+		log: logging.WithName("web|stream|writer").WithValues(
+			"peer",
+			ctx.Request.RemoteAddr),
+	}
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", EventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	// Events are held until the `started`
+	// event has been sent.
+	writer.mutex.Lock()
+	watch, err := db.Watch(m, writer)
+	if err != nil {
+		writer.done = true
+		writer.mutex.Unlock()
+		err = liberr.Wrap(
+			err,
+			"watch failed.",
+			"url",
+			ctx.Request.URL)
+		return
+	}
+	ctx.Status(http.StatusOK)
+	writer.write(
+		0,
+		model.Started,
+		StartedEvent{
+			ID:      writer.watchID,
+			Resumed: watch.Resumed(),
+		})
+	writer.mutex.Unlock()
+
+	log.V(3).Info(
+		"handler: stream created.",
+		"url",
+		ctx.Request.URL,
+		"watch",
+		watch.String())
+
+	ticker := time.NewTicker(Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			writer.heartbeat()
+		case <-writer.ended:
+			writer.log.V(3).Info("stopped.")
+			return
+		case <-ctx.Request.Context().Done():
+			writer.log.V(4).Info("closed by peer.")
+			writer.close()
+			watch.End()
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+)
+
+type TestObject struct {
+	ID   string `sql:"pk"`
+	Name string `sql:""`
+}
+
+func (m *TestObject) Pk() string {
+	return m.ID
+}
+
+// Open an event stream.
+// Returns a function reading the next frame.
+func openStream(
+	g *gomega.WithT,
+	ctx context.Context,
+	url string,
+	lastID string) (next func() map[string]string) {
+	//
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	request.Header.Set("Accept", EventStream)
+	if lastID != "" {
+		request.Header.Set(LastEventIDHeader, lastID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusOK))
+	g.Expect(response.Header.Get("Content-Type")).To(gomega.Equal(EventStream))
+	reader := bufio.NewReader(response.Body)
+	next = func() (frame map[string]string) {
+		frame = map[string]string{}
+		for {
+			line, err := reader.ReadString('\n')
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				if len(frame) > 0 {
+					return
+				}
+				continue
+			}
+			if strings.HasPrefix(line, ":") {
+				continue
+			}
+			field, value, _ := strings.Cut(line, ": ")
+			frame[field] = value
+		}
+	}
+	return
+}
+
+func TestEventStream(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	db := model.New("/tmp/test-event-stream.db", &TestObject{})
+	err := db.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	err = db.Insert(&TestObject{ID: "1", Name: "Elmer"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	router := gin.New()
+	router.GET("/objects", func(ctx *gin.Context) {
+		h := &Watched{}
+		status := h.Prepare(ctx)
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		_ = h.Watch(ctx, db, &TestObject{}, func(m model.Model) interface{} { return m })
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := server.URL + "/objects?" + WatchParam + "=" + WatchSnapshot
+	started := func(frame map[string]string) (event StartedEvent) {
+		g.Expect(frame["event"]).To(gomega.Equal("started"))
+		err := json.Unmarshal([]byte(frame["data"]), &event)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		return
+	}
+
+	// Snapshot.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	next := openStream(g, ctx, url, "")
+	g.Expect(started(next()).Resumed).To(gomega.BeFalse())
+	frame := next()
+	g.Expect(frame["event"]).To(gomega.Equal("created"))
+	g.Expect(frame).ToNot(gomega.HaveKey("id"))
+	g.Expect(next()["event"]).To(gomega.Equal("parity"))
+	err = db.Insert(&TestObject{ID: "2", Name: "Bugs"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	frame = next()
+	g.Expect(frame["event"]).To(gomega.Equal("created"))
+	g.Expect(frame["id"]).To(gomega.HavePrefix(model.Epoch + "-"))
+	lastID := frame["id"]
+	err = db.Insert(&TestObject{ID: "3", Name: "Daffy"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(next()["event"]).To(gomega.Equal("created"))
+	cancel()
+
+	// Resumed after the last event ID.
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	next = openStream(g, ctx, url, lastID)
+	g.Expect(started(next()).Resumed).To(gomega.BeTrue())
+	g.Expect(next()["event"]).To(gomega.Equal("parity"))
+	frame = next()
+	g.Expect(frame["event"]).To(gomega.Equal("created"))
+	g.Expect(frame["data"]).To(gomega.ContainSubstring("Daffy"))
+	cancel()
+
+	// Event ID issued by a previous process.
+	_, serial, _ := strings.Cut(lastID, "-")
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	next = openStream(g, ctx, url, "previous-"+serial)
+	g.Expect(started(next()).Resumed).To(gomega.BeFalse())
+	for i := 0; i < 3; i++ {
+		g.Expect(next()["event"]).To(gomega.Equal("created"))
+	}
+	g.Expect(next()["event"]).To(gomega.Equal("parity"))
+	cancel()
+
+	// Malformed event ID.
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	request.Header.Set("Accept", EventStream)
+	request.Header.Set(LastEventIDHeader, "previous-x")
+	response, err := http.DefaultClient.Do(request)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(response.StatusCode).To(gomega.Equal(http.StatusBadRequest))
+}
@@ -44,7 +44,7 @@ func (w *WebServer) Start(middleware ...gin.HandlerFunc) {
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"GET"},
-		AllowHeaders:     []string{"Authorization", "Origin"},
+		AllowHeaders:     []string{"Authorization", "Origin", LastEventIDHeader},
 		AllowOriginFunc:  w.allow,
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
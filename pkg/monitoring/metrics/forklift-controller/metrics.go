@@ -114,4 +114,80 @@ var (
 			"target",
 		},
 	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	planTransferCompletedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_plan_transfer_completed_bytes",
+		Help: "Bytes of disk data transferred in the running plan migrations",
+	},
+		[]string{"plan", "migration"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	planTransferTotalGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_plan_transfer_total_bytes",
+		Help: "Bytes of disk data to transfer in the running plan migrations",
+	},
+		[]string{"plan", "migration"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	planTransferRateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_plan_transfer_rate_bytes_per_second",
+		Help: "Disk transfer rate of the running plan migrations in bytes per second",
+	},
+		[]string{"plan", "migration"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	planTransferETAGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_plan_transfer_eta_seconds",
+		Help: "Estimated seconds until the disk transfers of the running plan migrations complete",
+	},
+		[]string{"plan", "migration"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	// 'vm' - [Id]
+	vmTransferCompletedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_vm_transfer_completed_bytes",
+		Help: "Bytes of disk data transferred in the running VM migrations",
+	},
+		[]string{"plan", "migration", "vm"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	// 'vm' - [Id]
+	vmTransferTotalGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_vm_transfer_total_bytes",
+		Help: "Bytes of disk data to transfer in the running VM migrations",
+	},
+		[]string{"plan", "migration", "vm"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	// 'vm' - [Id]
+	vmTransferRateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_vm_transfer_rate_bytes_per_second",
+		Help: "Disk transfer rate of the running VM migrations in bytes per second",
+	},
+		[]string{"plan", "migration", "vm"},
+	)
+
+	// 'plan' - [Id]
+	// 'migration' - [Id]
+	// 'vm' - [Id]
+	vmTransferETAGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_vm_transfer_eta_seconds",
+		Help: "Estimated seconds until the disk transfers of the running VM migrations complete",
+	},
+		[]string{"plan", "migration", "vm"},
+	)
 )
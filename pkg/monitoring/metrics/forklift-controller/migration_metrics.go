@@ -3,9 +3,11 @@ package forklift_controller
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -38,6 +40,7 @@ func RecordMigrationMetrics(c client.Client) {
 				if err != nil {
 					continue
 				}
+				recordTransferMetrics(m, string(plan.UID), time.Now())
 
 				sourceProvider := api.Provider{}
 				err = c.Get(context.TODO(), client.ObjectKey{Namespace: plan.Spec.Provider.Source.Namespace, Name: plan.Spec.Provider.Source.Name}, &sourceProvider)
@@ -103,6 +106,66 @@ func processMigration(migration api.Migration, provider, mode, target, planUID s
 	}
 }
 
+// Record the disk transfer progress of a running migration.
+// The metrics of completed migrations are removed.
+func recordTransferMetrics(migration api.Migration, planUID string, now time.Time) {
+	if migration.Status.MarkedCompleted() || migration.Status.Transfer == nil {
+		deleteTransferMetrics(string(migration.UID))
+		return
+	}
+	labels := prometheus.Labels{"plan": planUID, "migration": string(migration.UID)}
+	setTransferMetrics(
+		migration.Status.Transfer,
+		now,
+		planTransferCompletedGauge.With(labels),
+		planTransferTotalGauge.With(labels),
+		planTransferRateGauge.With(labels),
+		planTransferETAGauge.With(labels))
+	for _, vm := range migration.Status.VMs {
+		if vm.Transfer == nil {
+			continue
+		}
+		labels := prometheus.Labels{"plan": planUID, "migration": string(migration.UID), "vm": vm.ID}
+		setTransferMetrics(
+			vm.Transfer,
+			now,
+			vmTransferCompletedGauge.With(labels),
+			vmTransferTotalGauge.With(labels),
+			vmTransferRateGauge.With(labels),
+			vmTransferETAGauge.With(labels))
+	}
+}
+
+// Set the transfer gauges. The ETA is in seconds from now,
+// zero when the completion cannot be estimated.
+func setTransferMetrics(transfer *plan.TransferProgress, now time.Time, completed, total, rate, eta prometheus.Gauge) {
+	completed.Set(float64(transfer.BytesCompleted))
+	total.Set(float64(transfer.BytesTotal))
+	rate.Set(float64(transfer.Rate))
+	if transfer.ETA != nil {
+		eta.Set(math.Max(transfer.ETA.Sub(now).Seconds(), 0))
+	} else {
+		eta.Set(0)
+	}
+}
+
+// Delete the transfer metrics of a migration.
+func deleteTransferMetrics(migrationUID string) {
+	labels := prometheus.Labels{"migration": migrationUID}
+	for _, gauge := range []*prometheus.GaugeVec{
+		planTransferCompletedGauge,
+		planTransferTotalGauge,
+		planTransferRateGauge,
+		planTransferETAGauge,
+		vmTransferCompletedGauge,
+		vmTransferTotalGauge,
+		vmTransferRateGauge,
+		vmTransferETAGauge,
+	} {
+		gauge.DeletePartialMatch(labels)
+	}
+}
+
 func updateMetricsCount(status, provider, mode, target, plan string) {
 	migrationStatusCounter.With(prometheus.Labels{"status": status, "provider": provider, "mode": mode, "target": target}).Inc()
 	migrationPlanCorrelationStatusCounter.With(prometheus.Labels{"status": status, "provider": provider, "mode": mode, "target": target, "plan": plan}).Inc()
@@ -5,6 +5,7 @@ const (
 	FeatureOvirtWarmMigration        = "FEATURE_OVIRT_WARM_MIGRATION"
 	FeatureRetainPrecopyImporterPods = "FEATURE_RETAIN_PRECOPY_IMPORTER_PODS"
 	FeatureVsphereIncrementalBackup  = "FEATURE_VSPHERE_INCREMENTAL_BACKUP"
+	FeatureVsphereStorageOffload     = "FEATURE_VSPHERE_STORAGE_OFFLOAD"
 )
 
 // Feature gates.
@@ -16,6 +17,9 @@ type Features struct {
 	RetainPrecopyImporterPods bool
 	// Whether to use changeID-based incremental backup workflow (with a version of CDI that supports it)
 	VsphereIncrementalBackup bool
+	// Whether the disks of vSphere VMs may be cloned by the storage array
+	// (`storageOffload` provider setting) using a registered driver.
+	VsphereStorageOffload bool
 }
 
 // Load settings.
@@ -23,5 +27,6 @@ func (r *Features) Load() (err error) {
 	r.OvirtWarmMigration = getEnvBool(FeatureOvirtWarmMigration, false)
 	r.RetainPrecopyImporterPods = getEnvBool(FeatureRetainPrecopyImporterPods, false)
 	r.VsphereIncrementalBackup = getEnvBool(FeatureVsphereIncrementalBackup, false)
+	r.VsphereStorageOffload = getEnvBool(FeatureVsphereStorageOffload, false)
 	return
 }
@@ -15,6 +15,7 @@ const (
 const (
 	AllowedOrigins = "CORS_ALLOWED_ORIGINS"
 	WorkingDir     = "WORKING_DIR"
+	Database       = "INVENTORY_DATABASE"
 	AuthRequired   = "AUTH_REQUIRED"
 	Host           = "API_HOST"
 	Port           = "API_PORT"
@@ -35,6 +36,12 @@ type Inventory struct {
 	CORS CORS
 	// DB working directory.
 	WorkingDir string
+	// PostgreSQL connection (URL).
+	// When set, inventory is stored in the database (schema
+	// per provider) rather than in the working directory.
+	// The replicas elect a leader collecting the inventory;
+	// the others serve the inventory collected by the leader.
+	Database string
 	// Authorization required.
 	AuthRequired bool
 	// Host.
@@ -72,6 +79,10 @@ func (r *Inventory) Load() error {
 	} else {
 		r.WorkingDir = os.TempDir()
 	}
+	// Database
+	if s, found := os.LookupEnv(Database); found {
+		r.Database = s
+	}
 	// Auth
 	r.AuthRequired = getEnvBool(AuthRequired, true)
 	// Host
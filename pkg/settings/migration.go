@@ -29,6 +29,7 @@ const (
 	VddkJobActiveDeadline     = "VDDK_JOB_ACTIVE_DEADLINE"
 	VirtV2vExtraArgs          = "VIRT_V2V_EXTRA_ARGS"
 	VirtV2vExtraConfConfigMap = "VIRT_V2V_EXTRA_CONF_CONFIG_MAP"
+	OvirtPopulatorImage       = "OVIRT_POPULATOR_IMAGE"
 )
 
 // Migration settings
@@ -68,6 +69,9 @@ type Migration struct {
 	VirtV2vExtraArgs string
 	// Additional configuration for virt-v2v
 	VirtV2vExtraConfConfigMap string
+	// oVirt populator image, computes the ImageIO checksum
+	// of the disks copied by CDI.
+	OvirtPopulatorImage string
 }
 
 // Load settings.
@@ -144,5 +148,8 @@ func (r *Migration) Load() (err error) {
 	if val, found := os.LookupEnv(VirtV2vExtraConfConfigMap); found {
 		r.VirtV2vExtraConfConfigMap = val
 	}
+	if val, found := os.LookupEnv(OvirtPopulatorImage); found {
+		r.OvirtPopulatorImage = val
+	}
 	return
 }
@@ -10,6 +10,8 @@ const (
 	PolicyAgentCA             = "POLICY_AGENT_CA"
 	PolicyAgentWorkerLimit    = "POLICY_AGENT_WORKER_LIMIT"
 	PolicyAgentSearchInterval = "POLICY_AGENT_SEARCH_INTERVAL"
+	PolicyAgentEmbedded       = "POLICY_AGENT_EMBEDDED"
+	PolicyAgentPolicies       = "POLICY_AGENT_POLICIES"
 )
 
 // Policy agent settings.
@@ -21,6 +23,12 @@ type PolicyAgent struct {
 		// CA path
 		CA string
 	}
+	// Evaluate policies in-process rather
+	// than using the agent (service).
+	Embedded bool
+	// Policies directory (embedded).
+	// Defaults to the policies built into the controller.
+	Policies string
 	// Search interval (seconds).
 	SearchInterval int
 	// Limits.
@@ -41,6 +49,10 @@ func (r *PolicyAgent) Load() (err error) {
 	} else {
 		r.TLS.CA = ServiceCAFile
 	}
+	r.Embedded = getEnvBool(PolicyAgentEmbedded, false)
+	if s, found := os.LookupEnv(PolicyAgentPolicies); found {
+		r.Policies = s
+	}
 	r.Limit.Worker, err = getPositiveEnvLimit(PolicyAgentWorkerLimit, 10)
 	if err != nil {
 		return err
@@ -55,5 +67,5 @@ func (r *PolicyAgent) Load() (err error) {
 
 // Enabled.
 func (r *PolicyAgent) Enabled() bool {
-	return r.URL != ""
+	return r.URL != "" || r.Embedded
 }
@@ -0,0 +1,10 @@
+// Package validation provides the VM validation policies.
+package validation
+
+import "embed"
+
+// Rego policy bundles.
+// Rooted at: policies/io/konveyor/forklift/<provider>.
+//
+//go:embed policies
+var Policies embed.FS
@@ -0,0 +1,21 @@
+package io.konveyor.forklift.openstack
+
+test_secure_boot_with_smm {
+	mock_vm := {
+		"name": "test",
+		"flavor": {"extraSpecs": {"os:secure_boot": "required"}},
+		"destination": {"featureGates": ["SMM"]},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_secure_boot_without_smm {
+	mock_vm := {
+		"name": "test",
+		"flavor": {"extraSpecs": {"os:secure_boot": "required"}},
+		"destination": {"featureGates": []},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 2
+}
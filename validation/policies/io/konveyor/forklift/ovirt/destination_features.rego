@@ -0,0 +1,16 @@
+package io.konveyor.forklift.ovirt
+
+destination_smm {
+    input.destination.featureGates[_] == "SMM"
+}
+
+concerns[flag] {
+    input.destination.featureGates
+    secure_boot_enabled
+    not destination_smm
+    flag := {
+        "category": "Critical",
+        "label": "UEFI secure boot not supported by the destination cluster",
+        "assessment": "UEFI secure boot requires SMM but the SMM feature gate is not enabled in the destination cluster. The VM could not be started."
+    }
+}
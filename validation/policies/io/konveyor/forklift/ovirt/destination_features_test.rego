@@ -0,0 +1,25 @@
+package io.konveyor.forklift.ovirt
+
+test_secure_boot_with_smm {
+    mock_vm := {
+        "name": "test",
+        "bios": "q35_secure_boot",
+        "destination": {
+            "featureGates": ["SMM"]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_secure_boot_without_smm {
+    mock_vm := {
+        "name": "test",
+        "bios": "q35_secure_boot",
+        "destination": {
+            "featureGates": []
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
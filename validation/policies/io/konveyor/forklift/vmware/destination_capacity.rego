@@ -0,0 +1,31 @@
+package io.konveyor.forklift.vmware
+
+max_node_cpu := max([n.cpu | n := input.destination.nodes[_]])
+
+max_node_memory := max([n.memory | n := input.destination.nodes[_]])
+
+exceeds_node_cpu {
+    input.cpuCount > max_node_cpu
+}
+
+exceeds_node_memory {
+    input.memoryMB * 1024 * 1024 > max_node_memory
+}
+
+concerns[flag] {
+    exceeds_node_cpu
+    flag := {
+        "category": "Critical",
+        "label": "Insufficient node CPU capacity",
+        "assessment": "The VM requires more vCPUs than are allocatable on any node in the destination cluster. The VM could not be scheduled."
+    }
+}
+
+concerns[flag] {
+    exceeds_node_memory
+    flag := {
+        "category": "Critical",
+        "label": "Insufficient node memory capacity",
+        "assessment": "The VM requires more memory than is allocatable on any node in the destination cluster. The VM could not be scheduled."
+    }
+}
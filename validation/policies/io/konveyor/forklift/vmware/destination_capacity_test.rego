@@ -0,0 +1,38 @@
+package io.konveyor.forklift.vmware
+
+test_without_destination {
+    mock_vm := { "name": "test", "cpuCount": 64, "memoryMB": 1048576 }
+    results = concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_within_node_capacity {
+    mock_vm := {
+        "name": "test",
+        "cpuCount": 4,
+        "memoryMB": 4096,
+        "destination": {
+            "nodes": [
+                { "name": "n1", "cpu": 2, "memory": 2147483648 },
+                { "name": "n2", "cpu": 8, "memory": 17179869184 }
+            ]
+        }
+    }
+    results = concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_exceeds_node_capacity {
+    mock_vm := {
+        "name": "test",
+        "cpuCount": 16,
+        "memoryMB": 32768,
+        "destination": {
+            "nodes": [
+                { "name": "n1", "cpu": 8, "memory": 17179869184 }
+            ]
+        }
+    }
+    results = concerns with input as mock_vm
+    count(results) == 2
+}
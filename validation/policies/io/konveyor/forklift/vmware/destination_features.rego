@@ -0,0 +1,16 @@
+package io.konveyor.forklift.vmware
+
+destination_persistent_state {
+    input.destination.featureGates[_] == "VMPersistentState"
+}
+
+concerns[flag] {
+    input.destination.featureGates
+    has_tpm_enabled
+    not destination_persistent_state
+    flag := {
+        "category": "Warning",
+        "label": "Persistent TPM not enabled in the destination cluster",
+        "assessment": "The VM is configured with a TPM device but the VMPersistentState feature gate is not enabled in the destination cluster. The TPM state will not persist across restarts of the VM."
+    }
+}
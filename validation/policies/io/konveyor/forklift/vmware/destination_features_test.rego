@@ -0,0 +1,25 @@
+package io.konveyor.forklift.vmware
+
+test_tpm_with_persistent_state {
+    mock_vm := {
+        "name": "test",
+        "tpmEnabled": true,
+        "destination": {
+            "featureGates": ["VMPersistentState"]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_tpm_without_persistent_state {
+    mock_vm := {
+        "name": "test",
+        "tpmEnabled": true,
+        "destination": {
+            "featureGates": []
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
@@ -0,0 +1,17 @@
+package io.konveyor.forklift.vmware
+
+destination_sriov_network {
+    some i
+    input.destination.networks[i].type == "sriov"
+}
+
+concerns[flag] {
+    input.destination.networks
+    has_sriov_device
+    not destination_sriov_network
+    flag := {
+        "category": "Critical",
+        "label": "No SR-IOV network in the destination cluster",
+        "assessment": "The VM is configured with an SR-IOV adapter but no SR-IOV network (attachment definition) is present in the destination cluster."
+    }
+}
@@ -0,0 +1,34 @@
+package io.konveyor.forklift.vmware
+
+test_sriov_nic_with_sriov_network {
+    mock_vm := {
+        "name": "test",
+        "devices": [
+            { "kind": "VirtualSriovEthernetCard" }
+        ],
+        "destination": {
+            "networks": [
+                { "namespace": "ns", "name": "bridge", "type": "bridge" },
+                { "namespace": "ns", "name": "sriov", "type": "sriov" }
+            ]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_sriov_nic_without_sriov_network {
+    mock_vm := {
+        "name": "test",
+        "devices": [
+            { "kind": "VirtualSriovEthernetCard" }
+        ],
+        "destination": {
+            "networks": [
+                { "namespace": "ns", "name": "bridge", "type": "bridge" }
+            ]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
@@ -0,0 +1,17 @@
+package io.konveyor.forklift.vmware
+
+destination_shared_storage {
+    some i, j
+    input.destination.storageClasses[i].accessModes[j] == "ReadWriteMany"
+}
+
+concerns[flag] {
+    input.destination.storageClasses
+    has_shareable_disk
+    not destination_shared_storage
+    flag := {
+        "category": "Critical",
+        "label": "No shared storage in the destination cluster",
+        "assessment": "The VM is configured with a shareable disk but no storage class in the destination cluster supports the ReadWriteMany access mode. The disk could not be shared."
+    }
+}
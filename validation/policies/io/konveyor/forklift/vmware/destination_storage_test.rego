@@ -0,0 +1,45 @@
+package io.konveyor.forklift.vmware
+
+test_shared_disk_without_destination {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "shared": true }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_shared_disk_with_shared_storage {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "shared": true }
+        ],
+        "destination": {
+            "storageClasses": [
+                { "name": "rwo", "accessModes": ["ReadWriteOnce"] },
+                { "name": "rwx", "accessModes": ["ReadWriteOnce", "ReadWriteMany"] }
+            ]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_shared_disk_without_shared_storage {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "shared": true }
+        ],
+        "destination": {
+            "storageClasses": [
+                { "name": "rwo", "accessModes": ["ReadWriteOnce"] }
+            ]
+        }
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenName(t *testing.T) {
@@ -56,3 +57,37 @@ func TestStaticIPs(t *testing.T) {
 		}
 	}
 }
+
+func TestRateSchedule(t *testing.T) {
+	schedule, err := parseRateSchedule("1000", "Mon,Tue/09:00-17:00=100 22:00-06:00=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schedule.limited() {
+		t.Fatal("expected the schedule to be limited")
+	}
+	cases := []struct {
+		now      time.Time
+		expected string
+	}{
+		// Monday
+		{time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC), "800"},
+		{time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC), "8000"},
+		{time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC), "1125899906842624"},
+		// Wednesday
+		{time.Date(2024, 7, 3, 10, 0, 0, 0, time.UTC), "8000"},
+	}
+	for _, c := range cases {
+		if bits := schedule.bits(c.now); bits != c.expected {
+			t.Errorf("bits(%s) = %s; want %s", c.now, bits, c.expected)
+		}
+	}
+	if schedule, _ = parseRateSchedule("", ""); schedule.limited() {
+		t.Error("expected the schedule to not be limited")
+	}
+	for _, windows := range []string{"09:00-17:00", "Foo/09:00-17:00=1", "9-17=1"} {
+		if _, err = parseRateSchedule("", windows); err == nil {
+			t.Errorf("expected %q to be invalid", windows)
+		}
+	}
+}
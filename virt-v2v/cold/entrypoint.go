@@ -231,6 +231,19 @@ func LinkDisks(diskKind string, num int) (err error) {
 
 func executeVirtV2v(args []string) error {
 	v2vCmd := exec.Command("virt-v2v", args...)
+	schedule, err := parseRateSchedule(os.Getenv("V2V_rateLimit"), os.Getenv("V2V_rateWindows"))
+	if err != nil {
+		fmt.Printf("Error parsing the rate limit: %v\n", err)
+		return err
+	}
+	if schedule.limited() {
+		path, err := limitRate(schedule)
+		if err != nil {
+			fmt.Printf("Error limiting the transfer rate: %v\n", err)
+			return err
+		}
+		v2vCmd.Env = append(os.Environ(), "PATH="+path)
+	}
 	monitorCmd := exec.Command("/usr/local/bin/virt-v2v-monitor")
 	monitorCmd.Stdout = os.Stdout
 	monitorCmd.Stderr = os.Stderr
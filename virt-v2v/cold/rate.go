@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// virt-v2v reads the disks through nbdkit. The transfer rate is
+// limited by a wrapper, found first in the PATH, adding the nbdkit
+// rate filter. The rate is read from the rate file which is updated
+// when a time window of the schedule starts or ends.
+const (
+	// Directory of the nbdkit wrapper and the rate file.
+	RATEDIR = "/var/tmp/rate"
+	// Rate written to the rate file when not limited,
+	// nbdkit has no value for an unlimited rate (bits per second).
+	unlimitedBits = 1 << 50
+)
+
+// Wrapper adding the rate filter to the nbdkit instances serving
+// disks. The instances started to query nbdkit are left alone.
+const nbdkitWrapper = `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+	--dump-*|--version|--help) exec %[1]s "$@" ;;
+	esac
+done
+exec %[1]s --filter=rate "$@" rate=%[2]s rate-file=%[3]s
+`
+
+// Days of the week.
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// Rate during a time window (UTC).
+type rateWindow struct {
+	// Days the window starts, every day when empty.
+	days []time.Weekday
+	// Start and end, minutes after midnight.
+	start, end int
+	// Bytes per second. Zero is not limited.
+	rate int64
+}
+
+// Schedule of the transfer rate.
+type rateSchedule struct {
+	// Bytes per second outside of the windows. Zero is not limited.
+	rate    int64
+	windows []rateWindow
+}
+
+// Parse the schedule set by the controller.
+// V2V_rateLimit: bytes per second.
+// V2V_rateWindows: space separated [<day>,...]/<HH:MM>-<HH:MM>=<rate>.
+func parseRateSchedule(rate, windows string) (schedule *rateSchedule, err error) {
+	schedule = &rateSchedule{}
+	if rate != "" {
+		schedule.rate, err = strconv.ParseInt(rate, 10, 64)
+		if err != nil || schedule.rate < 0 {
+			err = fmt.Errorf("invalid rate %q", rate)
+			return
+		}
+	}
+	for _, value := range strings.Fields(windows) {
+		window := rateWindow{}
+		spec, rateValue, found := strings.Cut(value, "=")
+		if !found {
+			err = fmt.Errorf("window %q has no rate", value)
+			return
+		}
+		window.rate, err = strconv.ParseInt(rateValue, 10, 64)
+		if err != nil || window.rate < 0 {
+			err = fmt.Errorf("window %q has an invalid rate", value)
+			return
+		}
+		days, times, found := strings.Cut(spec, "/")
+		if !found {
+			times = days
+			days = ""
+		}
+		if days != "" {
+			for _, name := range strings.Split(days, ",") {
+				day, known := weekdays[name]
+				if !known {
+					err = fmt.Errorf("window %q has an invalid day %q", value, name)
+					return
+				}
+				window.days = append(window.days, day)
+			}
+		}
+		start, end, _ := strings.Cut(times, "-")
+		if window.start, err = parseMinutes(start); err != nil {
+			return
+		}
+		if window.end, err = parseMinutes(end); err != nil {
+			return
+		}
+		schedule.windows = append(schedule.windows, window)
+	}
+	return
+}
+
+// Parse HH:MM into minutes after midnight.
+func parseMinutes(value string) (minutes int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		err = fmt.Errorf("invalid time %q", value)
+		return
+	}
+	minutes = t.Hour()*60 + t.Minute()
+	return
+}
+
+// The window contains the time.
+func (r *rateWindow) contains(t time.Time) bool {
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+	startsOn := func(day time.Weekday) bool {
+		if len(r.days) == 0 {
+			return true
+		}
+		for _, d := range r.days {
+			if d == day {
+				return true
+			}
+		}
+		return false
+	}
+	if r.end > r.start {
+		return startsOn(t.Weekday()) && minutes >= r.start && minutes < r.end
+	}
+	// Spans midnight.
+	if minutes >= r.start {
+		return startsOn(t.Weekday())
+	}
+	if minutes < r.end {
+		return startsOn((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// Any rate is limited.
+func (r *rateSchedule) limited() bool {
+	if r.rate > 0 {
+		return true
+	}
+	for _, window := range r.windows {
+		if window.rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Rate at the time in bits per second.
+func (r *rateSchedule) bits(t time.Time) string {
+	bytes := r.rate
+	for i := range r.windows {
+		if r.windows[i].contains(t) {
+			bytes = r.windows[i].rate
+			break
+		}
+	}
+	if bytes <= 0 {
+		return strconv.FormatInt(unlimitedBits, 10)
+	}
+	return strconv.FormatInt(bytes*8, 10)
+}
+
+// Install the nbdkit wrapper and maintain the rate file.
+// Returns the PATH virt-v2v must be run with.
+func limitRate(schedule *rateSchedule) (path string, err error) {
+	nbdkit, err := exec.LookPath("nbdkit")
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(RATEDIR, os.ModePerm); err != nil {
+		return
+	}
+	rateFile := filepath.Join(RATEDIR, "rate")
+	current := schedule.bits(time.Now())
+	if err = os.WriteFile(rateFile, []byte(current+"\n"), 0600); err != nil {
+		return
+	}
+	wrapper := fmt.Sprintf(nbdkitWrapper, nbdkit, current, rateFile)
+	if err = os.WriteFile(filepath.Join(RATEDIR, "nbdkit"), []byte(wrapper), 0700); err != nil {
+		return
+	}
+	go func() {
+		for now := range time.Tick(time.Minute) {
+			bits := schedule.bits(now)
+			if bits == current {
+				continue
+			}
+			if wErr := os.WriteFile(rateFile, []byte(bits+"\n"), 0600); wErr != nil {
+				fmt.Println("Error updating the rate file ", wErr)
+				continue
+			}
+			fmt.Println("Transfer rate (bits/s):", bits)
+			current = bits
+		}
+	}()
+	path = RATEDIR + string(os.PathListSeparator) + os.Getenv("PATH")
+	return
+}